@@ -1,21 +1,169 @@
 package main
 
 import (
+	"context"
+	"expvar"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vaxxnsh/metaverse/api/internal/broker"
 	"github.com/vaxxnsh/metaverse/api/internal/config"
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/handlers"
+	"github.com/vaxxnsh/metaverse/api/internal/mailer"
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/realtime"
 	"github.com/vaxxnsh/metaverse/api/internal/repository"
+	"github.com/vaxxnsh/metaverse/api/internal/router"
 	"github.com/vaxxnsh/metaverse/api/internal/service"
 )
 
+// drainTimeout bounds how long a stopping server waits for its clients to
+// be redirected.
+const drainTimeout = 10 * time.Second
+
 func main() {
 	cfg := config.Load()
 
-	db, queries, _ := db.NewDB(cfg.DBURL)
+	pool, err := pgxpool.New(context.Background(), cfg.DBURL)
+	if err != nil {
+		log.Fatal("connect to database:", err)
+	}
+	defer pool.Close()
+	queries := db.New(pool)
 
 	userRepo := repository.NewUserRepository(queries)
-	userService := service.NewUserService()
+	userService := service.NewUserService(userRepo)
 	userHandler := handlers.NewUserHandler(userService)
 
-	router := router.SetupRouter(userHandler)
+	presenceRepo := repository.NewPresenceRepository(queries)
+
+	spaceRepo := repository.NewSpaceRepository(pool, queries)
+	blockRepo := repository.NewBlockRepository(queries)
+	zoneRepo := repository.NewZoneRepository(queries)
+	portalRepo := repository.NewPortalRepository(queries)
+
+	realtimeServer := realtime.NewServer(cfg.JWTSecret, userRepo, blockRepo, zoneRepo, portalRepo, spaceRepo, presenceRepo)
+	realtimeServer.SetTickRate(cfg.TickRate)
+	realtimeServer.SetAutoKick(cfg.AutoKick)
+	realtimeServer.SetResumeGrace(cfg.ResumeGrace)
+	realtimeServer.SetSendQueue(cfg.SendQueue, cfg.SlowClientTimeout)
+	realtimeServer.SetPing(cfg.PingInterval, cfg.PingTimeout)
+	realtimeServer.SetAFKTimeout(cfg.AFKTimeout)
+
+	var roomBroker realtime.Broker = broker.NewMemory()
+	if cfg.Broker == "postgres" {
+		roomBroker = broker.NewPostgres(context.Background(), pool)
+	}
+	realtimeServer.SetBroker(roomBroker)
+	go realtimeServer.RunPresence(context.Background())
+
+	if cfg.WSPublicURL != "" {
+		nodeRepo := repository.NewNodeRepository(queries)
+		realtimeServer.SetNodes(nodeRepo, cfg.WSPublicURL)
+		go realtimeServer.RunNode(context.Background())
+	}
+
+	spaceService := service.NewSpaceService(spaceRepo, realtimeServer)
+	spaceHandler := handlers.NewSpaceHandler(spaceService)
+	realtimeServer.SetSpaces(spaceService)
+
+	moderationService := service.NewModerationService(spaceRepo, realtimeServer)
+	moderationHandler := handlers.NewModerationHandler(moderationService)
+	realtimeServer.SetModeration(moderationService)
+
+	zoneService := service.NewZoneService(zoneRepo, spaceService, realtimeServer)
+	zoneHandler := handlers.NewZoneHandler(zoneService)
+
+	portalService := service.NewPortalService(portalRepo, spaceService, realtimeServer)
+	portalHandler := handlers.NewPortalHandler(portalService)
+
+	pathService := service.NewPathService(spaceService, spaceRepo, zoneRepo)
+	pathHandler := handlers.NewPathHandler(pathService)
+
+	blockService := service.NewBlockService(blockRepo, realtimeServer)
+	blockHandler := handlers.NewBlockHandler(blockService)
+
+	friendRepo := repository.NewFriendRepository(queries)
+	friendService := service.NewFriendService(friendRepo, blockRepo, realtimeServer)
+	friendHandler := handlers.NewFriendHandler(friendService)
+
+	preferencesRepo := repository.NewPreferencesRepository(queries)
+	preferencesService := service.NewPreferencesService(preferencesRepo)
+	preferencesHandler := handlers.NewPreferencesHandler(preferencesService)
+
+	elementRepo := repository.NewElementRepository(queries)
+	elementService := service.NewElementService(elementRepo)
+	elementHandler := handlers.NewElementHandler(elementService)
+
+	spaceBundleService := service.NewSpaceBundleService(spaceService, spaceRepo, elementRepo, zoneRepo, portalRepo)
+	spaceBundleHandler := handlers.NewSpaceBundleHandler(spaceBundleService)
+
+	purger := service.NewPurger(cfg.TrashRetention, cfg.TrashPurgeInterval, spaceRepo, elementRepo, userRepo)
+	go purger.Run(context.Background())
+
+	invitationRepo := repository.NewInvitationRepository(pool, queries)
+	invitationService := service.NewInvitationService(
+		invitationRepo,
+		mailer.New(cfg.SMTPAddr, cfg.MailFrom),
+		cfg.InviteURL,
+		cfg.InviteTTL,
+	)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+
+	importRepo := repository.NewImportRepository(queries)
+	importService := service.NewImportService(importRepo, invitationService, invitationRepo)
+	importHandler := handlers.NewImportHandler(importService)
+
+	if err := importService.ResumeUserImports(context.Background()); err != nil {
+		log.Println("resume user imports:", err)
+	}
+
+	apiRouter := router.SetupRouter(
+		cfg.JWTSecret,
+		userRepo,
+		userHandler,
+		friendHandler,
+		blockHandler,
+		preferencesHandler,
+		spaceHandler,
+		elementHandler,
+		importHandler,
+		invitationHandler,
+		moderationHandler,
+		zoneHandler,
+		portalHandler,
+		pathHandler,
+		spaceBundleHandler,
+	)
+
+	// Connection counts and round trips are served as JSON at /debug/vars
+	// next to the WebSocket endpoint, to admins only.
+	expvar.Publish("realtime", expvar.Func(func() any { return realtimeServer.Stats() }))
+	wsMux := http.NewServeMux()
+	wsMux.Handle("/debug/vars", middleware.Auth(cfg.JWTSecret, userRepo)(middleware.RequireAdmin(expvar.Handler())))
+	wsMux.Handle("/", realtimeServer)
+	go http.ListenAndServe(":"+cfg.WSPort, wsMux)
+
+	// On deploy, the spaces served here move to the other nodes before the
+	// process stops.
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, syscall.SIGTERM, os.Interrupt)
+		<-stop
+
+		ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		if err := realtimeServer.Drain(ctx); err != nil {
+			log.Println("drain:", err)
+		}
+		cancel()
+		os.Exit(0)
+	}()
 
-	router.Run(cfg.Port)
+	log.Fatal(http.ListenAndServe(":"+cfg.Port, apiRouter))
 }
@@ -0,0 +1,104 @@
+// Package broker fans messages out to the subscribers of a topic, either
+// within one process or across every process sharing a database.
+package broker
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriptionBuffer is how many messages a subscriber may fall behind
+// before delivery waits for it.
+const subscriptionBuffer = 256
+
+// Memory is a broker for a single process.
+type Memory struct {
+	topics
+}
+
+func NewMemory() *Memory {
+	return &Memory{topics: newTopics()}
+}
+
+func (b *Memory) Publish(ctx context.Context, topic string, msg []byte) error {
+	b.deliver(topic, msg)
+	return nil
+}
+
+// topics holds the subscriptions of a broker. Every subscription has its
+// handler called with one message at a time, in the order they were
+// delivered.
+type topics struct {
+	mu   sync.Mutex
+	subs map[string][]*subscription
+}
+
+type subscription struct {
+	handler func(msg []byte)
+	queue   chan []byte
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newTopics() topics {
+	return topics{subs: make(map[string][]*subscription)}
+}
+
+// Subscribe calls handler with every message published to topic until the
+// returned function is called.
+func (t *topics) Subscribe(topic string, handler func(msg []byte)) func() {
+	s := &subscription{
+		handler: handler,
+		queue:   make(chan []byte, subscriptionBuffer),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+
+	t.mu.Lock()
+	t.subs[topic] = append(t.subs[topic], s)
+	t.mu.Unlock()
+
+	return func() {
+		// Closing done first releases a delivery waiting on s.
+		s.once.Do(func() { close(s.done) })
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		subs := t.subs[topic]
+		for i := range subs {
+			if subs[i] == s {
+				subs = append(subs[:i:i], subs[i+1:]...)
+				break
+			}
+		}
+		if len(subs) == 0 {
+			delete(t.subs, topic)
+		} else {
+			t.subs[topic] = subs
+		}
+	}
+}
+
+func (t *topics) deliver(topic string, msg []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, s := range t.subs[topic] {
+		select {
+		case s.queue <- msg:
+		case <-s.done:
+		}
+	}
+}
+
+func (s *subscription) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case msg := <-s.queue:
+			s.handler(msg)
+		}
+	}
+}
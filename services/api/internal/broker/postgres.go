@@ -0,0 +1,88 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// channel is the one Postgres channel every topic is sent on. A
+// notification is its topic and message separated by a newline.
+const channel = "realtime_broker"
+
+// maxPayload is the largest notification Postgres accepts.
+const maxPayload = 7999
+
+// reconnectDelay is how long the listener waits after losing its
+// connection before taking another.
+const reconnectDelay = time.Second
+
+var ErrMessageTooLarge = errors.New("message too large for the broker")
+
+// Postgres is a broker shared by every process connected to the same
+// database, through LISTEN and NOTIFY. Messages published while a process
+// is reconnecting its listener are lost to it.
+type Postgres struct {
+	topics
+	pool *pgxpool.Pool
+}
+
+// NewPostgres starts listening on a connection taken from pool, which it
+// holds until ctx is done.
+func NewPostgres(ctx context.Context, pool *pgxpool.Pool) *Postgres {
+	b := &Postgres{topics: newTopics(), pool: pool}
+	go b.listen(ctx)
+	return b
+}
+
+func (b *Postgres) Publish(ctx context.Context, topic string, msg []byte) error {
+	payload := topic + "\n" + string(msg)
+	if len(payload) > maxPayload {
+		return ErrMessageTooLarge
+	}
+
+	_, err := b.pool.Exec(ctx, "SELECT pg_notify($1, $2)", channel, payload)
+	return err
+}
+
+func (b *Postgres) listen(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := b.receive(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Println("broker: listen:", err)
+		time.Sleep(reconnectDelay)
+	}
+}
+
+// receive delivers notifications until the connection fails.
+func (b *Postgres) receive(ctx context.Context) error {
+	conn, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+channel); err != nil {
+		return err
+	}
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		topic, msg, ok := strings.Cut(n.Payload, "\n")
+		if !ok {
+			continue
+		}
+		b.deliver(topic, []byte(msg))
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -11,9 +12,56 @@ import (
 type Config struct {
 	Env         string
 	Port        string
+	WSPort      string
 	DBURL       string
 	JWTSecret   string
 	ReadTimeout time.Duration
+
+	// TickRate is how many times a second each live space applies moves
+	// and sends state deltas.
+	TickRate int
+
+	// AutoKick is how many movement violations within ten seconds get a
+	// client kicked; zero turns automatic kicks off.
+	AutoKick int
+
+	// ResumeGrace is how long a client that lost its connection keeps its
+	// place in a space for a reconnect to resume it.
+	ResumeGrace time.Duration
+
+	// SendQueue is how many events may wait to be written to a client,
+	// and SlowClientTimeout how long a client may have over half that
+	// many waiting before it is disconnected.
+	SendQueue         int
+	SlowClientTimeout time.Duration
+
+	// PingInterval is how often clients are pinged and PingTimeout how long
+	// one may stay silent before it is dropped. AFKTimeout is how long a
+	// user may send nothing before being shown as idle; zero turns it off.
+	PingInterval time.Duration
+	PingTimeout  time.Duration
+	AFKTimeout   time.Duration
+
+	// Broker is how nodes share rooms: "memory" for a single node, or
+	// "postgres" for every node using the database.
+	Broker string
+
+	// WSPublicURL is where clients reach this node's WebSocket server.
+	// Setting it shares spaces out between the nodes, which redirect
+	// clients to the one serving a space; empty, every node serves every
+	// space.
+	WSPublicURL string
+
+	// TrashRetention is how long soft-deleted rows are kept before the
+	// purger removes them for good.
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// SMTPAddr is empty in development, where mail is only logged.
+	SMTPAddr  string
+	MailFrom  string
+	InviteURL string
+	InviteTTL time.Duration
 }
 
 func Load() *Config {
@@ -21,9 +69,29 @@ func Load() *Config {
 	cfg := &Config{
 		Env:         getEnv("ENV", "development"),
 		Port:        getEnv("PORT", "8080"),
+		WSPort:      getEnv("WS_PORT", "3001"),
 		DBURL:       getEnv("DATABASE_URL", ""),
 		JWTSecret:   getEnv("JWT_SECRET", "supersecret"),
 		ReadTimeout: 5 * time.Second,
+		TickRate:    getInt("TICK_RATE", 20),
+		AutoKick:    getInt("ANTICHEAT_AUTO_KICK", 20),
+		ResumeGrace: getDuration("RESUME_GRACE", 30*time.Second),
+		Broker:      getEnv("REALTIME_BROKER", "memory"),
+		WSPublicURL: getEnv("WS_PUBLIC_URL", ""),
+
+		SendQueue:         getInt("SEND_QUEUE", 256),
+		SlowClientTimeout: getDuration("SLOW_CLIENT_TIMEOUT", 5*time.Second),
+		PingInterval:      getDuration("PING_INTERVAL", 10*time.Second),
+		PingTimeout:       getDuration("PING_TIMEOUT", 30*time.Second),
+		AFKTimeout:        getDuration("AFK_TIMEOUT", 5*time.Minute),
+
+		TrashRetention:     getDuration("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval: getDuration("TRASH_PURGE_INTERVAL", time.Hour),
+
+		SMTPAddr:  getEnv("SMTP_ADDR", ""),
+		MailFrom:  getEnv("MAIL_FROM", "no-reply@localhost"),
+		InviteURL: getEnv("INVITE_URL", "http://localhost:5173/invitation"),
+		InviteTTL: getDuration("INVITE_TTL", 7*24*time.Hour),
 	}
 
 	if cfg.DBURL == "" {
@@ -40,3 +108,29 @@ func getEnv(key string, fallback string) string {
 	}
 	return val
 }
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	val, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		log.Fatalf("%s must be a duration: %v", key, err)
+	}
+	return d
+}
+
+func getInt(key string, fallback int) int {
+	val, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 0 {
+		log.Fatalf("%s must be a non-negative integer", key)
+	}
+	return n
+}
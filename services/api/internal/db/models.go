@@ -8,13 +8,182 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type User struct {
+type Element struct {
+	ID          pgtype.UUID
+	ImageUrl    string
+	Width       int32
+	Height      int32
+	Static      bool
+	CreatedAt   pgtype.Timestamp
+	UpdatedAt   pgtype.Timestamp
+	DeletedAt   pgtype.Timestamp
+	ContentHash pgtype.Text
+}
+
+type FriendRequest struct {
+	ID         pgtype.UUID
+	SenderID   pgtype.UUID
+	ReceiverID pgtype.UUID
+	Status     string
+	CreatedAt  pgtype.Timestamp
+	UpdatedAt  pgtype.Timestamp
+}
+
+type RealtimeNode struct {
+	ID          string
+	Url         string
+	Draining    bool
+	HeartbeatAt pgtype.Timestamp
+}
+
+type Space struct {
+	ID           pgtype.UUID
+	Name         string
+	Width        int32
+	Height       int32
+	Thumbnail    pgtype.Text
+	CreatorID    pgtype.UUID
+	CreatedAt    pgtype.Timestamp
+	UpdatedAt    pgtype.Timestamp
+	DeletedAt    pgtype.Timestamp
+	Visibility   string
+	PasswordHash string
+	Capacity     pgtype.Int4
+	RateLimits   []byte
+}
+
+type SpaceElement struct {
+	ID        pgtype.UUID
+	SpaceID   pgtype.UUID
+	ElementID pgtype.UUID
+	X         int32
+	Y         int32
+	CreatedAt pgtype.Timestamp
+	DeletedAt pgtype.Timestamp
+}
+
+type SpaceInvitation struct {
 	ID        pgtype.UUID
+	SpaceID   pgtype.UUID
+	Code      string
+	CreatedBy pgtype.UUID
+	ExpiresAt pgtype.Timestamp
+	MaxUses   pgtype.Int4
+	Uses      int32
+	CreatedAt pgtype.Timestamp
+}
+
+type SpaceMember struct {
+	SpaceID   pgtype.UUID
+	UserID    pgtype.UUID
+	Role      string
+	CreatedAt pgtype.Timestamp
+}
+
+type SpacePortal struct {
+	ElementID          pgtype.UUID
+	SpaceID            pgtype.UUID
+	DestinationSpaceID pgtype.UUID
+	DestinationX       int32
+	DestinationY       int32
+	CreatedAt          pgtype.Timestamp
+	UpdatedAt          pgtype.Timestamp
+}
+
+type SpaceSanction struct {
+	SpaceID     pgtype.UUID
+	UserID      pgtype.UUID
+	Kind        string
+	Reason      string
+	ModeratorID pgtype.UUID
+	ExpiresAt   pgtype.Timestamp
+	CreatedAt   pgtype.Timestamp
+}
+
+type SpaceVersion struct {
+	SpaceID         pgtype.UUID
+	Version         int32
+	AuthorID        pgtype.UUID
+	Action          string
+	RestoredVersion pgtype.Int4
+	Elements        []byte
+	CreatedAt       pgtype.Timestamp
+}
+
+type SpaceZone struct {
+	ID         pgtype.UUID
+	SpaceID    pgtype.UUID
+	Name       string
+	Shape      string
+	Points     []byte
+	Properties []byte
+	ChatScope  string
+	Audio      string
+	MinRole    string
+	CreatedAt  pgtype.Timestamp
+	UpdatedAt  pgtype.Timestamp
+}
+
+type User struct {
+	ID                 pgtype.UUID
+	Name               string
+	Email              string
+	Password           string
+	AvatarID           pgtype.UUID
+	Role               string
+	CreatedAt          pgtype.Timestamp
+	UpdatedAt          pgtype.Timestamp
+	PresenceVisibility string
+	DeletedAt          pgtype.Timestamp
+}
+
+type UserBlock struct {
+	UserID    pgtype.UUID
+	BlockedID pgtype.UUID
+	Kind      string
+	CreatedAt pgtype.Timestamp
+}
+
+type UserImport struct {
+	ID         pgtype.UUID
+	CreatedBy  pgtype.UUID
+	Status     string
+	DryRun     bool
+	CreatedAt  pgtype.Timestamp
+	UpdatedAt  pgtype.Timestamp
+	FinishedAt pgtype.Timestamp
+}
+
+type UserImportRow struct {
+	ImportID  pgtype.UUID
+	RowNumber int32
 	Name      string
 	Email     string
-	Password  string
-	AvatarID  pgtype.UUID
 	Role      string
-	CreatedAt pgtype.Timestamp
+	AvatarID  string
+	Status    string
+	Message   string
+}
+
+type UserInvitation struct {
+	ID         pgtype.UUID
+	UserID     pgtype.UUID
+	TokenHash  string
+	ExpiresAt  pgtype.Timestamp
+	AcceptedAt pgtype.Timestamp
+	CreatedAt  pgtype.Timestamp
+}
+
+type UserPreference struct {
+	UserID    pgtype.UUID
+	Version   int32
+	Document  []byte
 	UpdatedAt pgtype.Timestamp
 }
+
+type UserPresence struct {
+	UserID  pgtype.UUID
+	SpaceID pgtype.UUID
+	NodeID  string
+	SeenAt  pgtype.Timestamp
+}
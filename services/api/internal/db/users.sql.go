@@ -11,11 +11,54 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const createInvitedUser = `-- name: CreateInvitedUser :one
+INSERT INTO users(id, name, email, password, avatar_id, role, created_at, updated_at)
+VALUES($1,$2,$3,'',$4,$5,$6,$7)
+ON CONFLICT ((lower(email))) DO NOTHING
+RETURNING id, name, email, password, avatar_id, role, created_at, updated_at, presence_visibility, deleted_at
+`
+
+type CreateInvitedUserParams struct {
+	ID        pgtype.UUID
+	Name      string
+	Email     string
+	AvatarID  pgtype.UUID
+	Role      string
+	CreatedAt pgtype.Timestamp
+	UpdatedAt pgtype.Timestamp
+}
+
+func (q *Queries) CreateInvitedUser(ctx context.Context, arg CreateInvitedUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, createInvitedUser,
+		arg.ID,
+		arg.Name,
+		arg.Email,
+		arg.AvatarID,
+		arg.Role,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Password,
+		&i.AvatarID,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PresenceVisibility,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 
 INSERT INTO users(id, name, email, password, created_at, updated_at)
 VALUES($1,$2,$3,$4,$5,$6)
-RETURNING id, name, email, password, avatar_id, role, created_at, updated_at
+RETURNING id, name, email, password, avatar_id, role, created_at, updated_at, presence_visibility, deleted_at
 `
 
 type CreateUserParams struct {
@@ -46,12 +89,122 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Role,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PresenceVisibility,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getInvitedUserForUpdate = `-- name: GetInvitedUserForUpdate :one
+SELECT id, name, email, password, avatar_id, role, created_at, updated_at, presence_visibility, deleted_at FROM users
+WHERE lower(email) = lower($1) AND password = '' AND deleted_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetInvitedUserForUpdate(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getInvitedUserForUpdate, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Password,
+		&i.AvatarID,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PresenceVisibility,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const getUser = `-- name: GetUser :one
+SELECT id, name, email, password, avatar_id, role, created_at, updated_at, presence_visibility, deleted_at FROM users
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUser(ctx context.Context, id pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, getUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Password,
+		&i.AvatarID,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PresenceVisibility,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, name, email, password, avatar_id, role, created_at, updated_at, presence_visibility, deleted_at FROM users
+WHERE lower(email) = lower($1) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Password,
+		&i.AvatarID,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PresenceVisibility,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const listDeletedUsers = `-- name: ListDeletedUsers :many
+SELECT id, name, email, password, avatar_id, role, created_at, updated_at, presence_visibility, deleted_at FROM users
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) ListDeletedUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.Query(ctx, listDeletedUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Password,
+			&i.AvatarID,
+			&i.Role,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PresenceVisibility,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsers = `-- name: ListUsers :many
-SELECT id, name, email, password, avatar_id, role, created_at, updated_at FROM users
+SELECT id, name, email, password, avatar_id, role, created_at, updated_at, presence_visibility, deleted_at FROM users
+WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 `
 
@@ -73,6 +226,8 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 			&i.Role,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PresenceVisibility,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -83,3 +238,116 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	}
 	return items, nil
 }
+
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, deletedAt pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedUsers, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreUser = `-- name: RestoreUser :execrows
+UPDATE users
+SET deleted_at = NULL, updated_at = $2
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+type RestoreUserParams struct {
+	ID        pgtype.UUID
+	UpdatedAt pgtype.Timestamp
+}
+
+func (q *Queries) RestoreUser(ctx context.Context, arg RestoreUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreUser, arg.ID, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setUserPassword = `-- name: SetUserPassword :exec
+UPDATE users
+SET password = $2, updated_at = $3
+WHERE id = $1
+`
+
+type SetUserPasswordParams struct {
+	ID        pgtype.UUID
+	Password  string
+	UpdatedAt pgtype.Timestamp
+}
+
+func (q *Queries) SetUserPassword(ctx context.Context, arg SetUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, setUserPassword, arg.ID, arg.Password, arg.UpdatedAt)
+	return err
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = $2
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type SoftDeleteUserParams struct {
+	ID        pgtype.UUID
+	DeletedAt pgtype.Timestamp
+}
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, arg SoftDeleteUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteUser, arg.ID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateUserPresenceVisibility = `-- name: UpdateUserPresenceVisibility :exec
+UPDATE users
+SET presence_visibility = $2, updated_at = $3
+WHERE id = $1
+`
+
+type UpdateUserPresenceVisibilityParams struct {
+	ID                 pgtype.UUID
+	PresenceVisibility string
+	UpdatedAt          pgtype.Timestamp
+}
+
+func (q *Queries) UpdateUserPresenceVisibility(ctx context.Context, arg UpdateUserPresenceVisibilityParams) error {
+	_, err := q.db.Exec(ctx, updateUserPresenceVisibility, arg.ID, arg.PresenceVisibility, arg.UpdatedAt)
+	return err
+}
+
+const userActive = `-- name: UserActive :one
+SELECT EXISTS (
+    SELECT 1 FROM users
+    WHERE id = $1 AND deleted_at IS NULL
+)
+`
+
+func (q *Queries) UserActive(ctx context.Context, id pgtype.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, userActive, id)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const userEmailExists = `-- name: UserEmailExists :one
+SELECT EXISTS (
+    SELECT 1 FROM users
+    WHERE lower(email) = lower($1)
+)
+`
+
+func (q *Queries) UserEmailExists(ctx context.Context, email string) (bool, error) {
+	row := q.db.QueryRow(ctx, userEmailExists, email)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type BlockHandler struct {
+	service service.BlockService
+}
+
+func NewBlockHandler(s service.BlockService) *BlockHandler {
+	return &BlockHandler{service: s}
+}
+
+type blockRequest struct {
+	UserID string `json:"userId"`
+}
+
+type blockResponse struct {
+	UserID    string `json:"userId"`
+	Kind      string `json:"kind"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// POST /api/v1/user/blocks
+func (h *BlockHandler) Block(w http.ResponseWriter, r *http.Request) {
+	h.put(w, r, h.service.Block)
+}
+
+// POST /api/v1/user/mutes
+func (h *BlockHandler) Mute(w http.ResponseWriter, r *http.Request) {
+	h.put(w, r, h.service.Mute)
+}
+
+func (h *BlockHandler) put(
+	w http.ResponseWriter,
+	r *http.Request,
+	put func(ctx context.Context, userID, targetID string) (*service.Block, error),
+) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req blockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	b, err := put(r.Context(), userID, req.UserID)
+	if err != nil {
+		writeBlockError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toBlockResponse(*b))
+}
+
+// DELETE /api/v1/user/blocks/{id}
+func (h *BlockHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.Remove(r.Context(), userID, r.PathValue("id")); err != nil {
+		writeBlockError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/v1/user/blocks
+func (h *BlockHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	blocks, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		writeBlockError(w, err)
+		return
+	}
+
+	resp := make([]blockResponse, 0, len(blocks))
+	for _, b := range blocks {
+		resp = append(resp, toBlockResponse(b))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"blocks": resp,
+	})
+}
+
+func writeBlockError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrInvalidUserID, service.ErrInvalidBlockTarget:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case service.ErrUserNotFound, service.ErrBlockNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func toBlockResponse(b service.Block) blockResponse {
+	return blockResponse{
+		UserID:    b.TargetID,
+		Kind:      b.Kind,
+		CreatedAt: b.CreatedAt.Format(time.RFC3339),
+	}
+}
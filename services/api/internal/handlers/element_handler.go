@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type ElementHandler struct {
+	service service.ElementService
+}
+
+func NewElementHandler(s service.ElementService) *ElementHandler {
+	return &ElementHandler{service: s}
+}
+
+type deletedElementResponse struct {
+	ID        string `json:"id"`
+	ImageURL  string `json:"imageUrl"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Static    bool   `json:"static"`
+	DeletedAt string `json:"deletedAt"`
+}
+
+// DELETE /api/v1/admin/element/{id}
+func (h *ElementHandler) DeleteElement(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteElement(r.Context(), r.PathValue("id")); err != nil {
+		writeElementError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{})
+}
+
+// GET /api/v1/admin/element/trash
+func (h *ElementHandler) ListDeletedElements(w http.ResponseWriter, r *http.Request) {
+	elements, err := h.service.ListDeletedElements(r.Context())
+	if err != nil {
+		writeElementError(w, err)
+		return
+	}
+
+	resp := make([]deletedElementResponse, 0, len(elements))
+	for _, e := range elements {
+		resp = append(resp, deletedElementResponse{
+			ID:        e.ID,
+			ImageURL:  e.ImageURL,
+			Width:     e.Width,
+			Height:    e.Height,
+			Static:    e.Static,
+			DeletedAt: e.DeletedAt.Format(time.RFC3339),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"elements": resp,
+	})
+}
+
+// POST /api/v1/admin/element/{id}/restore
+func (h *ElementHandler) RestoreElement(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RestoreElement(r.Context(), r.PathValue("id")); err != nil {
+		writeElementError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{})
+}
+
+func writeElementError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrElementNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
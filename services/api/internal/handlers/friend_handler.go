@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type FriendHandler struct {
+	service service.FriendService
+}
+
+func NewFriendHandler(s service.FriendService) *FriendHandler {
+	return &FriendHandler{service: s}
+}
+
+type friendRequestBody struct {
+	UserID string `json:"userId"`
+}
+
+type friendRequestResponse struct {
+	ID         string `json:"id"`
+	SenderID   string `json:"senderId"`
+	ReceiverID string `json:"receiverId"`
+	Status     string `json:"status"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+type friendResponse struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	AvatarID     string `json:"avatarId,omitempty"`
+	Online       bool   `json:"online"`
+	SpaceID      string `json:"spaceId,omitempty"`
+	FriendsSince string `json:"friendsSince"`
+}
+
+type presenceVisibilityBody struct {
+	Visibility string `json:"visibility"`
+}
+
+// POST /api/v1/friends/requests
+func (h *FriendHandler) SendRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body friendRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	req, err := h.service.SendRequest(r.Context(), userID, body.UserID)
+	if err != nil {
+		writeFriendError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toFriendRequestResponse(req))
+}
+
+// GET /api/v1/friends/requests
+func (h *FriendHandler) ListRequests(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	requests, err := h.service.ListRequests(r.Context(), userID)
+	if err != nil {
+		writeFriendError(w, err)
+		return
+	}
+
+	incoming := []friendRequestResponse{}
+	outgoing := []friendRequestResponse{}
+	for i := range requests {
+		if requests[i].ReceiverID == userID {
+			incoming = append(incoming, toFriendRequestResponse(&requests[i]))
+		} else {
+			outgoing = append(outgoing, toFriendRequestResponse(&requests[i]))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"incoming": incoming,
+		"outgoing": outgoing,
+	})
+}
+
+// POST /api/v1/friends/requests/{id}/accept
+func (h *FriendHandler) AcceptRequest(w http.ResponseWriter, r *http.Request) {
+	h.resolveRequest(w, r, h.service.AcceptRequest)
+}
+
+// POST /api/v1/friends/requests/{id}/decline
+func (h *FriendHandler) DeclineRequest(w http.ResponseWriter, r *http.Request) {
+	h.resolveRequest(w, r, h.service.DeclineRequest)
+}
+
+// DELETE /api/v1/friends/requests/{id}
+func (h *FriendHandler) CancelRequest(w http.ResponseWriter, r *http.Request) {
+	h.resolveRequest(w, r, h.service.CancelRequest)
+}
+
+func (h *FriendHandler) resolveRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+	resolve func(ctx context.Context, userID, requestID string) (*service.FriendRequest, error),
+) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	req, err := resolve(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeFriendError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toFriendRequestResponse(req))
+}
+
+// GET /api/v1/friends
+func (h *FriendHandler) ListFriends(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	friends, err := h.service.ListFriends(r.Context(), userID)
+	if err != nil {
+		writeFriendError(w, err)
+		return
+	}
+
+	resp := make([]friendResponse, 0, len(friends))
+	for _, f := range friends {
+		resp = append(resp, friendResponse{
+			ID:           f.ID,
+			Name:         f.Name,
+			AvatarID:     f.AvatarID,
+			Online:       f.SpaceID != "",
+			SpaceID:      f.SpaceID,
+			FriendsSince: f.FriendsSince.Format(time.RFC3339),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"friends": resp,
+	})
+}
+
+// POST /api/v1/friends/{id}/join
+func (h *FriendHandler) JoinFriend(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	target, err := h.service.JoinFriend(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeFriendError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"spaceId":     target.SpaceID,
+		"spawnTicket": target.SpawnTicket,
+	})
+}
+
+// PUT /api/v1/user/presence
+func (h *FriendHandler) SetPresenceVisibility(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body presenceVisibilityBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.SetPresenceVisibility(r.Context(), userID, body.Visibility); err != nil {
+		writeFriendError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"visibility": body.Visibility,
+	})
+}
+
+func writeFriendError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrInvalidUserID, service.ErrInvalidFriendRequest, service.ErrInvalidVisibility:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case service.ErrFriendRequestForbidden, service.ErrNotFriends, service.ErrUserBlocked:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case service.ErrUserNotFound, service.ErrFriendRequestNotFound, service.ErrFriendOffline:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case service.ErrFriendRequestExists, service.ErrFriendRequestResolved:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func toFriendRequestResponse(req *service.FriendRequest) friendRequestResponse {
+	return friendRequestResponse{
+		ID:         req.ID,
+		SenderID:   req.SenderID,
+		ReceiverID: req.ReceiverID,
+		Status:     req.Status,
+		CreatedAt:  req.CreatedAt.Format(time.RFC3339),
+	}
+}
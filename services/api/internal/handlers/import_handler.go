@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+const maxImportFileSize = 5 << 20
+
+type ImportHandler struct {
+	service service.ImportService
+}
+
+func NewImportHandler(s service.ImportService) *ImportHandler {
+	return &ImportHandler{service: s}
+}
+
+type userImportRowResponse struct {
+	Row     int    `json:"row"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Role    string `json:"role"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type userImportResponse struct {
+	ID         string                  `json:"id"`
+	Status     string                  `json:"status"`
+	DryRun     bool                    `json:"dryRun"`
+	CreatedAt  string                  `json:"createdAt"`
+	FinishedAt string                  `json:"finishedAt,omitempty"`
+	Summary    map[string]int          `json:"summary"`
+	Rows       []userImportRowResponse `json:"rows"`
+}
+
+// POST /api/v1/admin/users/import?dryRun=true
+//
+// The body is the CSV file itself.
+func (h *ImportHandler) StartUserImport(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	dryRun := false
+	if v := r.URL.Query().Get("dryRun"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "invalid dryRun", http.StatusBadRequest)
+			return
+		}
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportFileSize)
+
+	imp, err := h.service.StartUserImport(r.Context(), adminID, body, dryRun)
+	if err != nil {
+		writeImportError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/admin/users/import/"+imp.ID)
+	writeJSON(w, http.StatusAccepted, toUserImportResponse(imp))
+}
+
+// GET /api/v1/admin/users/import/{id}
+func (h *ImportHandler) GetUserImport(w http.ResponseWriter, r *http.Request) {
+	imp, err := h.service.GetUserImport(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeImportError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toUserImportResponse(imp))
+}
+
+func toUserImportResponse(imp *service.UserImport) userImportResponse {
+	resp := userImportResponse{
+		ID:        imp.ID,
+		Status:    imp.Status,
+		DryRun:    imp.DryRun,
+		CreatedAt: imp.CreatedAt.Format(time.RFC3339),
+		Summary:   imp.Summary(),
+		Rows:      make([]userImportRowResponse, 0, len(imp.Rows)),
+	}
+	if !imp.FinishedAt.IsZero() {
+		resp.FinishedAt = imp.FinishedAt.Format(time.RFC3339)
+	}
+
+	for _, row := range imp.Rows {
+		resp.Rows = append(resp.Rows, userImportRowResponse{
+			Row:     row.Number,
+			Name:    row.Name,
+			Email:   row.Email,
+			Role:    row.Role,
+			Status:  row.Status,
+			Message: row.Message,
+		})
+	}
+
+	return resp
+}
+
+func writeImportError(w http.ResponseWriter, err error) {
+	var tooBig *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &tooBig):
+		http.Error(w, "csv file is too large", http.StatusRequestEntityTooLarge)
+	case errors.Is(err, service.ErrInvalidImportFile):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrImportTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, service.ErrUserImportNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
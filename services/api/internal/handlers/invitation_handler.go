@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type InvitationHandler struct {
+	service service.InvitationService
+}
+
+func NewInvitationHandler(s service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{service: s}
+}
+
+type acceptInvitationRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// POST /api/v1/invitations/accept
+func (h *InvitationHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	var req acceptInvitationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.AcceptInvitation(r.Context(), req.Token, req.Password); err != nil {
+		switch err {
+		case service.ErrWeakPassword:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case service.ErrInvitationNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case service.ErrInvitationExpired:
+			http.Error(w, err.Error(), http.StatusGone)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{})
+}
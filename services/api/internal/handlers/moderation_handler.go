@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type ModerationHandler struct {
+	service service.ModerationService
+}
+
+func NewModerationHandler(s service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{service: s}
+}
+
+type moderationRequest struct {
+	UserID string `json:"userId"`
+	Reason string `json:"reason"`
+	// Duration is in seconds. Mutes need one; a ban without one is
+	// permanent.
+	Duration int `json:"duration"`
+}
+
+type sanctionResponse struct {
+	UserID      string `json:"userId"`
+	Kind        string `json:"kind"`
+	Reason      string `json:"reason"`
+	ModeratorID string `json:"moderatorId"`
+	ExpiresAt   string `json:"expiresAt,omitempty"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+// POST /api/v1/space/{id}/kick
+func (h *ModerationHandler) Kick(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req moderationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Kick(r.Context(), userID, r.PathValue("id"), req.UserID, req.Reason); err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{})
+}
+
+// POST /api/v1/space/{id}/mutes
+func (h *ModerationHandler) Mute(w http.ResponseWriter, r *http.Request) {
+	h.sanction(w, r, h.service.Mute)
+}
+
+// DELETE /api/v1/space/{id}/mutes/{userId}
+func (h *ModerationHandler) Unmute(w http.ResponseWriter, r *http.Request) {
+	h.lift(w, r, h.service.Unmute)
+}
+
+// POST /api/v1/space/{id}/bans
+func (h *ModerationHandler) Ban(w http.ResponseWriter, r *http.Request) {
+	h.sanction(w, r, h.service.Ban)
+}
+
+// DELETE /api/v1/space/{id}/bans/{userId}
+func (h *ModerationHandler) Unban(w http.ResponseWriter, r *http.Request) {
+	h.lift(w, r, h.service.Unban)
+}
+
+// GET /api/v1/space/{id}/sanctions
+func (h *ModerationHandler) ListSanctions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sanctions, err := h.service.ListSanctions(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	resp := make([]sanctionResponse, 0, len(sanctions))
+	for _, s := range sanctions {
+		resp = append(resp, toSanctionResponse(&s))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sanctions": resp,
+	})
+}
+
+func (h *ModerationHandler) sanction(
+	w http.ResponseWriter,
+	r *http.Request,
+	apply func(ctx context.Context, moderatorID, spaceID, userID, reason string, d time.Duration) (*service.SpaceSanction, error),
+) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req moderationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	d := time.Duration(req.Duration) * time.Second
+	sanction, err := apply(r.Context(), userID, r.PathValue("id"), req.UserID, req.Reason, d)
+	if err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toSanctionResponse(sanction))
+}
+
+func (h *ModerationHandler) lift(
+	w http.ResponseWriter,
+	r *http.Request,
+	lift func(ctx context.Context, moderatorID, spaceID, userID string) error,
+) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := lift(r.Context(), userID, r.PathValue("id"), r.PathValue("userId")); err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toSanctionResponse(s *service.SpaceSanction) sanctionResponse {
+	resp := sanctionResponse{
+		UserID:      s.UserID,
+		Kind:        s.Kind,
+		Reason:      s.Reason,
+		ModeratorID: s.ModeratorID,
+		CreatedAt:   s.CreatedAt.Format(time.RFC3339),
+	}
+	if !s.ExpiresAt.IsZero() {
+		resp.ExpiresAt = s.ExpiresAt.Format(time.RFC3339)
+	}
+	return resp
+}
+
+func writeModerationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidUserID), errors.Is(err, service.ErrInvalidSanctionDuration):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrCannotModerate):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrUserNotInSpace), errors.Is(err, service.ErrSanctionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		writeSpaceError(w, err)
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type PathHandler struct {
+	service service.PathService
+}
+
+func NewPathHandler(s service.PathService) *PathHandler {
+	return &PathHandler{service: s}
+}
+
+// GET /api/v1/space/{id}/path?fromX=&fromY=&toX=&toY=
+func (h *PathHandler) FindPath(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var coords [4]int
+	for i, name := range []string{"fromX", "fromY", "toX", "toY"} {
+		n, err := strconv.Atoi(r.URL.Query().Get(name))
+		if err != nil {
+			http.Error(w, "invalid "+name, http.StatusBadRequest)
+			return
+		}
+		coords[i] = n
+	}
+
+	from := service.Point{X: coords[0], Y: coords[1]}
+	to := service.Point{X: coords[2], Y: coords[3]}
+
+	path, err := h.service.FindPath(r.Context(), userID, r.PathValue("id"), r.Header.Get(spacePasswordHeader), from, to)
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"path": path,
+	})
+}
+
+func writePathError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrInvalidPathPoint:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case service.ErrNoPath:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		writeSpaceError(w, err)
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type PortalHandler struct {
+	service service.PortalService
+}
+
+func NewPortalHandler(s service.PortalService) *PortalHandler {
+	return &PortalHandler{service: s}
+}
+
+type setPortalRequest struct {
+	DestinationSpaceID string        `json:"destinationSpaceId"`
+	Spawn              service.Point `json:"spawn"`
+}
+
+type portalResponse struct {
+	ElementID          string        `json:"elementId"`
+	X                  int           `json:"x"`
+	Y                  int           `json:"y"`
+	DestinationSpaceID string        `json:"destinationSpaceId"`
+	Spawn              service.Point `json:"spawn"`
+	UpdatedAt          string        `json:"updatedAt"`
+}
+
+// GET /api/v1/space/{id}/portals
+func (h *PortalHandler) ListPortals(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	portals, err := h.service.ListPortals(r.Context(), userID, r.PathValue("id"), r.Header.Get(spacePasswordHeader))
+	if err != nil {
+		writePortalError(w, err)
+		return
+	}
+
+	resp := make([]portalResponse, 0, len(portals))
+	for _, p := range portals {
+		resp = append(resp, toPortalResponse(&p))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"portals": resp,
+	})
+}
+
+// PUT /api/v1/space/{id}/portals/{elementId}
+func (h *PortalHandler) SetPortal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req setPortalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	portal, err := h.service.SetPortal(
+		r.Context(),
+		userID,
+		r.PathValue("id"),
+		r.PathValue("elementId"),
+		req.DestinationSpaceID,
+		req.Spawn,
+	)
+	if err != nil {
+		writePortalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toPortalResponse(portal))
+}
+
+// DELETE /api/v1/space/{id}/portals/{elementId}
+func (h *PortalHandler) DeletePortal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeletePortal(r.Context(), userID, r.PathValue("id"), r.PathValue("elementId")); err != nil {
+		writePortalError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toPortalResponse(p *service.Portal) portalResponse {
+	return portalResponse{
+		ElementID:          p.ElementID,
+		X:                  p.X,
+		Y:                  p.Y,
+		DestinationSpaceID: p.DestinationID,
+		Spawn:              p.Spawn,
+		UpdatedAt:          p.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func writePortalError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrInvalidPortalDestination:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case service.ErrPortalNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		writeSpaceError(w, err)
+	}
+}
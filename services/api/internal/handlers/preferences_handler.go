@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+const maxPreferencesPatchBytes = 64 << 10
+
+type PreferencesHandler struct {
+	service service.PreferencesService
+}
+
+func NewPreferencesHandler(s service.PreferencesService) *PreferencesHandler {
+	return &PreferencesHandler{service: s}
+}
+
+type preferencesResponse struct {
+	Version   int    `json:"version"`
+	Document  any    `json:"preferences"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+}
+
+type validationErrorResponse struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// GET /api/v1/user/preferences
+func (h *PreferencesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	prefs, err := h.service.Get(r.Context(), userID)
+	if err != nil {
+		writePreferencesError(w, err)
+		return
+	}
+
+	etag := preferencesETag(prefs.Version)
+	if r.Header.Get("If-None-Match") == etag {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writePreferences(w, prefs)
+}
+
+// PATCH /api/v1/user/preferences
+//
+// The body is a JSON Merge Patch (application/merge-patch+json). An If-Match
+// header carrying the ETag from a previous read makes the update conditional.
+func (h *PreferencesHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var ifMatch *int
+	if header := r.Header.Get("If-Match"); header != "" && header != "*" {
+		version, ok := parsePreferencesETag(header)
+		if !ok {
+			http.Error(w, service.ErrPreferencesConflict.Error(), http.StatusPreconditionFailed)
+			return
+		}
+		ifMatch = &version
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPreferencesPatchBytes))
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.service.Patch(r.Context(), userID, ifMatch, patch)
+	if err != nil {
+		writePreferencesError(w, err)
+		return
+	}
+
+	writePreferences(w, prefs)
+}
+
+func writePreferences(w http.ResponseWriter, prefs *service.Preferences) {
+	resp := preferencesResponse{
+		Version:  prefs.Version,
+		Document: prefs.Document,
+	}
+	if !prefs.UpdatedAt.IsZero() {
+		resp.UpdatedAt = prefs.UpdatedAt.Format("2006-01-02T15:04:05Z")
+	}
+
+	w.Header().Set("ETag", preferencesETag(prefs.Version))
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writePreferencesError(w http.ResponseWriter, err error) {
+	var validationErr *service.PreferencesValidationError
+	if errors.As(err, &validationErr) {
+		errs := make([]validationErrorResponse, 0, len(validationErr.Errors))
+		for _, e := range validationErr.Errors {
+			errs = append(errs, validationErrorResponse{Path: e.Path, Message: e.Message})
+		}
+
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{
+			"error":  err.Error(),
+			"errors": errs,
+		})
+		return
+	}
+
+	switch err {
+	case service.ErrInvalidMergePatch:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case service.ErrPreferencesConflict:
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+	case service.ErrUserNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func preferencesETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func parsePreferencesETag(header string) (int, bool) {
+	header = strings.TrimPrefix(strings.TrimSpace(header), "W/")
+
+	version, err := strconv.Atoi(strings.Trim(header, `"`))
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
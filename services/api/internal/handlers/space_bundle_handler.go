@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type SpaceBundleHandler struct {
+	service service.SpaceBundleService
+}
+
+func NewSpaceBundleHandler(s service.SpaceBundleService) *SpaceBundleHandler {
+	return &SpaceBundleHandler{service: s}
+}
+
+type importedElementResponse struct {
+	BundleID     string `json:"bundleId"`
+	ElementID    string `json:"elementId"`
+	Deduplicated bool   `json:"deduplicated"`
+}
+
+type bundleConflictResponse struct {
+	Kind    string `json:"kind"`
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+type spaceImportResponse struct {
+	SpaceID   string                    `json:"spaceId"`
+	Name      string                    `json:"name"`
+	Elements  []importedElementResponse `json:"elements"`
+	Conflicts []bundleConflictResponse  `json:"conflicts"`
+}
+
+// GET /api/v1/space/{id}/export?format=zip
+//
+// The bundle is returned as JSON unless format is zip.
+func (h *SpaceBundleHandler) ExportSpace(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "zip" {
+		http.Error(w, "invalid format", http.StatusBadRequest)
+		return
+	}
+
+	bundle, err := h.service.ExportSpace(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeSpaceBundleError(w, err)
+		return
+	}
+
+	name := "space-" + bundle.Space.ID
+	if format != "zip" {
+		w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.json"`)
+		writeJSON(w, http.StatusOK, bundle)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.zip"`)
+	if err := service.WriteSpaceBundleZip(w, bundle); err != nil {
+		log.Println("write space bundle:", err)
+	}
+}
+
+// POST /api/v1/space/import
+//
+// The body is the bundle itself, as JSON or a zip export.
+func (h *SpaceBundleHandler) ImportSpace(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	imp, err := h.service.ImportSpace(r.Context(), userID, r.Body)
+	if err != nil {
+		writeSpaceBundleError(w, err)
+		return
+	}
+
+	writeSpaceImport(w, imp)
+}
+
+// POST /api/v1/space/import/tiled?name=&thumbnail=&assetBaseUrl=&collisionLayer=
+//
+// The body is the map saved by Tiled, as JSON or TMX, with its tilesets
+// embedded.
+func (h *SpaceBundleHandler) ImportTiledMap(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	imp, err := h.service.ImportTiledMap(r.Context(), userID, r.Body, service.TiledImportOptions{
+		Name:           q.Get("name"),
+		Thumbnail:      q.Get("thumbnail"),
+		AssetBaseURL:   q.Get("assetBaseUrl"),
+		CollisionLayer: q.Get("collisionLayer"),
+	})
+	if err != nil {
+		writeSpaceBundleError(w, err)
+		return
+	}
+
+	writeSpaceImport(w, imp)
+}
+
+func writeSpaceImport(w http.ResponseWriter, imp *service.SpaceImport) {
+	resp := spaceImportResponse{
+		SpaceID:   imp.Space.ID,
+		Name:      imp.Space.Name,
+		Elements:  make([]importedElementResponse, 0, len(imp.Elements)),
+		Conflicts: make([]bundleConflictResponse, 0, len(imp.Conflicts)),
+	}
+	for _, e := range imp.Elements {
+		resp.Elements = append(resp.Elements, importedElementResponse(e))
+	}
+	for _, c := range imp.Conflicts {
+		resp.Conflicts = append(resp.Conflicts, bundleConflictResponse(c))
+	}
+
+	w.Header().Set("Location", "/api/v1/space/"+imp.Space.ID)
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func writeSpaceBundleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSpaceBundle):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrSpaceBundleTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	default:
+		writeSpaceError(w, err)
+	}
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type SpaceHandler struct {
+	service service.SpaceService
+}
+
+func NewSpaceHandler(s service.SpaceService) *SpaceHandler {
+	return &SpaceHandler{service: s}
+}
+
+type addSpaceElementRequest struct {
+	ElementID string `json:"elementId"`
+	SpaceID   string `json:"spaceId"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+}
+
+type deleteSpaceElementRequest struct {
+	ID string `json:"id"`
+}
+
+type deletedSpaceResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Dimensions string `json:"dimensions"`
+	DeletedAt  string `json:"deletedAt"`
+}
+
+type deletedSpaceElementResponse struct {
+	ID        string `json:"id"`
+	ElementID string `json:"elementId"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	DeletedAt string `json:"deletedAt"`
+}
+
+// DELETE /api/v1/space/{id}
+func (h *SpaceHandler) DeleteSpace(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteSpace(r.Context(), userID, r.PathValue("id")); err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{})
+}
+
+// GET /api/v1/space/trash
+func (h *SpaceHandler) ListDeletedSpaces(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	spaces, err := h.service.ListDeletedSpaces(r.Context(), userID)
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	resp := make([]deletedSpaceResponse, 0, len(spaces))
+	for _, s := range spaces {
+		resp = append(resp, deletedSpaceResponse{
+			ID:         s.ID,
+			Name:       s.Name,
+			Dimensions: fmt.Sprintf("%dx%d", s.Width, s.Height),
+			DeletedAt:  s.DeletedAt.Format(time.RFC3339),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"spaces": resp,
+	})
+}
+
+// POST /api/v1/space/{id}/restore
+func (h *SpaceHandler) RestoreSpace(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.RestoreSpace(r.Context(), userID, r.PathValue("id")); err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{})
+}
+
+// POST /api/v1/space/element
+func (h *SpaceHandler) AddSpaceElement(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req addSpaceElementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	el, err := h.service.AddSpaceElement(r.Context(), userID, req.SpaceID, req.ElementID, req.X, req.Y)
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"id": el.ID,
+	})
+}
+
+// DELETE /api/v1/space/element
+func (h *SpaceHandler) DeleteSpaceElement(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req deleteSpaceElementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteSpaceElement(r.Context(), userID, req.ID); err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{})
+}
+
+// GET /api/v1/space/{id}/trash
+func (h *SpaceHandler) ListDeletedSpaceElements(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	elements, err := h.service.ListDeletedSpaceElements(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	resp := make([]deletedSpaceElementResponse, 0, len(elements))
+	for _, e := range elements {
+		resp = append(resp, deletedSpaceElementResponse{
+			ID:        e.ID,
+			ElementID: e.ElementID,
+			X:         e.X,
+			Y:         e.Y,
+			DeletedAt: e.DeletedAt.Format(time.RFC3339),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"elements": resp,
+	})
+}
+
+// POST /api/v1/space/element/{id}/restore
+func (h *SpaceHandler) RestoreSpaceElement(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.RestoreSpaceElement(r.Context(), userID, r.PathValue("id")); err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{})
+}
+
+func writeSpaceError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrInvalidSpaceID, service.ErrInvalidUserID, service.ErrInvalidSpaceVisibility,
+		service.ErrInvalidSpaceRole, service.ErrInvalidSpaceInvitation, service.ErrInvalidSpaceCapacity,
+		service.ErrInvalidSpaceRateLimits, service.ErrInvalidElementPlace:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case service.ErrSpacePasswordRequired:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case service.ErrSpaceForbidden, service.ErrSpaceInviteOnly, service.ErrSpaceWrongPassword,
+		service.ErrSpaceBanned:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case service.ErrSpaceNotFound, service.ErrSpaceElementNotFound, service.ErrSpaceMemberNotFound,
+		service.ErrSpaceInvitationInvalid, service.ErrUserNotFound, service.ErrElementNotFound,
+		service.ErrSpaceVersionNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case service.ErrSpaceDeleted, service.ErrSpaceOwnerRole, service.ErrSpaceVersionConflict:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
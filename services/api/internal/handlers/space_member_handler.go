@@ -0,0 +1,369 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+// spacePasswordHeader carries the password of a password-protected space.
+const spacePasswordHeader = "X-Space-Password"
+
+type spaceElementResponse struct {
+	ID        string `json:"id"`
+	ElementID string `json:"elementId"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+}
+
+type spaceResponse struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	Dimensions string                 `json:"dimensions"`
+	Visibility string                 `json:"visibility"`
+	Capacity   int                    `json:"capacity"`
+	Elements   []spaceElementResponse `json:"elements"`
+}
+
+type setSpaceVisibilityRequest struct {
+	Visibility string `json:"visibility"`
+	Password   string `json:"password"`
+}
+
+type setSpaceCapacityRequest struct {
+	// Capacity of 0 derives the limit from the map size.
+	Capacity int `json:"capacity"`
+}
+
+type spaceRateLimitRequest struct {
+	Message string  `json:"message"`
+	Role    string  `json:"role"`
+	Rate    float64 `json:"rate"`
+	Burst   int     `json:"burst"`
+}
+
+type setSpaceRateLimitsRequest struct {
+	// An empty list goes back to the default limits.
+	RateLimits []spaceRateLimitRequest `json:"rateLimits"`
+}
+
+type spaceMemberResponse struct {
+	UserID   string `json:"userId"`
+	Role     string `json:"role"`
+	JoinedAt string `json:"joinedAt"`
+}
+
+type setSpaceMemberRequest struct {
+	Role string `json:"role"`
+}
+
+type createSpaceInvitationRequest struct {
+	// ExpiresIn is in seconds; 0 never expires.
+	ExpiresIn int `json:"expiresIn"`
+	// MaxUses of 0 is unlimited.
+	MaxUses int `json:"maxUses"`
+}
+
+type spaceInvitationResponse struct {
+	ID        string `json:"id"`
+	Code      string `json:"code"`
+	ExpiresAt string `json:"expiresAt,omitempty"`
+	MaxUses   int    `json:"maxUses,omitempty"`
+	Uses      int    `json:"uses"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// GET /api/v1/space/{id}
+func (h *SpaceHandler) GetSpace(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	space, elements, err := h.service.GetSpace(r.Context(), userID, r.PathValue("id"), r.Header.Get(spacePasswordHeader))
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	resp := spaceResponse{
+		ID:         space.ID,
+		Name:       space.Name,
+		Dimensions: fmt.Sprintf("%dx%d", space.Width, space.Height),
+		Visibility: space.Visibility,
+		Capacity:   space.MaxOccupancy(),
+		Elements:   make([]spaceElementResponse, 0, len(elements)),
+	}
+	for _, e := range elements {
+		resp.Elements = append(resp.Elements, spaceElementResponse{
+			ID:        e.ID,
+			ElementID: e.ElementID,
+			X:         e.X,
+			Y:         e.Y,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// PUT /api/v1/space/{id}/visibility
+func (h *SpaceHandler) SetSpaceVisibility(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req setSpaceVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.SetSpaceVisibility(r.Context(), userID, r.PathValue("id"), req.Visibility, req.Password)
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"visibility": req.Visibility,
+	})
+}
+
+// PUT /api/v1/space/{id}/capacity
+func (h *SpaceHandler) SetSpaceCapacity(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req setSpaceCapacityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.SetSpaceCapacity(r.Context(), userID, r.PathValue("id"), req.Capacity); err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{
+		"capacity": req.Capacity,
+	})
+}
+
+// PUT /api/v1/space/{id}/rate-limits
+func (h *SpaceHandler) SetSpaceRateLimits(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req setSpaceRateLimitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	limits := make([]service.SpaceRateLimit, 0, len(req.RateLimits))
+	for _, l := range req.RateLimits {
+		limits = append(limits, service.SpaceRateLimit{
+			Message: l.Message,
+			Role:    l.Role,
+			Rate:    l.Rate,
+			Burst:   l.Burst,
+		})
+	}
+
+	if err := h.service.SetSpaceRateLimits(r.Context(), userID, r.PathValue("id"), limits); err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"rateLimits": limits,
+	})
+}
+
+// GET /api/v1/space/{id}/members
+func (h *SpaceHandler) ListSpaceMembers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	members, err := h.service.ListSpaceMembers(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	resp := make([]spaceMemberResponse, 0, len(members))
+	for _, m := range members {
+		resp = append(resp, toSpaceMemberResponse(&m))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"members": resp,
+	})
+}
+
+// PUT /api/v1/space/{id}/members/{userId}
+func (h *SpaceHandler) SetSpaceMemberRole(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req setSpaceMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	member, err := h.service.SetSpaceMemberRole(r.Context(), userID, r.PathValue("id"), r.PathValue("userId"), req.Role)
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toSpaceMemberResponse(member))
+}
+
+// DELETE /api/v1/space/{id}/members/{userId}
+func (h *SpaceHandler) RemoveSpaceMember(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.RemoveSpaceMember(r.Context(), userID, r.PathValue("id"), r.PathValue("userId")); err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /api/v1/space/{id}/invitations
+func (h *SpaceHandler) CreateSpaceInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req createSpaceInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	inv, err := h.service.CreateSpaceInvitation(
+		r.Context(),
+		userID,
+		r.PathValue("id"),
+		time.Duration(req.ExpiresIn)*time.Second,
+		req.MaxUses,
+	)
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toSpaceInvitationResponse(inv))
+}
+
+// GET /api/v1/space/{id}/invitations
+func (h *SpaceHandler) ListSpaceInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	invitations, err := h.service.ListSpaceInvitations(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	resp := make([]spaceInvitationResponse, 0, len(invitations))
+	for _, inv := range invitations {
+		resp = append(resp, toSpaceInvitationResponse(&inv))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"invitations": resp,
+	})
+}
+
+// DELETE /api/v1/space/{id}/invitations/{invitationId}
+func (h *SpaceHandler) RevokeSpaceInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.service.RevokeSpaceInvitation(r.Context(), userID, r.PathValue("id"), r.PathValue("invitationId"))
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /api/v1/space/invitations/{code}/accept
+func (h *SpaceHandler) AcceptSpaceInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	space, err := h.service.AcceptSpaceInvitation(r.Context(), userID, r.PathValue("code"))
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"spaceId": space.ID,
+	})
+}
+
+func toSpaceMemberResponse(m *service.SpaceMember) spaceMemberResponse {
+	return spaceMemberResponse{
+		UserID:   m.UserID,
+		Role:     m.Role,
+		JoinedAt: m.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toSpaceInvitationResponse(inv *service.SpaceInvitation) spaceInvitationResponse {
+	resp := spaceInvitationResponse{
+		ID:        inv.ID,
+		Code:      inv.Code,
+		MaxUses:   inv.MaxUses,
+		Uses:      inv.Uses,
+		CreatedAt: inv.CreatedAt.Format(time.RFC3339),
+	}
+	if !inv.ExpiresAt.IsZero() {
+		resp.ExpiresAt = inv.ExpiresAt.Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type layoutElementResponse struct {
+	ID        string `json:"id"`
+	ElementID string `json:"elementId"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+}
+
+type layoutDiffResponse struct {
+	Added   []layoutElementResponse `json:"added"`
+	Removed []layoutElementResponse `json:"removed"`
+	Moved   []layoutElementResponse `json:"moved"`
+}
+
+type spaceVersionResponse struct {
+	Version         int                `json:"version"`
+	AuthorID        string             `json:"authorId,omitempty"`
+	Action          string             `json:"action"`
+	RestoredVersion int                `json:"restoredVersion,omitempty"`
+	CreatedAt       string             `json:"createdAt"`
+	Changes         layoutDiffResponse `json:"changes"`
+}
+
+// GET /api/v1/space/{id}/versions
+func (h *SpaceHandler) ListSpaceVersions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	versions, err := h.service.ListSpaceVersions(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	resp := make([]spaceVersionResponse, 0, len(versions))
+	for _, v := range versions {
+		resp = append(resp, spaceVersionResponse{
+			Version:         v.Version,
+			AuthorID:        v.AuthorID,
+			Action:          v.Action,
+			RestoredVersion: v.RestoredVersion,
+			CreatedAt:       v.CreatedAt.Format(time.RFC3339),
+			Changes:         toLayoutDiffResponse(v.Diff),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"versions": resp,
+	})
+}
+
+// GET /api/v1/space/{id}/versions/diff?from=&to=
+func (h *SpaceHandler) DiffSpaceVersions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "invalid from", http.StatusBadRequest)
+		return
+	}
+
+	to, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "invalid to", http.StatusBadRequest)
+		return
+	}
+
+	diff, err := h.service.DiffSpaceVersions(r.Context(), userID, r.PathValue("id"), from, to)
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toLayoutDiffResponse(diff))
+}
+
+// POST /api/v1/space/{id}/versions/{version}/rollback
+func (h *SpaceHandler) RollbackSpace(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil {
+		http.Error(w, "invalid version", http.StatusBadRequest)
+		return
+	}
+
+	v, err := h.service.RollbackSpace(r.Context(), userID, r.PathValue("id"), version)
+	if err != nil {
+		writeSpaceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"version": v.Version,
+	})
+}
+
+func toLayoutDiffResponse(diff *service.LayoutDiff) layoutDiffResponse {
+	return layoutDiffResponse{
+		Added:   toLayoutElementResponses(diff.Added),
+		Removed: toLayoutElementResponses(diff.Removed),
+		Moved:   toLayoutElementResponses(diff.Moved),
+	}
+}
+
+func toLayoutElementResponses(elements []service.LayoutElement) []layoutElementResponse {
+	resp := make([]layoutElementResponse, 0, len(elements))
+	for _, el := range elements {
+		resp = append(resp, layoutElementResponse(el))
+	}
+	return resp
+}
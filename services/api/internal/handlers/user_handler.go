@@ -1,4 +1,4 @@
-package user
+package handlers
 
 import (
 	"encoding/json"
@@ -7,12 +7,12 @@ import (
 	"github.com/vaxxnsh/metaverse/api/internal/service"
 )
 
-type Handler struct {
+type UserHandler struct {
 	service service.Service
 }
 
-func NewHandler(s service.Service) *Handler {
-	return &Handler{service: s}
+func NewUserHandler(s service.Service) *UserHandler {
+	return &UserHandler{service: s}
 }
 
 type createUserRequest struct {
@@ -28,7 +28,7 @@ type userResponse struct {
 }
 
 // POST /users
-func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var req createUserRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -62,7 +62,7 @@ func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
 }
 
 // GET /users?id=123
-func (h *Handler) GetUserByID(w http.ResponseWriter, r *http.Request) {
+func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 	id := r.URL.Query().Get("id")
 	if id == "" {
 		http.Error(w, "missing id", http.StatusBadRequest)
@@ -90,3 +90,63 @@ func (h *Handler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
+
+// DELETE /api/v1/admin/user/{id}
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteUser(r.Context(), r.PathValue("id")); err != nil {
+		switch err {
+		case service.ErrUserNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/v1/admin/user/trash
+func (h *UserHandler) ListDeletedUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.service.ListDeletedUsers(r.Context())
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	type deletedUserResponse struct {
+		userResponse
+		DeletedAt string `json:"deleted_at"`
+	}
+
+	resp := make([]deletedUserResponse, 0, len(users))
+	for _, user := range users {
+		resp = append(resp, deletedUserResponse{
+			userResponse: userResponse{
+				ID:        user.ID,
+				Email:     user.Email,
+				Name:      user.Name,
+				CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			},
+			DeletedAt: user.DeletedAt.Format("2006-01-02T15:04:05Z"),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// POST /api/v1/admin/user/{id}/restore
+func (h *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RestoreUser(r.Context(), r.PathValue("id")); err != nil {
+		switch err {
+		case service.ErrUserNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type ZoneHandler struct {
+	service service.ZoneService
+}
+
+func NewZoneHandler(s service.ZoneService) *ZoneHandler {
+	return &ZoneHandler{service: s}
+}
+
+type zoneRect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// zoneRequest takes either rect or polygon.
+type zoneRequest struct {
+	Name       string          `json:"name"`
+	Rect       *zoneRect       `json:"rect"`
+	Polygon    []service.Point `json:"polygon"`
+	Properties json.RawMessage `json:"properties"`
+	ChatScope  string          `json:"chatScope"`
+	Audio      string          `json:"audio"`
+	MinRole    string          `json:"minRole"`
+}
+
+type zoneResponse struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Shape      string          `json:"shape"`
+	Points     []service.Point `json:"points"`
+	Properties json.RawMessage `json:"properties"`
+	ChatScope  string          `json:"chatScope"`
+	Audio      string          `json:"audio"`
+	MinRole    string          `json:"minRole,omitempty"`
+}
+
+// GET /api/v1/space/{id}/zones
+func (h *ZoneHandler) ListZones(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	zones, err := h.service.ListZones(r.Context(), userID, r.PathValue("id"), r.Header.Get(spacePasswordHeader))
+	if err != nil {
+		writeZoneError(w, err)
+		return
+	}
+
+	resp := make([]zoneResponse, 0, len(zones))
+	for _, z := range zones {
+		resp = append(resp, toZoneResponse(&z))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"zones": resp,
+	})
+}
+
+// POST /api/v1/space/{id}/zones
+func (h *ZoneHandler) CreateZone(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	zone, ok := decodeZone(w, r)
+	if !ok {
+		return
+	}
+
+	created, err := h.service.CreateZone(r.Context(), userID, r.PathValue("id"), zone)
+	if err != nil {
+		writeZoneError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toZoneResponse(created))
+}
+
+// PUT /api/v1/space/{id}/zones/{zoneId}
+func (h *ZoneHandler) UpdateZone(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	zone, ok := decodeZone(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.service.UpdateZone(r.Context(), userID, r.PathValue("id"), r.PathValue("zoneId"), zone)
+	if err != nil {
+		writeZoneError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toZoneResponse(updated))
+}
+
+// DELETE /api/v1/space/{id}/zones/{zoneId}
+func (h *ZoneHandler) DeleteZone(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteZone(r.Context(), userID, r.PathValue("id"), r.PathValue("zoneId")); err != nil {
+		writeZoneError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeZone(w http.ResponseWriter, r *http.Request) (*service.Zone, bool) {
+	var req zoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	zone := &service.Zone{
+		Name:       req.Name,
+		Properties: req.Properties,
+		ChatScope:  req.ChatScope,
+		Audio:      req.Audio,
+		MinRole:    req.MinRole,
+	}
+
+	switch {
+	case req.Rect != nil && req.Polygon == nil:
+		zone.Shape = service.ZoneRect
+		zone.Points = []service.Point{
+			{X: req.Rect.X, Y: req.Rect.Y},
+			{X: req.Rect.X + req.Rect.Width, Y: req.Rect.Y + req.Rect.Height},
+		}
+	case req.Polygon != nil && req.Rect == nil:
+		zone.Shape = service.ZonePolygon
+		zone.Points = req.Polygon
+	default:
+		http.Error(w, "zone needs either rect or polygon", http.StatusBadRequest)
+		return nil, false
+	}
+
+	return zone, true
+}
+
+func toZoneResponse(z *service.Zone) zoneResponse {
+	return zoneResponse{
+		ID:         z.ID,
+		Name:       z.Name,
+		Shape:      z.Shape,
+		Points:     z.Points,
+		Properties: z.Properties,
+		ChatScope:  z.ChatScope,
+		Audio:      z.Audio,
+		MinRole:    z.MinRole,
+	}
+}
+
+func writeZoneError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidZone):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrZoneNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		writeSpaceError(w, err)
+	}
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+)
+
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// New returns an SMTP mailer for addr, or a mailer that only logs who
+// messages go to when addr is empty so local setups work without a mail
+// server.
+func New(addr, from string) Mailer {
+	if addr == "" {
+		return logMailer{}
+	}
+
+	return &smtpMailer{
+		addr: addr,
+		from: from,
+	}
+}
+
+// logMailer only records that a message was sent. Bodies are left out, as
+// they carry secrets such as invitation tokens.
+type logMailer struct{}
+
+func (logMailer) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("mail to %s: %s", to, subject)
+	return nil
+}
+
+type smtpMailer struct {
+	addr string
+	from string
+}
+
+func (m *smtpMailer) Send(ctx context.Context, to, subject, body string) error {
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return fmt.Errorf("mailer: header contains a line break")
+	}
+
+	msg := "From: " + m.from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" + body
+
+	return smtp.SendMail(m.addr, nil, m.from, []string{to}, []byte(msg))
+}
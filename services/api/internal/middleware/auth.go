@@ -1 +1,139 @@
 package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type Claims struct {
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+	Expiry int64  `json:"exp"`
+}
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type contextKey int
+
+const claimsKey contextKey = iota
+
+// ParseToken verifies an HS256 JWT signed with secret and returns its claims.
+func ParseToken(secret, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.UserID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.Expiry != 0 && time.Now().Unix() > claims.Expiry {
+		return nil, ErrInvalidToken
+	}
+
+	return &claims, nil
+}
+
+// UserStore tells whether the user a token was issued to may still use it.
+type UserStore interface {
+	Active(ctx context.Context, userID string) (bool, error)
+}
+
+// Authenticate is ParseToken that also rejects tokens of users deleted since
+// they were issued.
+func Authenticate(ctx context.Context, secret string, users UserStore, token string) (*Claims, error) {
+	claims, err := ParseToken(secret, token)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := users.Active(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// Auth rejects requests without a valid bearer token of an active user and
+// stores the token's claims on the request context.
+func Auth(secret string, users UserStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := Authenticate(r.Context(), secret, users, token)
+			if errors.Is(err, ErrInvalidToken) {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if err != nil {
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), claimsKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func ClaimsFrom(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*Claims)
+	return claims, ok
+}
+
+func UserID(ctx context.Context) (string, bool) {
+	claims, ok := ClaimsFrom(ctx)
+	if !ok {
+		return "", false
+	}
+	return claims.UserID, true
+}
+
+// RequireAdmin must run after Auth and rejects callers without the admin
+// role.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := ClaimsFrom(r.Context())
+		if !ok || claims.Role != "admin" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
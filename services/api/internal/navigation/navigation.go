@@ -0,0 +1,163 @@
+package navigation
+
+import "container/heap"
+
+// maxExpanded bounds how many tiles FindPath explores before giving up, so a
+// request into a huge or sealed-off space stays cheap.
+const maxExpanded = 1 << 16
+
+type Point struct {
+	X int
+	Y int
+}
+
+// Grid counts the obstacles covering each tile of a space, row by row.
+type Grid struct {
+	width   int
+	height  int
+	blocked []uint16
+}
+
+func NewGrid(width, height int) *Grid {
+	return &Grid{
+		width:   width,
+		height:  height,
+		blocked: make([]uint16, width*height),
+	}
+}
+
+// Cover adds delta to every tile of the w by h rectangle at x, y that lies
+// inside the grid.
+func (g *Grid) Cover(x, y, w, h, delta int) {
+	for ty := max(y, 0); ty < min(y+h, g.height); ty++ {
+		for tx := max(x, 0); tx < min(x+w, g.width); tx++ {
+			i := ty*g.width + tx
+			g.blocked[i] = uint16(int(g.blocked[i]) + delta)
+		}
+	}
+}
+
+// Open reports whether x, y is inside the grid and not covered.
+func (g *Grid) Open(x, y int) bool {
+	return x >= 0 && y >= 0 && x < g.width && y < g.height && g.blocked[y*g.width+x] == 0
+}
+
+// nearestRadius bounds how many steps away from its point Nearest looks.
+const nearestRadius = 32
+
+// Nearest returns the tile closest to p, in steps, for which walkable is
+// true, p itself included. It reports false when there is none within
+// nearestRadius.
+func Nearest(p Point, walkable func(x, y int) bool) (Point, bool) {
+	for d := 0; d <= nearestRadius; d++ {
+		for dx := -d; dx <= d; dx++ {
+			dy := d - abs(dx)
+			if walkable(p.X+dx, p.Y+dy) {
+				return Point{X: p.X + dx, Y: p.Y + dy}, true
+			}
+			if dy != 0 && walkable(p.X+dx, p.Y-dy) {
+				return Point{X: p.X + dx, Y: p.Y - dy}, true
+			}
+		}
+	}
+	return Point{}, false
+}
+
+// FindPath runs A* over the four-connected tiles for which walkable is true
+// and returns the steps from start to goal, excluding start. It returns nil
+// when goal cannot be reached, and an empty path when start is the goal.
+func FindPath(start, goal Point, walkable func(x, y int) bool) []Point {
+	if start == goal {
+		return []Point{}
+	}
+	if !walkable(goal.X, goal.Y) {
+		return nil
+	}
+
+	open := &frontier{{Point: start, f: distance(start, goal)}}
+	cost := map[Point]int{start: 0}
+	from := make(map[Point]Point)
+
+	for expanded := 0; open.Len() > 0 && expanded < maxExpanded; expanded++ {
+		cur := heap.Pop(open).(node)
+		if cur.Point == goal {
+			return walkBack(from, start, goal)
+		}
+		if cur.g > cost[cur.Point] {
+			continue
+		}
+
+		for _, d := range [...]Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			next := Point{X: cur.X + d.X, Y: cur.Y + d.Y}
+			if !walkable(next.X, next.Y) {
+				continue
+			}
+
+			g := cur.g + 1
+			if known, ok := cost[next]; ok && known <= g {
+				continue
+			}
+
+			cost[next] = g
+			from[next] = cur.Point
+			heap.Push(open, node{Point: next, g: g, f: g + distance(next, goal)})
+		}
+	}
+
+	return nil
+}
+
+func walkBack(from map[Point]Point, start, goal Point) []Point {
+	var path []Point
+	for p := goal; p != start; p = from[p] {
+		path = append(path, p)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// distance is the Manhattan distance, which never overestimates on a
+// four-connected grid.
+func distance(a, b Point) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+type node struct {
+	Point
+	g int
+	f int
+}
+
+// frontier is a min-heap of nodes ordered by f, breaking ties towards the
+// goal.
+type frontier []node
+
+func (f frontier) Len() int { return len(f) }
+
+func (f frontier) Less(i, j int) bool {
+	if f[i].f != f[j].f {
+		return f[i].f < f[j].f
+	}
+	return f[i].g > f[j].g
+}
+
+func (f frontier) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
+
+func (f *frontier) Push(x any) { *f = append(*f, x.(node)) }
+
+func (f *frontier) Pop() any {
+	old := *f
+	n := old[len(old)-1]
+	*f = old[:len(old)-1]
+	return n
+}
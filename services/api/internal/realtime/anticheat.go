@@ -0,0 +1,91 @@
+package realtime
+
+import (
+	"log"
+	"time"
+)
+
+// Moves are one tile along an axis. A client earns maxStepsPerTick steps
+// every tick and may bank up to maxStepBurst of them, which absorbs moves
+// bunched up by the network without letting it outrun the limit for long.
+const (
+	maxStepsPerTick = 1
+	maxStepBurst    = 4
+)
+
+// violationWindow is how long a client's anti-cheat violations count
+// towards an automatic kick.
+const violationWindow = 10 * time.Second
+
+// DefaultAutoKick is how many violations within the window get a client
+// kicked, unless the server is given another limit.
+const DefaultAutoKick = 20
+
+// Why a move was rejected.
+const (
+	rejectBlocked     = "blocked"
+	rejectNotAdjacent = "not-adjacent"
+	rejectTooFast     = "too-fast"
+	rejectUnreachable = "unreachable"
+)
+
+const autoKickReason = "movement violations"
+
+// checkStep validates a move of c from its authoritative position from to
+// x, y and spends a step on it if it is allowed. It returns why the move is
+// refused, or "" if it is allowed; refused moves cost no steps. Site admins
+// only need a free tile. r.mu must be held.
+func (r *Room) checkStep(c *Client, from point, x, y int) string {
+	d := abs(x-from.X) + abs(y-from.Y)
+	if d == 0 {
+		return ""
+	}
+	if c.admin {
+		if !r.walkable(c.role, x, y) {
+			return rejectBlocked
+		}
+		return ""
+	}
+	if d > 1 {
+		return rejectNotAdjacent
+	}
+
+	c.steps = min(maxStepBurst, c.steps+int(r.ticks-c.stepsAt)*maxStepsPerTick)
+	c.stepsAt = r.ticks
+	if c.steps == 0 {
+		return rejectTooFast
+	}
+	if !r.walkable(c.role, x, y) {
+		return rejectBlocked
+	}
+
+	c.steps--
+	return ""
+}
+
+// violate counts a move that no honest client sends and reports whether c
+// has now reached the server's limit. r.mu must be held.
+func (c *Client) violate(now time.Time) bool {
+	if now.Sub(c.violationsSince) > violationWindow {
+		c.violations = 0
+		c.violationsSince = now
+	}
+	c.violations++
+
+	limit := c.server.autoKick
+	return limit > 0 && c.violations >= limit
+}
+
+// SetAutoKick sets how many movement violations within ten seconds get a
+// client kicked from its space. Zero only rejects the moves.
+func (s *Server) SetAutoKick(violations int) {
+	s.autoKick = violations
+}
+
+// autoKicked disconnects a client caught cheating.
+func (r *Room) autoKicked(c *Client) {
+	log.Printf("anticheat: kicked %s from space %s", c.userID, r.spaceID)
+
+	c.send(EventKicked, sanctionPayload{SpaceID: r.spaceID, Reason: autoKickReason})
+	c.close()
+}
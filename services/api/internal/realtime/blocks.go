@@ -0,0 +1,75 @@
+package realtime
+
+import (
+	"context"
+
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+// BlockStore loads every block and mute a user is part of, in either
+// direction.
+type BlockStore interface {
+	ListInvolving(ctx context.Context, userID string) ([]service.Block, error)
+}
+
+// relations is a connection's view of its block list. It is replaced
+// wholesale when the list changes, never mutated.
+type relations struct {
+	// hidden holds users whose chat and emotes this user does not see.
+	hidden map[string]bool
+
+	// blocked holds users this user cannot exchange direct messages with.
+	blocked map[string]bool
+}
+
+func newRelations(userID string, blocks []service.Block) *relations {
+	rel := &relations{
+		hidden:  make(map[string]bool),
+		blocked: make(map[string]bool),
+	}
+
+	for _, b := range blocks {
+		if b.UserID == userID {
+			rel.hidden[b.TargetID] = true
+			if b.Kind == service.BlockKindBlock {
+				rel.blocked[b.TargetID] = true
+			}
+			continue
+		}
+
+		if b.Kind == service.BlockKindBlock {
+			rel.blocked[b.UserID] = true
+		}
+	}
+
+	return rel
+}
+
+func (r *relations) hides(userID string) bool {
+	return r.hidden[userID]
+}
+
+func (r *relations) blocks(userID string) bool {
+	return r.blocked[userID]
+}
+
+// BlocksChanged reloads the block lists of both users on whichever nodes
+// they are connected to.
+func (s *Server) BlocksChanged(userID, targetID string) {
+	s.reloadRelations(userID, targetID)
+	s.publishUser(userEvent{Kind: userBlocks, UserID: userID, TargetID: targetID})
+}
+
+// reloadRelations reloads the block lists of the users connected here.
+func (s *Server) reloadRelations(userIDs ...string) {
+	for _, id := range userIDs {
+		c := s.client(id)
+		if c == nil {
+			continue
+		}
+
+		if err := c.loadRelations(context.Background()); err != nil {
+			c.sendError("failed to refresh block list")
+		}
+	}
+}
@@ -0,0 +1,374 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/websocket"
+	"github.com/vaxxnsh/metaverse/api/wire"
+)
+
+const writeWait = 10 * time.Second
+
+// Client is a user's session in the real-time server. It starts out on a
+// single WebSocket connection, and until it joins a space it has no user or
+// room. Once joined it outlives a dropped connection for the resume grace
+// period, without one, until a new connection resumes it.
+type Client struct {
+	server *Server
+
+	// writeMu guards out and session. out is c's connection, nil while c
+	// waits to be resumed.
+	writeMu sync.Mutex
+	out     *outbox
+	session session
+
+	// actions serializes the read loop, portal travel and disconnecting,
+	// the only things that change c's room.
+	actions sync.Mutex
+
+	userID string
+	room   *Room
+
+	// node is the node a remote client is connected to, and empty for
+	// clients connected here.
+	node string
+
+	// role is the user's role in room's space, "" for non-members.
+	role string
+
+	// admin is set for site admins, who may move to any free tile of the
+	// space at once.
+	admin bool
+
+	// stateDeltas is set when c asked for state deltas rather than the
+	// movement events of each user.
+	stateDeltas atomic.Bool
+
+	// admitted is set once c has a place in room rather than waiting in
+	// its queue, and admittedAt is when it got it from the node it is
+	// connected to. admittedAt is guarded by room.mu.
+	admitted   atomic.Bool
+	admittedAt time.Time
+
+	// spawnMoved is set when c was placed off the spawn point it asked
+	// for, which was blocked.
+	spawnMoved bool
+
+	// x, y, path, nextStep, steps, stepsAt, violations, violationsSince,
+	// zones and chatZone are guarded by room.mu.
+	x int
+	y int
+
+	// path holds the steps left of a walk, the next of which is taken on
+	// tick nextStep of the room.
+	path     []point
+	nextStep uint64
+
+	// steps is how many moves c has left, as of tick stepsAt of the room,
+	// and violations how many cheating moves it sent since
+	// violationsSince.
+	steps           int
+	stepsAt         uint64
+	violations      int
+	violationsSince time.Time
+
+	// zones holds the ids of the zones c stands in, and chatZone the one
+	// its chat is confined to, if any.
+	zones    []string
+	chatZone string
+
+	relations atomic.Pointer[relations]
+
+	// lastActive is when c last sent anything, in Unix nanoseconds, and
+	// idle is set once its space has been told it went quiet.
+	lastActive atomic.Int64
+	idle       atomic.Bool
+
+	// mutedUntil is when a moderator's mute in the current space ends, and
+	// floodMutedUntil when the one for flooding does, in Unix nanoseconds.
+	mutedUntil      atomic.Int64
+	floodMutedUntil atomic.Int64
+
+	// limits rate-limits the messages c sends. It is guarded by actions.
+	limits limiter
+}
+
+func newClient(s *Server, conn *websocket.Conn) *Client {
+	c := &Client{
+		server: s,
+		out:    newOutbox(s, conn),
+	}
+	c.relations.Store(newRelations("", nil))
+	return c
+}
+
+// readLoop handles the messages of c's connection. A connection that resumes
+// another session goes on speaking for that session's client.
+func (c *Client) readLoop() {
+	out := c.out
+	conn, codec := out.conn, out.codec
+
+	defer func() {
+		c.actions.Lock()
+		defer c.actions.Unlock()
+
+		c.server.disconnect(c, out)
+	}()
+
+	// A peer that sends nothing, not even pongs, for the ping timeout is
+	// dead. Pongs run on this goroutine, so they go to whichever client
+	// the connection speaks for by then.
+	conn.SetReadDeadline(time.Now().Add(out.pingTimeout))
+	conn.SetPongHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(out.pingTimeout))
+		if rtt, ok := out.pong(data); ok {
+			c.notify(EventLatency, latencyPayload{RTT: uint32(rtt.Milliseconds())})
+		}
+		return nil
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			// Closing the connection properly is leaving; only a dropped
+			// one can be resumed.
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				c.writeMu.Lock()
+				if c.out == out {
+					c.session.closed = true
+				}
+				c.writeMu.Unlock()
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(out.pingTimeout))
+
+		// Messages over c's rate limits are dropped, resumes and ones that
+		// make no sense included.
+		msg, err := codec.Decode(data)
+		if err != nil {
+			c.actions.Lock()
+			if c.allow(messageInvalid) {
+				if errors.Is(err, wire.ErrUnknownType) {
+					c.sendError("unknown message type")
+				} else {
+					c.sendError("invalid message")
+				}
+			}
+			c.actions.Unlock()
+			continue
+		}
+
+		c.actions.Lock()
+		if !c.allow(msg.Type) {
+			c.actions.Unlock()
+			continue
+		}
+
+		if msg.Type == MessageResume {
+			c.actions.Unlock()
+			if resumed := c.server.resume(c, out, msg.Payload); resumed != nil {
+				c = resumed
+			}
+			continue
+		}
+
+		c.active()
+		c.handle(msg)
+		c.actions.Unlock()
+	}
+}
+
+func (c *Client) handle(msg Message) {
+	if msg.Type == MessageJoin {
+		var p joinPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			c.sendError("invalid join payload")
+			return
+		}
+		c.server.join(c, p)
+		return
+	}
+
+	if c.room == nil {
+		c.sendError("join a space first")
+		return
+	}
+
+	if !c.admitted.Load() {
+		c.sendError("waiting for a place in the space")
+		return
+	}
+
+	switch msg.Type {
+	case MessageMove:
+		var p movePayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			c.sendError("invalid move payload")
+			return
+		}
+		c.room.move(c, p.X, p.Y)
+
+	case MessageMoveTo:
+		var p movePayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			c.sendError("invalid move-to payload")
+			return
+		}
+		c.moveTo(p.X, p.Y)
+
+	case MessageChat:
+		var p chatPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil || p.Message == "" {
+			c.sendError("invalid chat payload")
+			return
+		}
+		if utf8.RuneCountInString(p.Message) > maxMessageLength {
+			c.sendError("message is too long")
+			return
+		}
+		if c.muted() {
+			c.sendError("you are muted in this space")
+			return
+		}
+		c.room.chat(c, p.Message)
+
+	case MessageEmote:
+		var p emotePayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil || p.Emote == "" {
+			c.sendError("invalid emote payload")
+			return
+		}
+		if utf8.RuneCountInString(p.Emote) > maxMessageLength {
+			c.sendError("emote is too long")
+			return
+		}
+		c.room.emote(c, p.Emote)
+
+	case MessageDirectMessage:
+		var p directMessagePayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil || p.Message == "" {
+			c.sendError("invalid direct message payload")
+			return
+		}
+		if utf8.RuneCountInString(p.Message) > maxMessageLength {
+			c.sendError("message is too long")
+			return
+		}
+		if c.muted() {
+			c.sendError("you are muted in this space")
+			return
+		}
+		c.room.directMessage(c, p.UserID, p.Message)
+
+	case MessageKick, MessageMute, MessageBan:
+		var p moderationPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil || p.UserID == "" {
+			c.sendError("invalid moderation payload")
+			return
+		}
+		c.moderate(msg.Type, p)
+
+	default:
+		c.sendError("unknown message type")
+	}
+}
+
+func (c *Client) loadRelations(ctx context.Context) error {
+	blocks, err := c.server.blocks.ListInvolving(ctx, c.userID)
+	if err != nil {
+		return err
+	}
+
+	c.relations.Store(newRelations(c.userID, blocks))
+	return nil
+}
+
+// hides reports whether this client should not see chat or emotes from
+// userID.
+func (c *Client) hides(userID string) bool {
+	return c.relations.Load().hides(userID)
+}
+
+func (c *Client) blocks(userID string) bool {
+	return c.relations.Load().blocks(userID)
+}
+
+func (c *Client) send(eventType string, payload any) {
+	c.queue(eventType, payload, outgoing{})
+}
+
+// sendDelta sends d as what c saw change by tick. A delta still waiting to
+// be written takes d in instead. A client that did not ask for state deltas
+// is sent the movement, enter-view and leave-view events of the users in d
+// instead, and nothing about its own moves.
+func (c *Client) sendDelta(d *stateDelta, tick uint64) {
+	if !c.stateDeltas.Load() {
+		for _, u := range d.updates {
+			if u.at.UserID == c.userID {
+				continue
+			}
+
+			switch u.kind {
+			case viewMoved:
+				c.queue(EventMovement, u.at, outgoing{mover: u.at.UserID})
+			case viewEntered:
+				c.send(EventEnterView, u.at)
+			case viewLeft:
+				c.send(EventLeaveView, userLeftPayload{UserID: u.at.UserID})
+			}
+		}
+		return
+	}
+
+	c.queue(EventStateDelta, d.payload(tick), outgoing{delta: d, tick: tick})
+}
+
+// queue numbers an event for c's session and hands it to c's connection as
+// out, if c has one.
+func (c *Client) queue(eventType string, payload any, out outgoing) {
+	if c.node != "" {
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("encode %s: %v", eventType, err)
+		return
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	out.msg = c.session.record(Message{Type: eventType, Payload: data})
+	if c.out != nil {
+		c.out.push(out)
+	}
+}
+
+// close ends c's session for good. A connected client is disconnected once
+// the events sent to it so far are written; one waiting to be resumed
+// leaves its room right away.
+func (c *Client) close() {
+	c.writeMu.Lock()
+	c.session.closed = true
+	out := c.out
+	c.writeMu.Unlock()
+
+	if out != nil {
+		out.finish()
+		return
+	}
+	go c.server.expire(c)
+}
+
+func (c *Client) sendError(message string) {
+	c.send(EventError, errorPayload{Message: message})
+}
@@ -0,0 +1,458 @@
+package realtime
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+// Broker carries room events between the nodes of a cluster. Every node
+// with a room live subscribes to the room's topic, and sees the users
+// connected to other nodes in it as remote clients.
+type Broker interface {
+	Publish(ctx context.Context, topic string, msg []byte) error
+
+	// Subscribe calls handler with the messages published to topic, one
+	// at a time, until the returned function is called.
+	Subscribe(topic string, handler func(msg []byte)) func()
+}
+
+// SetBroker shares rooms with the other nodes using b. Without a broker a
+// node only knows its own clients.
+func (s *Server) SetBroker(b Broker) {
+	s.broker = b
+	b.Subscribe(usersTopic, s.userEvent)
+}
+
+// Kinds of room event.
+const (
+	roomSync          = "sync"
+	roomPresent       = "present"
+	roomJoined        = "joined"
+	roomMoved         = "moved"
+	roomLeft          = "left"
+	roomChat          = "chat"
+	roomEmote         = "emote"
+	roomDirectMessage = "direct-message"
+	roomIdle          = "idle"
+	roomActive        = "active"
+
+	// The changes made through the API to a space with a room live on any
+	// node.
+	roomKicked          = "kicked"
+	roomSanctions       = "sanctions"
+	roomObstacleAdded   = "obstacle-added"
+	roomObstacleRemoved = "obstacle-removed"
+	roomElements        = "elements"
+	roomPortals         = "portals"
+	roomZones           = "zones"
+	roomAccess          = "access"
+	roomRateLimits      = "rate-limits"
+	roomCapacity        = "capacity"
+	roomRole            = "role"
+)
+
+// usersTopic carries the changes made through the API to users, who may be
+// connected to any node.
+const usersTopic = "users"
+
+// Kinds of user event.
+const (
+	userBlocks = "blocks"
+)
+
+// movedPerEvent bounds the users in one moved event, and
+// elementsPerEvent the elements in one elements event, so that a busy tick
+// or a large layout change still fits in a broker message.
+const (
+	movedPerEvent    = 64
+	elementsPerEvent = 48
+)
+
+// roomEvent is what a node tells the others about its clients in a room. A
+// new room asks the others with a sync, which they answer by listing their
+// clients as present. The users of a node that goes away without a word
+// stay in the other nodes' rooms until those are dropped.
+//
+// At is when the user of a joined event was admitted, which settles who
+// keeps a place when nodes fill the last ones at once.
+type roomEvent struct {
+	Node       string                   `json:"node"`
+	Kind       string                   `json:"kind"`
+	Users      []userPosition           `json:"users,omitempty"`
+	UserID     string                   `json:"userId,omitempty"`
+	To         string                   `json:"to,omitempty"`
+	ChatZone   string                   `json:"chatZone,omitempty"`
+	Text       string                   `json:"text,omitempty"`
+	At         time.Time                `json:"at,omitzero"`
+	Obstacle   *service.Obstacle        `json:"obstacle,omitempty"`
+	Version    int                      `json:"version,omitempty"`
+	Diff       *service.LayoutDiff      `json:"diff,omitempty"`
+	RateLimits []service.SpaceRateLimit `json:"rateLimits,omitempty"`
+	Capacity   int                      `json:"capacity,omitempty"`
+}
+
+// userEvent is what a node tells the others about a change to a user.
+type userEvent struct {
+	Node     string `json:"node"`
+	Kind     string `json:"kind"`
+	UserID   string `json:"userId"`
+	TargetID string `json:"targetId,omitempty"`
+}
+
+func roomTopic(spaceID string) string {
+	return "room:" + spaceID
+}
+
+// runRoom ticks r until it is dropped, keeping it in step with the other
+// nodes that have it live meanwhile.
+func (s *Server) runRoom(r *Room) {
+	if s.broker != nil {
+		unsubscribe := s.broker.Subscribe(roomTopic(r.spaceID), func(msg []byte) {
+			s.roomEvent(r, msg)
+		})
+		defer unsubscribe()
+
+		s.publish(r, roomEvent{Kind: roomSync})
+	}
+
+	r.run(s.tick)
+}
+
+// publish sends e to the other nodes with r live. No lock may be held, as
+// the broker may have to wait.
+func (s *Server) publish(r *Room, e roomEvent) {
+	s.publishSpace(r.spaceID, e)
+}
+
+// publishSpace sends e to the nodes with the room of spaceID live, whether
+// or not it is live here.
+func (s *Server) publishSpace(spaceID string, e roomEvent) {
+	if s.broker == nil {
+		return
+	}
+	e.Node = s.node
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("encode room event %s: %v", e.Kind, err)
+		return
+	}
+
+	if err := s.broker.Publish(context.Background(), roomTopic(spaceID), data); err != nil {
+		log.Printf("publish room event %s: %v", e.Kind, err)
+	}
+}
+
+// publishUser sends e to every other node.
+func (s *Server) publishUser(e userEvent) {
+	if s.broker == nil {
+		return
+	}
+	e.Node = s.node
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("encode user event %s: %v", e.Kind, err)
+		return
+	}
+
+	if err := s.broker.Publish(context.Background(), usersTopic, data); err != nil {
+		log.Printf("publish user event %s: %v", e.Kind, err)
+	}
+}
+
+func (s *Server) userEvent(msg []byte) {
+	var e userEvent
+	if err := json.Unmarshal(msg, &e); err != nil {
+		log.Println("decode user event:", err)
+		return
+	}
+	if e.Node == s.node {
+		return
+	}
+
+	switch e.Kind {
+	case userBlocks:
+		s.reloadRelations(e.UserID, e.TargetID)
+	}
+}
+
+// publishMoved tells the other nodes where the users that moved this tick
+// stand.
+func (s *Server) publishMoved(r *Room, moved []userPosition) {
+	for users := range slices.Chunk(moved, movedPerEvent) {
+		s.publish(r, roomEvent{Kind: roomMoved, Users: users})
+	}
+}
+
+func (s *Server) roomEvent(r *Room, msg []byte) {
+	var e roomEvent
+	if err := json.Unmarshal(msg, &e); err != nil {
+		log.Println("decode room event:", err)
+		return
+	}
+	if e.Node == s.node {
+		return
+	}
+
+	switch e.Kind {
+	case roomSync:
+		if users := r.localUsers(); len(users) > 0 {
+			s.publish(r, roomEvent{Kind: roomPresent, Users: users})
+		}
+	case roomJoined, roomPresent:
+		for _, u := range e.Users {
+			s.remoteJoined(r, e.Node, u, e.At, e.Kind == roomJoined)
+		}
+	case roomMoved:
+		r.remoteMoved(e.Users)
+	case roomLeft:
+		s.remoteLeft(r, e.UserID)
+	case roomChat:
+		r.broadcast(nil, EventChat, userChatPayload{UserID: e.UserID, Message: e.Text}, func(to *Client) bool {
+			return to.chatZone != e.ChatZone || to.hides(e.UserID)
+		})
+	case roomEmote:
+		r.broadcast(nil, EventEmote, userEmotePayload{UserID: e.UserID, Emote: e.Text}, func(to *Client) bool {
+			return to.hides(e.UserID)
+		})
+	case roomDirectMessage:
+		r.remoteDirectMessage(e.UserID, e.To, e.Text)
+	case roomIdle, roomActive:
+		r.remoteActivity(e.UserID, e.Kind == roomIdle)
+	case roomKicked:
+		s.kick(r.spaceID, e.UserID, e.Text)
+	case roomSanctions:
+		s.refreshSanctions(r.spaceID, e.UserID)
+	case roomObstacleAdded, roomObstacleRemoved:
+		if e.Obstacle != nil {
+			r.changeObstacle(*e.Obstacle, e.Kind == roomObstacleAdded)
+		}
+	case roomElements:
+		if e.Diff != nil {
+			r.elementsChanged(e.Version, e.Diff)
+		}
+	case roomPortals:
+		s.reloadPortals(r)
+	case roomZones:
+		s.reloadZones(r)
+	case roomAccess:
+		go s.recheckAccess(r.spaceID, e.UserID)
+	case roomRateLimits:
+		go r.setRateLimits(e.RateLimits)
+	case roomCapacity:
+		s.resize(r, e.Capacity)
+	case roomRole:
+		go s.changeRole(r.spaceID, e.UserID, e.Text)
+	}
+}
+
+// newRemoteClient stands for a user connected to node, standing at u in r
+// since at. Whatever is sent to it is for node to deliver, so it is dropped
+// here.
+func (s *Server) newRemoteClient(node string, r *Room, u userPosition, at time.Time) *Client {
+	c := &Client{
+		server:     s,
+		node:       node,
+		userID:     u.UserID,
+		room:       r,
+		x:          u.X,
+		y:          u.Y,
+		admittedAt: at,
+	}
+	c.relations.Store(newRelations("", nil))
+	c.session.closed = true
+	return c
+}
+
+// remoteJoined adds a user connected to another node, admitted there at
+// at, to r. A user already known is only moved. A user who joined another
+// node while connected here has left this node, as a user may only be
+// connected once. If the join overfills r, because nodes gave out its last
+// places at once, the clients connected here that were admitted last go
+// back to the head of the queue; the other nodes do the same with theirs.
+func (s *Server) remoteJoined(r *Room, node string, u userPosition, at time.Time, joined bool) {
+	r.mu.Lock()
+	existing := r.clients[u.UserID]
+	if existing != nil && (existing.node != "" || !joined) {
+		if existing.node != "" {
+			r.inputs = append(r.inputs, input{client: existing, x: u.X, y: u.Y, teleport: true})
+		}
+		r.mu.Unlock()
+		return
+	}
+
+	a := r.admit(s.newRemoteClient(node, r, u, at))
+
+	var bumped []*Client
+	var bumpedWatchers [][]*Client
+	if joined {
+		bumped, bumpedWatchers = r.bump()
+	}
+	r.mu.Unlock()
+
+	if existing != nil {
+		existing.close()
+	}
+
+	sendAll(a.watchers, EventUserJoined, u)
+	r.announceZones(a.zones)
+
+	s.bumped(r, bumped, bumpedWatchers)
+}
+
+// CapacityChanged applies spaceID's new occupancy limit to its room
+// wherever one is live.
+func (s *Server) CapacityChanged(spaceID string, capacity int) {
+	if room := s.room(spaceID); room != nil {
+		s.resize(room, capacity)
+	}
+	s.publishSpace(spaceID, roomEvent{Kind: roomCapacity, Capacity: capacity})
+}
+
+// resize sets r's capacity. Waiting clients are let into the places it
+// gained, and the clients connected here beyond a lower one go back to the
+// head of the queue, as every node does with its own.
+func (s *Server) resize(r *Room, capacity int) {
+	r.mu.Lock()
+	r.capacity = capacity
+	admitted := r.fill()
+	bumped, bumpedWatchers := r.bump()
+	r.mu.Unlock()
+
+	for _, a := range admitted {
+		s.admitted(a)
+	}
+	if len(admitted) > 0 {
+		r.sendQueuePositions()
+	}
+	s.bumped(r, bumped, bumpedWatchers)
+}
+
+// bump puts the clients connected here that hold a place beyond r's
+// capacity back at the head of the queue. It returns them with the users
+// that had each in view. r.mu must be held.
+func (r *Room) bump() ([]*Client, [][]*Client) {
+	var bumped []*Client
+	var watchers [][]*Client
+	for _, c := range r.overflow() {
+		bumped = append(bumped, c)
+		watchers = append(watchers, r.requeue(c))
+	}
+	return bumped, watchers
+}
+
+// bumped tells everyone that the clients bump put back in the queue have
+// left, and the queue where it now stands.
+func (s *Server) bumped(r *Room, clients []*Client, watchers [][]*Client) {
+	for i, c := range clients {
+		sendAll(watchers[i], EventUserLeft, userLeftPayload{UserID: c.userID})
+		s.publish(r, roomEvent{Kind: roomLeft, UserID: c.userID})
+	}
+	if len(clients) > 0 {
+		r.sendQueuePositions()
+	}
+}
+
+// overflow returns the clients connected here that hold a place beyond
+// r's capacity. Places go to whoever was admitted first, ties going to the
+// lower user id, so that every node picks the same clients. r.mu must be
+// held.
+func (r *Room) overflow() []*Client {
+	if len(r.clients) <= r.capacity {
+		return nil
+	}
+
+	clients := slices.Collect(maps.Values(r.clients))
+	slices.SortFunc(clients, func(a, b *Client) int {
+		return cmp.Or(a.admittedAt.Compare(b.admittedAt), cmp.Compare(a.userID, b.userID))
+	})
+
+	var local []*Client
+	for _, c := range clients[r.capacity:] {
+		if c.node == "" {
+			local = append(local, c)
+		}
+	}
+	return local
+}
+
+// requeue takes c's place in r away and puts it at the head of the queue,
+// returning the users that had c in view. r.mu must be held.
+func (r *Room) requeue(c *Client) []*Client {
+	watchers := r.watching(c.x, c.y, c)
+	delete(r.clients, c.userID)
+	r.view.remove(c, c.x, c.y)
+	delete(r.wide, c)
+	c.admitted.Store(false)
+
+	r.queue = slices.Insert(r.queue, 0, queued{client: c, priority: arrivalPriority})
+	return watchers
+}
+
+// remoteMoved moves users connected to other nodes on r's next tick.
+func (r *Room) remoteMoved(users []userPosition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range users {
+		if c := r.clients[u.UserID]; c != nil && c.node != "" {
+			r.inputs = append(r.inputs, input{client: c, x: u.X, y: u.Y, teleport: true})
+		}
+	}
+}
+
+// remoteLeft takes a user connected to another node out of r.
+func (s *Server) remoteLeft(r *Room, userID string) {
+	r.mu.RLock()
+	c := r.clients[userID]
+	r.mu.RUnlock()
+
+	if c == nil || c.node == "" {
+		return
+	}
+
+	admitted, watchers, _ := r.remove(c)
+	sendAll(watchers, EventUserLeft, userLeftPayload{UserID: userID})
+
+	for _, a := range admitted {
+		s.admitted(a)
+	}
+	r.sendQueuePositions()
+}
+
+// remoteDirectMessage delivers a direct message from another node, unless
+// the recipient has blocked or muted the sender.
+func (r *Room) remoteDirectMessage(fromUserID, toUserID, message string) {
+	r.mu.RLock()
+	to := r.clients[toUserID]
+	r.mu.RUnlock()
+
+	if to == nil || to.node != "" || to.blocks(fromUserID) || to.hides(fromUserID) {
+		return
+	}
+
+	to.send(EventDirectMessage, userChatPayload{UserID: fromUserID, Message: message})
+}
+
+// localUsers returns where the admitted clients connected to this node
+// stand.
+func (r *Room) localUsers() []userPosition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var users []userPosition
+	for _, c := range r.clients {
+		if c.node == "" {
+			users = append(users, userPosition{UserID: c.userID, X: c.x, Y: c.y})
+		}
+	}
+	return users
+}
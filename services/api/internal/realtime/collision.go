@@ -0,0 +1,100 @@
+package realtime
+
+import (
+	"context"
+	"slices"
+
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+// ObstacleStore loads the static elements placed in a space.
+type ObstacleStore interface {
+	ListObstacles(ctx context.Context, spaceID string) ([]service.Obstacle, error)
+}
+
+// walkable reports whether a user with role may stand at x, y: inside the
+// space, off every obstacle and outside zones closed to the role. r.mu must
+// be held.
+func (r *Room) walkable(role string, x, y int) bool {
+	return r.grid.Open(x, y) && r.allows(role, x, y)
+}
+
+// addObstacle blocks the tiles under o. Adding an obstacle twice has no
+// effect. r.mu must be held for writing.
+func (r *Room) addObstacle(o service.Obstacle) {
+	if _, ok := r.obstacles[o.ID]; ok {
+		return
+	}
+	r.obstacles[o.ID] = o
+	r.grid.Cover(o.X, o.Y, o.Width, o.Height, 1)
+}
+
+// removeObstacle unblocks the tiles the obstacle with o's id was added at,
+// unless another obstacle still covers them. r.mu must be held for writing.
+func (r *Room) removeObstacle(o service.Obstacle) {
+	added, ok := r.obstacles[o.ID]
+	if !ok {
+		return
+	}
+	delete(r.obstacles, o.ID)
+	r.grid.Cover(added.X, added.Y, added.Width, added.Height, -1)
+}
+
+// ObstacleAdded blocks o in its space's room if one is live. Users already
+// standing under it may still walk off.
+func (s *Server) ObstacleAdded(o service.Obstacle) {
+	if room := s.room(o.SpaceID); room != nil {
+		room.changeObstacle(o, true)
+	}
+	s.publishSpace(o.SpaceID, roomEvent{Kind: roomObstacleAdded, Obstacle: &o})
+}
+
+// ObstacleRemoved unblocks o in its space's room if one is live.
+func (s *Server) ObstacleRemoved(o service.Obstacle) {
+	if room := s.room(o.SpaceID); room != nil {
+		room.changeObstacle(o, false)
+	}
+	s.publishSpace(o.SpaceID, roomEvent{Kind: roomObstacleRemoved, Obstacle: &o})
+}
+
+// changeObstacle adds o to r, or removes it.
+func (r *Room) changeObstacle(o service.Obstacle, added bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if added {
+		r.addObstacle(o)
+	} else {
+		r.removeObstacle(o)
+	}
+}
+
+// ElementsChanged tells everyone in the space's room, if one is live, that
+// its layout moved on to version. The other nodes are told a part of diff
+// at a time, so that each part fits in a broker message.
+func (s *Server) ElementsChanged(spaceID string, version int, diff *service.LayoutDiff) {
+	if room := s.room(spaceID); room != nil {
+		room.elementsChanged(version, diff)
+	}
+
+	for added := range slices.Chunk(diff.Added, elementsPerEvent) {
+		s.publishSpace(spaceID, roomEvent{Kind: roomElements, Version: version, Diff: &service.LayoutDiff{Added: added}})
+	}
+	for removed := range slices.Chunk(diff.Removed, elementsPerEvent) {
+		s.publishSpace(spaceID, roomEvent{Kind: roomElements, Version: version, Diff: &service.LayoutDiff{Removed: removed}})
+	}
+	for moved := range slices.Chunk(diff.Moved, elementsPerEvent) {
+		s.publishSpace(spaceID, roomEvent{Kind: roomElements, Version: version, Diff: &service.LayoutDiff{Moved: moved}})
+	}
+}
+
+// elementsChanged tells everyone in r that its layout moved on to version.
+func (r *Room) elementsChanged(version int, diff *service.LayoutDiff) {
+	r.broadcast(nil, EventElementsChanged, elementsChangedPayload{
+		SpaceID: r.spaceID,
+		Version: version,
+		Added:   diff.Added,
+		Removed: diff.Removed,
+		Moved:   diff.Moved,
+	}, nil)
+}
@@ -0,0 +1,279 @@
+package realtime
+
+// viewDistance is how far a client that asked for state deltas sees other
+// users, in tiles along either axis. Movement, joins and leaves only reach
+// such clients within view. Clients that did not ask for them see the whole
+// room, as they did before interest management.
+const viewDistance = 16
+
+type cell struct {
+	x int
+	y int
+}
+
+// interestGrid buckets the admitted clients of a room into square cells one
+// view distance wide, so everyone in view of a point is in the nine cells
+// around it.
+type interestGrid map[cell]map[*Client]struct{}
+
+func cellOf(x, y int) cell {
+	return cell{x: x / viewDistance, y: y / viewDistance}
+}
+
+func (g interestGrid) add(c *Client, x, y int) {
+	k := cellOf(x, y)
+	if g[k] == nil {
+		g[k] = make(map[*Client]struct{})
+	}
+	g[k][c] = struct{}{}
+}
+
+func (g interestGrid) remove(c *Client, x, y int) {
+	k := cellOf(x, y)
+	delete(g[k], c)
+	if len(g[k]) == 0 {
+		delete(g, k)
+	}
+}
+
+// near returns the clients in view of x, y other than except.
+func (g interestGrid) near(x, y int, except *Client) []*Client {
+	var found []*Client
+
+	center := cellOf(x, y)
+	for cy := center.y - 1; cy <= center.y+1; cy++ {
+		for cx := center.x - 1; cx <= center.x+1; cx++ {
+			for c := range g[cell{x: cx, y: cy}] {
+				if c != except && inView(x, y, c.x, c.y) {
+					found = append(found, c)
+				}
+			}
+		}
+	}
+
+	return found
+}
+
+func inView(ax, ay, bx, by int) bool {
+	return abs(ax-bx) <= viewDistance && abs(ay-by) <= viewDistance
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// seesAll reports whether c sees the whole room rather than the users in
+// view: it is connected here and did not ask for state deltas.
+func (c *Client) seesAll() bool {
+	return c.node == "" && !c.stateDeltas.Load()
+}
+
+// watching returns the clients that see a user at x, y other than except:
+// those in view and those that see the whole room. r.mu must be held.
+func (r *Room) watching(x, y int, except *Client) []*Client {
+	watchers := r.view.near(x, y, except)
+	for c := range r.wide {
+		if c != except && !inView(x, y, c.x, c.y) {
+			watchers = append(watchers, c)
+		}
+	}
+	return watchers
+}
+
+// seenBy returns where the users c sees stand. r.mu must be held.
+func (r *Room) seenBy(c *Client) []userPosition {
+	users := make([]userPosition, 0)
+	if c.seesAll() {
+		for _, other := range r.clients {
+			if other.userID != c.userID {
+				users = append(users, userPosition{UserID: other.userID, X: other.x, Y: other.y})
+			}
+		}
+		return users
+	}
+
+	for _, other := range r.view.near(c.x, c.y, c) {
+		users = append(users, userPosition{UserID: other.userID, X: other.x, Y: other.y})
+	}
+	return users
+}
+
+// viewChange is what a move changed around the mover: who saw it move,
+// who it came into view of and who lost sight of it, and who came into or
+// went out of its own view and where they stand.
+type viewChange struct {
+	watchers []*Client
+	entered  []*Client
+	left     []*Client
+	seen     []userPosition
+	unseen   []*Client
+}
+
+// changeView moves c to x, y in the grid and returns the view changes. r.mu
+// must be held for writing.
+func (r *Room) changeView(c *Client, x, y int) viewChange {
+	before := make(map[*Client]bool)
+	for _, other := range r.view.near(c.x, c.y, c) {
+		before[other] = true
+	}
+
+	r.view.remove(c, c.x, c.y)
+	c.x, c.y = x, y
+	r.view.add(c, x, y)
+
+	// Those that see the whole room watch every move; the rest see c as
+	// it comes into and goes out of their view, as c sees them unless it
+	// sees the whole room itself.
+	var ch viewChange
+	for other := range r.wide {
+		if other != c {
+			ch.watchers = append(ch.watchers, other)
+		}
+	}
+
+	for _, other := range r.view.near(x, y, c) {
+		if before[other] {
+			delete(before, other)
+			if !other.seesAll() {
+				ch.watchers = append(ch.watchers, other)
+			}
+			continue
+		}
+		if !other.seesAll() {
+			ch.entered = append(ch.entered, other)
+		}
+		if !c.seesAll() {
+			ch.seen = append(ch.seen, userPosition{UserID: other.userID, X: other.x, Y: other.y})
+		}
+	}
+
+	for other := range before {
+		if !other.seesAll() {
+			ch.left = append(ch.left, other)
+		}
+		if !c.seesAll() {
+			ch.unseen = append(ch.unseen, other)
+		}
+	}
+
+	return ch
+}
+
+// record notes c's move in the deltas of those around it, and in c's own
+// as confirmation along with who came into or went out of its view. r.mu
+// must still be held.
+func (ch viewChange) record(c *Client, ds deltas) {
+	at := userPosition{UserID: c.userID, X: c.x, Y: c.y}
+	ds.of(c).update(viewMoved, at)
+
+	for _, other := range ch.watchers {
+		ds.of(other).update(viewMoved, at)
+	}
+
+	for _, other := range ch.entered {
+		ds.of(other).update(viewEntered, at)
+	}
+
+	for _, other := range ch.left {
+		ds.of(other).update(viewLeft, at)
+	}
+
+	for _, u := range ch.seen {
+		ds.of(c).update(viewEntered, u)
+	}
+
+	for _, other := range ch.unseen {
+		ds.of(c).update(viewLeft, userPosition{UserID: other.userID})
+	}
+}
+
+type viewKind int
+
+const (
+	viewNone viewKind = iota
+	viewMoved
+	viewEntered
+	viewLeft
+)
+
+type viewUpdate struct {
+	kind viewKind
+	at   userPosition
+}
+
+// stateDelta is what one client learns in a tick about the users around
+// it, one update per user.
+type stateDelta struct {
+	index   map[string]int
+	updates []viewUpdate
+}
+
+type deltas map[*Client]*stateDelta
+
+func (ds deltas) of(c *Client) *stateDelta {
+	d := ds[c]
+	if d == nil {
+		d = &stateDelta{index: make(map[string]int)}
+		ds[c] = d
+	}
+	return d
+}
+
+// update folds a change into what the client already learns this tick, so
+// that it ends up with how things stand at the end of the tick: a user that
+// came into view and left again was never seen, and one that left and came
+// back only moved.
+func (d *stateDelta) update(kind viewKind, at userPosition) {
+	i, ok := d.index[at.UserID]
+	if !ok {
+		d.index[at.UserID] = len(d.updates)
+		d.updates = append(d.updates, viewUpdate{kind: kind, at: at})
+		return
+	}
+
+	u := &d.updates[i]
+	switch {
+	case u.kind == viewEntered && kind == viewLeft:
+		u.kind = viewNone
+	case u.kind == viewLeft && kind == viewEntered:
+		u.kind = viewMoved
+	case u.kind == viewEntered && kind == viewMoved:
+	default:
+		u.kind = kind
+	}
+	u.at = at
+}
+
+func (d *stateDelta) empty() bool {
+	for _, u := range d.updates {
+		if u.kind != viewNone {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *stateDelta) payload(tick uint64) stateDeltaPayload {
+	p := stateDeltaPayload{Tick: tick}
+	for _, u := range d.updates {
+		switch u.kind {
+		case viewMoved:
+			p.Moved = append(p.Moved, u.at)
+		case viewEntered:
+			p.Entered = append(p.Entered, u.at)
+		case viewLeft:
+			p.Left = append(p.Left, u.at.UserID)
+		}
+	}
+	return p
+}
+
+// sendAll sends an event to each of clients.
+func sendAll(clients []*Client, eventType string, payload any) {
+	for _, c := range clients {
+		c.send(eventType, payload)
+	}
+}
@@ -0,0 +1,210 @@
+package realtime
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultPingInterval is how often connections are pinged, and
+// DefaultPingTimeout how long one may go without answering before its
+// peer is taken for dead, unless the server is given others.
+const (
+	DefaultPingInterval = 10 * time.Second
+	DefaultPingTimeout  = 30 * time.Second
+)
+
+// DefaultAFKTimeout is how long a user may send nothing before being shown
+// as idle, unless the server is given another timeout.
+const DefaultAFKTimeout = 5 * time.Minute
+
+// afkCheckInterval is how often rooms look for users that went idle.
+const afkCheckInterval = time.Second
+
+// SetPing sets how often connections are pinged and how long one may stay
+// silent, pongs included, before it is dropped. It only applies to
+// connections made afterwards.
+func (s *Server) SetPing(interval, timeout time.Duration) {
+	if interval > 0 {
+		s.pingInterval = interval
+	}
+	if timeout > 0 {
+		s.pingTimeout = timeout
+	}
+}
+
+// SetAFKTimeout sets how long a user may send nothing before the space is
+// told they are idle. Zero never marks anyone idle.
+func (s *Server) SetAFKTimeout(d time.Duration) {
+	s.afkTimeout = d
+}
+
+// ping asks the peer for a pong carrying the time it was sent at.
+func (o *outbox) ping() error {
+	data := strconv.FormatInt(time.Now().UnixNano(), 10)
+	return o.conn.WriteControl(websocket.PingMessage, []byte(data), time.Now().Add(writeWait))
+}
+
+// pong measures the round trip of the ping data answers, reporting false
+// if data is not one of ours.
+func (o *outbox) pong(data string) (time.Duration, bool) {
+	sent, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	rtt := time.Since(time.Unix(0, sent))
+	if rtt < 0 || rtt > o.pingTimeout {
+		return 0, false
+	}
+
+	o.rtt.Store(int64(rtt))
+	return rtt, true
+}
+
+// notify sends c an event that is not numbered, as there is no use in
+// replaying it on resume.
+func (c *Client) notify(eventType string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("encode %s: %v", eventType, err)
+		return
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.out != nil {
+		c.out.push(outgoing{msg: Message{Type: eventType, Payload: data}})
+	}
+}
+
+// active records that c sent something, telling its space it is back if
+// it was idle. c.actions must be held.
+func (c *Client) active() {
+	c.lastActive.Store(time.Now().UnixNano())
+
+	if c.room != nil && c.idle.CompareAndSwap(true, false) {
+		c.room.broadcast(c, EventUserActive, userActivityPayload{UserID: c.userID}, nil)
+		c.server.publish(c.room, roomEvent{Kind: roomActive, UserID: c.userID})
+	}
+}
+
+// idleClients marks idle the clients connected here that have sent nothing
+// for timeout, and returns them. r.mu must be held.
+func (r *Room) idleClients(now time.Time, timeout time.Duration) []*Client {
+	var idle []*Client
+	for _, c := range r.clients {
+		if c.node != "" || now.Sub(time.Unix(0, c.lastActive.Load())) < timeout {
+			continue
+		}
+		if c.idle.CompareAndSwap(false, true) {
+			idle = append(idle, c)
+		}
+	}
+	return idle
+}
+
+// announceIdle tells r that clients went idle.
+func (r *Room) announceIdle(clients []*Client) {
+	for _, c := range clients {
+		r.broadcast(c, EventUserIdle, userActivityPayload{UserID: c.userID}, nil)
+		r.server.publish(r, roomEvent{Kind: roomIdle, UserID: c.userID})
+	}
+}
+
+// remoteActivity marks a user connected to another node idle or back.
+func (r *Room) remoteActivity(userID string, idle bool) {
+	r.mu.RLock()
+	c := r.clients[userID]
+	r.mu.RUnlock()
+
+	if c == nil || c.node == "" || c.idle.Swap(idle) == idle {
+		return
+	}
+
+	eventType := EventUserActive
+	if idle {
+		eventType = EventUserIdle
+	}
+	r.broadcast(c, eventType, userActivityPayload{UserID: userID}, nil)
+}
+
+// idleUsers returns the users in r shown as idle. r.mu must be held.
+func (r *Room) idleUsers() []string {
+	var users []string
+	for _, c := range r.clients {
+		if c.idle.Load() {
+			users = append(users, c.userID)
+		}
+	}
+	slices.Sort(users)
+	return users
+}
+
+// Stats is a snapshot of the server's joined clients, for metrics. Round
+// trips are in milliseconds, over the clients measured so far. Dropped
+// counts the messages refused by rate limits since the server started, by
+// type.
+type Stats struct {
+	Clients int     `json:"clients"`
+	Idle    int     `json:"idle"`
+	RTTMean float64 `json:"rttMeanMs"`
+	RTTP95  float64 `json:"rttP95Ms"`
+	RTTMax  float64 `json:"rttMaxMs"`
+
+	Dropped    map[string]int64 `json:"dropped"`
+	FloodMutes int64            `json:"floodMutes"`
+	FloodKicks int64            `json:"floodKicks"`
+}
+
+// Stats returns how many clients are joined here, how many are idle, how
+// long their round trips take and how many messages were rate limited.
+func (s *Server) Stats() Stats {
+	s.mu.Lock()
+	clients := make([]*Client, 0, len(s.clients))
+	for _, c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+
+	st := Stats{
+		Clients:    len(clients),
+		Dropped:    s.droppedMessages(),
+		FloodMutes: s.floodMutes.Load(),
+		FloodKicks: s.floodKicks.Load(),
+	}
+	var rtts []time.Duration
+	for _, c := range clients {
+		if c.idle.Load() {
+			st.Idle++
+		}
+		if out := c.connection(); out != nil {
+			if rtt := out.rtt.Load(); rtt > 0 {
+				rtts = append(rtts, time.Duration(rtt))
+			}
+		}
+	}
+
+	if len(rtts) == 0 {
+		return st
+	}
+
+	slices.Sort(rtts)
+	var total time.Duration
+	for _, rtt := range rtts {
+		total += rtt
+	}
+	st.RTTMean = milliseconds(total / time.Duration(len(rtts)))
+	st.RTTP95 = milliseconds(rtts[(len(rtts)*95+99)/100-1])
+	st.RTTMax = milliseconds(rtts[len(rtts)-1])
+	return st
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,257 @@
+package realtime
+
+import (
+	"encoding/json"
+
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+	"github.com/vaxxnsh/metaverse/api/wire"
+)
+
+// Client to server message types.
+const (
+	MessageJoin          = "join"
+	MessageMove          = "move"
+	MessageMoveTo        = "move-to"
+	MessageChat          = "chat"
+	MessageEmote         = "emote"
+	MessageDirectMessage = "direct-message"
+	MessageKick          = "kick"
+	MessageMute          = "mute"
+	MessageBan           = "ban"
+	MessageResume        = "resume"
+)
+
+// Server to client event types.
+const (
+	EventSpaceJoined      = "space-joined"
+	EventUserJoined       = "user-joined"
+	EventUserLeft         = "user-left"
+	EventMovement         = "movement"
+	EventMovementRejected = "movement-rejected"
+	EventEnterView        = "enter-view"
+	EventLeaveView        = "leave-view"
+	EventStateDelta       = "state-delta"
+	EventChat             = "chat"
+	EventEmote            = "emote"
+	EventDirectMessage    = "direct-message"
+	EventKicked           = "kicked"
+	EventMuted            = "muted"
+	EventUnmuted          = "unmuted"
+	EventBanned           = "banned"
+	EventQueuePosition    = "queue-position"
+	EventZoneEntered      = "zone-entered"
+	EventZoneLeft         = "zone-left"
+	EventPortalTransition = "portal-transition"
+	EventPath             = "path"
+	EventElementsChanged  = "elements-changed"
+	EventResumed          = "resumed"
+	EventRedirect         = "redirect"
+	EventUserIdle         = "user-idle"
+	EventUserActive       = "user-active"
+	EventLatency          = "latency"
+	EventRateLimited      = "rate-limited"
+	EventError            = "error"
+)
+
+// Message is a frame on the socket, decoded by the connection's codec.
+type Message = wire.Message
+
+type point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type joinPayload struct {
+	SpaceID string `json:"spaceId"`
+	Token   string `json:"token"`
+
+	// Password is only needed for password-protected spaces the user is
+	// not a member of.
+	Password string `json:"password"`
+
+	// SpawnTicket is an optional spawn point the server issued, e.g. for
+	// joining a friend or in a redirect. Without one the user appears at a
+	// random point.
+	SpawnTicket string `json:"spawnTicket"`
+
+	// StateDeltas asks for a state delta per tick, which also confirms the
+	// client's own moves, in place of the movement, enter-view and
+	// leave-view events of each user.
+	StateDeltas bool `json:"stateDeltas"`
+}
+
+// resumePayload takes over a session that lost its connection. LastSeq is
+// the seq of the last event the client received.
+type resumePayload struct {
+	Token   string `json:"token"`
+	LastSeq uint64 `json:"lastSeq"`
+}
+
+type movePayload struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// maxMessageLength bounds, in characters, chat and direct messages and
+// emotes, so that they still fit in a broker message on their way to other
+// nodes.
+const maxMessageLength = 1000
+
+type chatPayload struct {
+	Message string `json:"message"`
+}
+
+type emotePayload struct {
+	Emote string `json:"emote"`
+}
+
+type directMessagePayload struct {
+	UserID  string `json:"userId"`
+	Message string `json:"message"`
+}
+
+// moderationPayload is a moderator command. Duration is in seconds and is
+// ignored for kicks.
+type moderationPayload struct {
+	UserID   string `json:"userId"`
+	Reason   string `json:"reason"`
+	Duration int    `json:"duration"`
+}
+
+type userPosition struct {
+	UserID string `json:"userId"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+}
+
+// spaceJoinedPayload carries the users of the space shown as idle, and the
+// token with which a new connection can resume the session if this one
+// drops. SpawnMoved is set when the spawn point asked for was blocked and
+// Spawn is the nearest free tile instead.
+type spaceJoinedPayload struct {
+	UserID      string         `json:"userId"`
+	Spawn       point          `json:"spawn"`
+	SpawnMoved  bool           `json:"spawnMoved,omitempty"`
+	Users       []userPosition `json:"users"`
+	Idle        []string       `json:"idle,omitempty"`
+	ResumeToken string         `json:"resumeToken"`
+}
+
+// resumedPayload is sent on the new connection of a resumed session, before
+// the events it missed. Position is where the server has the user, and
+// LastSeq is the seq of the last missed event.
+type resumedPayload struct {
+	SpaceID     string `json:"spaceId"`
+	ResumeToken string `json:"resumeToken"`
+	Position    point  `json:"position"`
+	LastSeq     uint64 `json:"lastSeq"`
+}
+
+// redirectPayload sends a client to the node at URL, which serves SpaceID.
+// SpawnTicket is what the client should join there with to appear where the
+// server means it to.
+type redirectPayload struct {
+	SpaceID     string `json:"spaceId"`
+	URL         string `json:"url"`
+	SpawnTicket string `json:"spawnTicket,omitempty"`
+}
+
+// queuePositionPayload is sent while a client waits for a full space.
+// Position is 1-based.
+type queuePositionPayload struct {
+	SpaceID  string `json:"spaceId"`
+	Position int    `json:"position"`
+	Size     int    `json:"size"`
+}
+
+type zoneEventPayload struct {
+	UserID     string          `json:"userId"`
+	ZoneID     string          `json:"zoneId"`
+	Name       string          `json:"name"`
+	ChatScope  string          `json:"chatScope,omitempty"`
+	Audio      string          `json:"audio,omitempty"`
+	Properties json.RawMessage `json:"properties,omitempty"`
+}
+
+// portalTransitionPayload is sent to a user stepping through a portal,
+// before it appears at Spawn in SpaceID.
+type portalTransitionPayload struct {
+	ElementID   string `json:"elementId"`
+	FromSpaceID string `json:"fromSpaceId"`
+	SpaceID     string `json:"spaceId"`
+	Spawn       point  `json:"spawn"`
+}
+
+// pathPayload is the route a move-to will walk, excluding the start.
+type pathPayload struct {
+	Steps []point `json:"steps"`
+}
+
+// elementsChangedPayload describes a new version of a space's layout.
+type elementsChangedPayload struct {
+	SpaceID string                  `json:"spaceId"`
+	Version int                     `json:"version"`
+	Added   []service.LayoutElement `json:"added"`
+	Removed []service.LayoutElement `json:"removed"`
+	Moved   []service.LayoutElement `json:"moved"`
+}
+
+// movementRejectedPayload puts a client back where the server has it, and
+// says why its move was refused.
+type movementRejectedPayload struct {
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Reason string `json:"reason"`
+}
+
+// stateDeltaPayload is what changed in a client's view during a tick: users
+// that moved within it, came into it and went out of it.
+type stateDeltaPayload struct {
+	Tick    uint64         `json:"tick"`
+	Moved   []userPosition `json:"moved,omitempty"`
+	Entered []userPosition `json:"entered,omitempty"`
+	Left    []string       `json:"left,omitempty"`
+}
+
+// userActivityPayload says a user went idle or came back.
+type userActivityPayload struct {
+	UserID string `json:"userId"`
+}
+
+// latencyPayload is a client's last measured round trip, in milliseconds.
+type latencyPayload struct {
+	RTT uint32 `json:"rtt"`
+}
+
+// rateLimitedPayload says messages of Type are dropped, and how many
+// milliseconds until the next one goes through.
+type rateLimitedPayload struct {
+	Type       string `json:"type"`
+	RetryAfter uint32 `json:"retryAfter"`
+}
+
+type userLeftPayload struct {
+	UserID string `json:"userId"`
+}
+
+type userChatPayload struct {
+	UserID  string `json:"userId"`
+	Message string `json:"message"`
+}
+
+type userEmotePayload struct {
+	UserID string `json:"userId"`
+	Emote  string `json:"emote"`
+}
+
+type errorPayload struct {
+	Message string `json:"message"`
+}
+
+// sanctionPayload tells a client why it was kicked, muted or banned. Until
+// is empty for kicks and permanent bans.
+type sanctionPayload struct {
+	SpaceID string `json:"spaceId"`
+	Reason  string `json:"reason,omitempty"`
+	Until   string `json:"until,omitempty"`
+}
@@ -0,0 +1,274 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+// Moderation carries out moderator commands sent over the socket and reports
+// the sanctions a user has in a space.
+type Moderation interface {
+	Kick(ctx context.Context, moderatorID, spaceID, userID, reason string) error
+	Mute(ctx context.Context, moderatorID, spaceID, userID, reason string, d time.Duration) (*service.SpaceSanction, error)
+	Ban(ctx context.Context, moderatorID, spaceID, userID, reason string, d time.Duration) (*service.SpaceSanction, error)
+	ActiveSanctions(ctx context.Context, spaceID, userID string) ([]service.SpaceSanction, error)
+}
+
+// SetModeration enables moderator commands. It is separate from NewServer
+// because the moderation service also reports back to the server.
+func (s *Server) SetModeration(m Moderation) {
+	s.moderation = m
+}
+
+func (c *Client) moderate(msgType string, p moderationPayload) {
+	if c.server.moderation == nil {
+		c.sendError("moderation is not available")
+		return
+	}
+
+	ctx := context.Background()
+	spaceID := c.room.spaceID
+	d := time.Duration(p.Duration) * time.Second
+
+	var err error
+	switch msgType {
+	case MessageKick:
+		err = c.server.moderation.Kick(ctx, c.userID, spaceID, p.UserID, p.Reason)
+	case MessageMute:
+		_, err = c.server.moderation.Mute(ctx, c.userID, spaceID, p.UserID, p.Reason, d)
+	case MessageBan:
+		_, err = c.server.moderation.Ban(ctx, c.userID, spaceID, p.UserID, p.Reason, d)
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, service.ErrSpaceForbidden),
+		errors.Is(err, service.ErrCannotModerate),
+		errors.Is(err, service.ErrUserNotInSpace),
+		errors.Is(err, service.ErrInvalidUserID),
+		errors.Is(err, service.ErrInvalidSanctionDuration),
+		errors.Is(err, service.ErrUserNotFound):
+		c.sendError(err.Error())
+	default:
+		log.Println("moderation failed:", err)
+		c.sendError("moderation failed")
+	}
+}
+
+// loadSanctions applies the sanctions c has in spaceID. It reports false,
+// after telling the client, when c is banned.
+func (c *Client) loadSanctions(ctx context.Context, spaceID string) (bool, error) {
+	if c.server.moderation == nil {
+		return true, nil
+	}
+
+	sanctions, err := c.server.moderation.ActiveSanctions(ctx, spaceID, c.userID)
+	if err != nil {
+		return false, err
+	}
+
+	var mute *service.SpaceSanction
+	for i := range sanctions {
+		switch sanctions[i].Kind {
+		case service.SanctionBan:
+			c.send(EventBanned, newSanctionPayload(&sanctions[i]))
+			return false, nil
+		case service.SanctionMute:
+			mute = &sanctions[i]
+		}
+	}
+
+	wasMuted := time.Now().UnixNano() < c.mutedUntil.Load()
+	if mute != nil {
+		c.mutedUntil.Store(mute.ExpiresAt.UnixNano())
+		c.send(EventMuted, newSanctionPayload(mute))
+	} else {
+		c.mutedUntil.Store(0)
+		if wasMuted {
+			c.send(EventUnmuted, sanctionPayload{SpaceID: spaceID})
+		}
+	}
+
+	return true, nil
+}
+
+// muted reports whether c may not chat right now, by a moderator's mute or
+// for flooding.
+func (c *Client) muted() bool {
+	now := time.Now().UnixNano()
+	return now < c.mutedUntil.Load() || now < c.floodMutedUntil.Load()
+}
+
+// UserKicked disconnects userID if they are in spaceID. A user connected
+// to another node is kicked by that node; when the room is not live here,
+// whether they were in it cannot be told, and they are taken to be.
+func (s *Server) UserKicked(spaceID, userID, reason string) bool {
+	if s.kick(spaceID, userID, reason) {
+		return true
+	}
+	if s.broker == nil {
+		return false
+	}
+
+	if room := s.room(spaceID); room != nil {
+		room.mu.RLock()
+		_, ok := room.clients[userID]
+		room.mu.RUnlock()
+		if !ok {
+			return false
+		}
+	}
+
+	s.publishSpace(spaceID, roomEvent{Kind: roomKicked, UserID: userID, Text: reason})
+	return true
+}
+
+// kick disconnects userID if they are connected here and in spaceID.
+func (s *Server) kick(spaceID, userID, reason string) bool {
+	c := s.clientIn(spaceID, userID)
+	if c == nil {
+		return false
+	}
+
+	c.send(EventKicked, sanctionPayload{SpaceID: spaceID, Reason: reason})
+	c.close()
+	return true
+}
+
+// SanctionsChanged reapplies userID's sanctions if they are in spaceID,
+// disconnecting them when they have been banned.
+func (s *Server) SanctionsChanged(spaceID, userID string) {
+	s.refreshSanctions(spaceID, userID)
+	s.publishSpace(spaceID, roomEvent{Kind: roomSanctions, UserID: userID})
+}
+
+// refreshSanctions is SanctionsChanged for a user connected here.
+func (s *Server) refreshSanctions(spaceID, userID string) {
+	c := s.clientIn(spaceID, userID)
+	if c == nil {
+		return
+	}
+
+	allowed, err := c.loadSanctions(context.Background(), spaceID)
+	if err != nil {
+		c.sendError("failed to refresh sanctions")
+		return
+	}
+
+	if !allowed {
+		c.close()
+	}
+}
+
+// MemberRoleChanged gives userID their new role in spaceID, "" once they
+// are no longer a member, if they are in it.
+func (s *Server) MemberRoleChanged(spaceID, userID, role string) {
+	s.changeRole(spaceID, userID, role)
+	s.publishSpace(spaceID, roomEvent{Kind: roomRole, UserID: userID, Text: role})
+}
+
+// changeRole is MemberRoleChanged for a user connected here. The tiles they
+// may walk on and their rate limits follow the role.
+func (s *Server) changeRole(spaceID, userID, role string) {
+	c := s.clientIn(spaceID, userID)
+	if c == nil {
+		return
+	}
+
+	c.actions.Lock()
+	defer c.actions.Unlock()
+
+	if c.room == nil || c.room.spaceID != spaceID {
+		return
+	}
+
+	c.room.mu.Lock()
+	c.role = role
+	c.room.mu.Unlock()
+	c.limits.configure(c.limits.overrides, role)
+}
+
+// MemberRemoved takes userID out of spaceID, wherever they are connected,
+// if they may no longer be in it.
+func (s *Server) MemberRemoved(spaceID, userID string) {
+	s.recheckAccess(spaceID, userID)
+	s.publishSpace(spaceID, roomEvent{Kind: roomAccess, UserID: userID})
+}
+
+// VisibilityChanged takes everyone who may no longer be in spaceID out of
+// it, wherever they are connected.
+func (s *Server) VisibilityChanged(spaceID string) {
+	s.recheckAccess(spaceID, "")
+	s.publishSpace(spaceID, roomEvent{Kind: roomAccess})
+}
+
+// localClients returns the clients connected here that are in or waiting
+// for r.
+func (r *Room) localClients() []*Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var clients []*Client
+	for _, c := range r.clients {
+		if c.node == "" {
+			clients = append(clients, c)
+		}
+	}
+	for _, q := range r.queue {
+		clients = append(clients, q.client)
+	}
+	return clients
+}
+
+// recheckAccess disconnects the clients connected here that are in or
+// waiting for spaceID's room, only userID's if given, and may no longer be
+// in it. The password of a password-protected space is not asked again.
+func (s *Server) recheckAccess(spaceID, userID string) {
+	room := s.room(spaceID)
+	if room == nil {
+		return
+	}
+
+	ctx := context.Background()
+	for _, c := range room.localClients() {
+		if userID != "" && c.userID != userID {
+			continue
+		}
+		_, err := s.spaces.ReenterSpace(ctx, c.userID, spaceID)
+		switch err {
+		case nil:
+		case service.ErrSpaceInviteOnly, service.ErrSpaceBanned, service.ErrSpaceNotFound:
+			c.sendError(joinError(err))
+			c.close()
+		default:
+			log.Println("recheck space access:", err)
+		}
+	}
+}
+
+// clientIn returns the connection of userID if it is joined to spaceID.
+func (s *Server) clientIn(spaceID, userID string) *Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.clients[userID]
+	if c == nil || c.room == nil || c.room.spaceID != spaceID {
+		return nil
+	}
+	return c
+}
+
+func newSanctionPayload(sanction *service.SpaceSanction) sanctionPayload {
+	p := sanctionPayload{
+		SpaceID: sanction.SpaceID,
+		Reason:  sanction.Reason,
+	}
+	if !sanction.ExpiresAt.IsZero() {
+		p.Until = sanction.ExpiresAt.Format(time.RFC3339)
+	}
+	return p
+}
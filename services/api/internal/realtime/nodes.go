@@ -0,0 +1,389 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/sharding"
+)
+
+// NodeStore is the registry of real-time nodes.
+type NodeStore interface {
+	Heartbeat(ctx context.Context, node sharding.Node) error
+	ListLive(ctx context.Context, since time.Time) ([]sharding.Node, error)
+	Delete(ctx context.Context, id string) error
+	DeleteStale(ctx context.Context, before time.Time) (int64, error)
+}
+
+// A node is live while its last heartbeat is younger than nodeTTL. Nodes
+// gone for staleNodeAge are dropped from the registry.
+const (
+	heartbeatInterval = 5 * time.Second
+	nodeTTL           = 3 * heartbeatInterval
+	staleNodeAge      = time.Hour
+)
+
+// arrivalPriority puts users who had a place in a migrated space ahead of
+// everyone waiting for one, and queuedArrivalPriority those who were waiting
+// in it ahead of everyone who was not.
+const (
+	arrivalPriority       = 4
+	queuedArrivalPriority = 3
+)
+
+// migratedPerEvent bounds the users in one migration, so that it fits in a
+// broker message along with their resume tokens.
+const migratedPerEvent = 32
+
+// migration hands users of a space over to the node that now owns it. A
+// large space takes several.
+type migration struct {
+	SpaceID string         `json:"spaceId"`
+	Users   []migratedUser `json:"users"`
+}
+
+// migratedUser is where a user stood, or their 1-based place in the queue
+// if they were waiting, the token their session resumes with, whether it
+// asked for state deltas and whether the user is a site admin.
+type migratedUser struct {
+	UserID      string `json:"userId"`
+	X           int    `json:"x"`
+	Y           int    `json:"y"`
+	Queued      int    `json:"queued,omitempty"`
+	ResumeToken string `json:"resumeToken,omitempty"`
+	StateDeltas bool   `json:"stateDeltas,omitempty"`
+	Admin       bool   `json:"admin,omitempty"`
+}
+
+// arrival is where a migrated user stood or their place in the queue, kept
+// until the user joins or the resume grace period is over.
+type arrival struct {
+	at          point
+	queued      int
+	token       string
+	stateDeltas bool
+	admin       bool
+	until       time.Time
+}
+
+// arrivalKey names the arrival a resume token came with.
+type arrivalKey struct {
+	spaceID string
+	userID  string
+}
+
+func nodeTopic(id string) string {
+	return "node:" + id
+}
+
+// SetNodes registers the server in nodes as reachable by clients at url
+// once RunNode runs. Spaces are then shared out between the live nodes,
+// and a client joining a space another node owns is sent there.
+func (s *Server) SetNodes(nodes NodeStore, url string) {
+	s.nodes = nodes
+	s.url = url
+}
+
+// RunNode keeps the server's heartbeat and its view of the other nodes
+// fresh until ctx is done, handing rooms to their new owner whenever the
+// ring changes.
+func (s *Server) RunNode(ctx context.Context) {
+	if s.broker != nil {
+		defer s.broker.Subscribe(nodeTopic(s.node), s.migrated)()
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		s.heartbeat(ctx)
+
+		select {
+		case <-ctx.Done():
+			if err := s.nodes.Delete(context.Background(), s.node); err != nil {
+				log.Println("deregister node:", err)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain takes the server out of the ring and migrates every room to the
+// node that owns it next, then waits until every client has been sent its
+// redirect and disconnected, so that a deploy can stop the server once
+// Drain returns. It gives up waiting when ctx is done. Without a registry
+// there is nowhere to migrate to.
+func (s *Server) Drain(ctx context.Context) error {
+	if s.nodes == nil {
+		return nil
+	}
+
+	s.draining.Store(true)
+	for _, out := range s.heartbeat(ctx) {
+		select {
+		case <-out.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// heartbeat records that the server is live, then migrates the rooms the
+// refreshed ring gives to other nodes. It returns the connections of the
+// clients it redirected, which close once the redirect is written.
+func (s *Server) heartbeat(ctx context.Context) []*outbox {
+	s.nodesMu.Lock()
+	defer s.nodesMu.Unlock()
+
+	now := time.Now().UTC()
+
+	err := s.nodes.Heartbeat(ctx, sharding.Node{
+		ID:          s.node,
+		URL:         s.url,
+		Draining:    s.draining.Load(),
+		HeartbeatAt: now,
+	})
+	if err != nil {
+		log.Println("node heartbeat:", err)
+		return nil
+	}
+
+	if _, err := s.nodes.DeleteStale(ctx, now.Add(-staleNodeAge)); err != nil {
+		log.Println("drop stale nodes:", err)
+	}
+
+	if err := s.refreshRing(ctx); err != nil {
+		log.Println("list nodes:", err)
+		return nil
+	}
+
+	return s.rebalance()
+}
+
+// refreshRing rebuilds the ring from the live nodes.
+func (s *Server) refreshRing(ctx context.Context) error {
+	live, err := s.nodes.ListLive(ctx, time.Now().UTC().Add(-nodeTTL))
+	if err != nil {
+		return err
+	}
+
+	s.ring.Store(sharding.NewRing(live))
+	return nil
+}
+
+// elsewhere returns the node that owns spaceID if it is not this one.
+// Without a registry, or before the first heartbeat, every space is
+// served here.
+func (s *Server) elsewhere(spaceID string) (sharding.Node, bool) {
+	owner, ok := s.ring.Load().Owner(spaceID)
+	if !ok || owner.ID == s.node {
+		return sharding.Node{}, false
+	}
+	return owner, true
+}
+
+// rebalance migrates every room this node no longer owns, returning the
+// connections of the clients it redirected.
+func (s *Server) rebalance() []*outbox {
+	s.mu.Lock()
+	var moving []*Room
+	for spaceID, r := range s.rooms {
+		if _, ok := s.elsewhere(spaceID); ok {
+			moving = append(moving, r)
+		}
+	}
+	s.mu.Unlock()
+
+	var closing []*outbox
+	for _, r := range moving {
+		if owner, ok := s.elsewhere(r.spaceID); ok {
+			closing = append(closing, s.migrate(r, owner)...)
+		}
+	}
+	return closing
+}
+
+// migrate hands r over to owner: owner learns where r's users stand, the
+// place in the queue of those waiting and the tokens their sessions resume
+// with, and every client connected here is redirected to owner and
+// disconnected. It returns the connections of those clients.
+func (s *Server) migrate(r *Room, owner sharding.Node) []*outbox {
+	var users []migratedUser
+	var clients []*Client
+
+	r.mu.RLock()
+	for _, c := range r.clients {
+		if c.node == "" {
+			users = append(users, migratedUser{
+				UserID:      c.userID,
+				X:           c.x,
+				Y:           c.y,
+				ResumeToken: c.resumeToken(),
+				StateDeltas: c.stateDeltas.Load(),
+				Admin:       c.admin,
+			})
+			clients = append(clients, c)
+		}
+	}
+	for i, q := range r.queue {
+		users = append(users, migratedUser{
+			UserID:      q.client.userID,
+			Queued:      i + 1,
+			ResumeToken: q.client.resumeToken(),
+			StateDeltas: q.client.stateDeltas.Load(),
+			Admin:       q.client.admin,
+		})
+		clients = append(clients, q.client)
+	}
+	r.mu.RUnlock()
+
+	log.Printf("migrating space %s with %d clients to node %s", r.spaceID, len(clients), owner.ID)
+
+	for chunk := range slices.Chunk(users, migratedPerEvent) {
+		s.handOver(owner, migration{SpaceID: r.spaceID, Users: chunk})
+	}
+
+	// Every client is redirected before any is disconnected, so that none
+	// sees the others leave first.
+	for i, c := range clients {
+		redirect := redirectPayload{SpaceID: r.spaceID, URL: owner.URL}
+		if users[i].Queued == 0 {
+			redirect.SpawnTicket = SignSpawn(s.secret, users[i].UserID, r.spaceID, users[i].X, users[i].Y)
+		}
+		c.send(EventRedirect, redirect)
+	}
+
+	var closing []*outbox
+	for _, c := range clients {
+		if out := c.connection(); out != nil {
+			closing = append(closing, out)
+		}
+		c.close()
+	}
+	return closing
+}
+
+// handOver sends m to owner. Without a broker owner spawns the users where
+// the tickets in their redirects say.
+func (s *Server) handOver(owner sharding.Node, m migration) {
+	if s.broker == nil {
+		return
+	}
+
+	data, err := json.Marshal(m)
+	if err == nil {
+		err = s.broker.Publish(context.Background(), nodeTopic(owner.ID), data)
+	}
+	if err != nil {
+		log.Printf("migrate space %s: %v", m.SpaceID, err)
+	}
+}
+
+// migrated expects the users of a space another node handed over. The ring
+// is refreshed first, as the other node may know of this one's ownership
+// before it does.
+func (s *Server) migrated(msg []byte) {
+	var m migration
+	if err := json.Unmarshal(msg, &m); err != nil {
+		log.Println("decode migration:", err)
+		return
+	}
+
+	if err := s.refreshRing(context.Background()); err != nil {
+		log.Println("list nodes:", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Users who never came are forgotten with the next migration.
+	now := time.Now()
+	for spaceID, arrivals := range s.arrivals {
+		maps.DeleteFunc(arrivals, func(_ string, a arrival) bool {
+			if now.Before(a.until) {
+				return false
+			}
+			delete(s.arrivalTokens, a.token)
+			return true
+		})
+		if len(arrivals) == 0 {
+			delete(s.arrivals, spaceID)
+		}
+	}
+
+	until := now.Add(max(s.resumeGrace, nodeTTL))
+	if s.arrivals[m.SpaceID] == nil {
+		s.arrivals[m.SpaceID] = make(map[string]arrival)
+	}
+	for _, u := range m.Users {
+		s.arrivals[m.SpaceID][u.UserID] = arrival{
+			at:          point{X: u.X, Y: u.Y},
+			queued:      u.Queued,
+			token:       u.ResumeToken,
+			stateDeltas: u.StateDeltas,
+			admin:       u.Admin,
+			until:       until,
+		}
+		if u.ResumeToken != "" {
+			s.arrivalTokens[u.ResumeToken] = arrivalKey{spaceID: m.SpaceID, userID: u.UserID}
+		}
+	}
+}
+
+// arrival returns and forgets where userID stood in spaceID before the
+// space was migrated here, if that was recently. s.mu must be held.
+func (s *Server) arrival(spaceID, userID string) (arrival, bool) {
+	a, ok := s.arrivals[spaceID][userID]
+	if !ok {
+		return arrival{}, false
+	}
+
+	delete(s.arrivals[spaceID], userID)
+	delete(s.arrivalTokens, a.token)
+	if len(s.arrivals[spaceID]) == 0 {
+		delete(s.arrivals, spaceID)
+	}
+	return a, time.Now().Before(a.until)
+}
+
+// arrivalToken returns whose session token resumes if it came with a
+// recent migration. s.mu must be held.
+func (s *Server) arrivalToken(token string) (arrivalKey, bool) {
+	k, ok := s.arrivalTokens[token]
+	if !ok {
+		return arrivalKey{}, false
+	}
+	a, ok := s.arrivals[k.spaceID][k.userID]
+	return k, ok && time.Now().Before(a.until)
+}
+
+// resumeMigrated takes up on c the session of a user whose space was
+// migrated here, putting them back where they stood or in their place in
+// the queue. The events the client missed stayed on the old node, so it is
+// sent space-joined with everyone in view, as on a join, rather than
+// resumed.
+func (s *Server) resumeMigrated(c *Client, k arrivalKey) {
+	ctx := context.Background()
+
+	space, err := s.spaces.ReenterSpace(ctx, k.userID, k.spaceID)
+	if err != nil {
+		c.sendError(joinError(err))
+		return
+	}
+
+	if owner, ok := s.elsewhere(space.ID); ok {
+		c.send(EventRedirect, redirectPayload{SpaceID: space.ID, URL: owner.URL})
+		return
+	}
+
+	c.actions.Lock()
+	defer c.actions.Unlock()
+
+	s.enter(ctx, c, k.userID, space, nil)
+}
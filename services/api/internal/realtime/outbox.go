@@ -0,0 +1,262 @@
+package realtime
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vaxxnsh/metaverse/api/wire"
+)
+
+// DefaultSendQueue is how many events may wait to be written to a
+// connection, and DefaultSlowClientTimeout how long a connection may stay
+// over half that behind, unless the server is given other limits.
+const (
+	DefaultSendQueue         = 256
+	DefaultSlowClientTimeout = 5 * time.Second
+)
+
+// closeTooSlow is the close reason given to a client that could not keep
+// up with its events.
+const closeTooSlow = "too slow to keep up"
+
+// SetSendQueue sets how many events may wait to be written to a
+// connection, and how long a connection may have over half that many
+// waiting, before the client is disconnected as too slow. It only applies
+// to connections made afterwards.
+func (s *Server) SetSendQueue(size int, slowTimeout time.Duration) {
+	if size > 0 {
+		s.sendQueue = size
+	}
+	if slowTimeout > 0 {
+		s.slowClientTimeout = slowTimeout
+	}
+}
+
+// outgoing is an event waiting to be written. A state delta keeps what it
+// is made of and its tick, for the next one to be folded into it while it
+// waits, and a movement event the user who moved, for their next one to
+// replace it.
+type outgoing struct {
+	msg   Message
+	delta *stateDelta
+	tick  uint64
+	mover string
+}
+
+// outbox is a connection with the events waiting to be written to it,
+// which its own goroutine writes so that a stalled client never holds up
+// whoever sends to it. A client that falls too far behind is disconnected
+// rather than left to grow the queue.
+type outbox struct {
+	conn  *websocket.Conn
+	codec wire.Codec
+
+	limit        int
+	slowTimeout  time.Duration
+	pingInterval time.Duration
+	pingTimeout  time.Duration
+
+	// rtt is the round trip of the last ping answered, 0 before any is.
+	rtt atomic.Int64
+
+	mu     sync.Mutex
+	queue  []outgoing
+	closed bool
+
+	// finishing closes the connection once the queue is written.
+	finishing bool
+
+	// behind is since when the queue has been more than half full, and
+	// zero while it is not.
+	behind time.Time
+
+	wake chan struct{}
+
+	// done is closed once the outbox has stopped writing.
+	done chan struct{}
+}
+
+// newOutbox starts writing to conn within the limits s sets.
+func newOutbox(s *Server, conn *websocket.Conn) *outbox {
+	o := &outbox{
+		conn:         conn,
+		codec:        wire.ServerCodec(conn.Subprotocol()),
+		limit:        s.sendQueue,
+		slowTimeout:  s.slowClientTimeout,
+		pingInterval: s.pingInterval,
+		pingTimeout:  s.pingTimeout,
+		wake:         make(chan struct{}, 1),
+		done:         make(chan struct{}),
+	}
+	go o.run()
+	return o
+}
+
+// push queues out. A state delta following another that has not been
+// written yet is folded into it, and a movement event takes the place of
+// the user's last one still waiting, so that the client only learns where
+// users ended up; it takes the later seq.
+func (o *outbox) push(out outgoing) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed || o.finishing {
+		return
+	}
+
+	if out.mover != "" {
+		// The earlier movement goes rather than being overwritten where it
+		// is, so that the new one stays after whatever was sent since.
+		for i, queued := range o.queue {
+			if queued.mover == out.mover {
+				o.queue = append(o.queue[:i], o.queue[i+1:]...)
+				break
+			}
+		}
+	}
+
+	if n := len(o.queue); out.delta != nil && n > 0 && o.queue[n-1].delta != nil {
+		last := &o.queue[n-1]
+		for _, u := range out.delta.updates {
+			last.delta.update(u.kind, u.at)
+		}
+
+		data, err := json.Marshal(last.delta.payload(out.tick))
+		if err == nil {
+			last.msg, last.tick = out.msg, out.tick
+			last.msg.Payload = data
+			return
+		}
+		log.Printf("encode %s: %v", out.msg.Type, err)
+	}
+
+	o.queue = append(o.queue, out)
+
+	switch {
+	case len(o.queue) <= o.limit/2:
+		o.behind = time.Time{}
+	case o.behind.IsZero():
+		o.behind = time.Now()
+	}
+	if len(o.queue) > o.limit || (!o.behind.IsZero() && time.Since(o.behind) > o.slowTimeout) {
+		o.drop(closeTooSlow)
+		return
+	}
+
+	o.signal()
+}
+
+// signal wakes the writer up. o.mu must be held, as close closes wake.
+func (o *outbox) signal() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run writes the queued events, and pings the peer every ping interval,
+// until the outbox is closed or a write fails.
+func (o *outbox) run() {
+	defer close(o.done)
+
+	ticker := time.NewTicker(o.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case _, ok := <-o.wake:
+			if !ok {
+				return
+			}
+		case <-ticker.C:
+			if err := o.ping(); err != nil {
+				o.close()
+				return
+			}
+			continue
+		}
+
+		o.mu.Lock()
+		if o.closed {
+			o.mu.Unlock()
+			return
+		}
+		queue, finishing := o.queue, o.finishing
+		o.queue = nil
+		o.behind = time.Time{}
+		o.mu.Unlock()
+
+		for _, out := range queue {
+			if err := o.write(out.msg); err != nil {
+				o.close()
+				return
+			}
+		}
+
+		if finishing {
+			o.close()
+			return
+		}
+	}
+}
+
+func (o *outbox) write(msg Message) error {
+	frame, err := o.codec.Encode(msg)
+	if err != nil {
+		log.Printf("encode %s: %v", msg.Type, err)
+		return nil
+	}
+
+	messageType := websocket.TextMessage
+	if o.codec.Binary() {
+		messageType = websocket.BinaryMessage
+	}
+
+	o.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return o.conn.WriteMessage(messageType, frame)
+}
+
+// drop disconnects a client that cannot keep up, telling it why. Its
+// session can still be resumed. o.mu must be held.
+func (o *outbox) drop(reason string) {
+	o.closed = true
+	o.queue = nil
+	close(o.wake)
+
+	go func() {
+		msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+		o.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait))
+		o.conn.Close()
+	}()
+}
+
+// finish closes the connection once the events queued so far are written.
+func (o *outbox) finish() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed || o.finishing {
+		return
+	}
+
+	o.finishing = true
+	o.signal()
+}
+
+// close closes the connection and stops writing to it. Events still queued
+// are lost to it.
+func (o *outbox) close() {
+	o.mu.Lock()
+	if !o.closed {
+		o.closed = true
+		o.queue = nil
+		close(o.wake)
+	}
+	o.mu.Unlock()
+
+	o.conn.Close()
+}
@@ -0,0 +1,135 @@
+package realtime
+
+import (
+	"context"
+	"log"
+
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+// PortalStore loads the portals placed in a space.
+type PortalStore interface {
+	ListBySpace(ctx context.Context, spaceID string) ([]service.Portal, error)
+}
+
+// portalAt returns the portal on the tile at x, y, if any. r.mu must be
+// held.
+func (r *Room) portalAt(x, y int) *service.Portal {
+	for i := range r.portals {
+		if r.portals[i].X == x && r.portals[i].Y == y {
+			p := r.portals[i]
+			return &p
+		}
+	}
+	return nil
+}
+
+// travel sends c through portal. A portal into the same space teleports c;
+// one into another space hands the connection over to that space's room as
+// if c had joined it, once c is known to be allowed in, or redirects c to
+// the node serving that space. Landing on another portal does not fire it.
+func (s *Server) travel(c *Client, portal *service.Portal) {
+	from := c.room
+	spawn := &point{X: portal.Spawn.X, Y: portal.Spawn.Y}
+
+	if portal.DestinationID == from.spaceID {
+		x, y, _ := from.spawnPoint(c, spawn)
+		c.send(EventPortalTransition, portalTransitionPayload{
+			ElementID:   portal.ElementID,
+			FromSpaceID: from.spaceID,
+			SpaceID:     from.spaceID,
+			Spawn:       point{X: x, Y: y},
+		})
+		from.teleport(c, x, y)
+		return
+	}
+
+	ctx := context.Background()
+
+	space, err := s.spaces.EnterSpace(ctx, c.userID, portal.DestinationID, "")
+	if err != nil {
+		c.sendError(joinError(err))
+		return
+	}
+
+	// Another node's space is joined there, from a new connection.
+	if owner, ok := s.elsewhere(space.ID); ok {
+		c.send(EventRedirect, redirectPayload{
+			SpaceID:     space.ID,
+			URL:         owner.URL,
+			SpawnTicket: SignSpawn(s.secret, c.userID, space.ID, spawn.X, spawn.Y),
+		})
+		c.close()
+		return
+	}
+
+	role, err := s.spaces.MemberRole(ctx, space.ID, c.userID)
+	if err != nil {
+		c.sendError(joinError(err))
+		return
+	}
+
+	l, err := s.loadLayout(ctx, space.ID)
+	if err != nil {
+		c.sendError(joinError(err))
+		return
+	}
+
+	allowed, err := c.loadSanctions(ctx, space.ID)
+	if err != nil || !allowed {
+		if err != nil {
+			c.sendError("failed to load sanctions")
+		}
+		return
+	}
+
+	s.mu.Lock()
+	left, watchers := s.leaveRoom(c)
+	c.admitted.Store(false)
+	admitted, position := s.enterRoom(c, space, role, l, spawn, queuePriority(role), 0)
+	s.mu.Unlock()
+
+	c.send(EventPortalTransition, portalTransitionPayload{
+		ElementID:   portal.ElementID,
+		FromSpaceID: from.spaceID,
+		SpaceID:     space.ID,
+		Spawn:       point{X: c.x, Y: c.y},
+	})
+
+	sendAll(watchers, EventUserLeft, userLeftPayload{UserID: c.userID})
+	s.publish(from, roomEvent{Kind: roomLeft, UserID: c.userID})
+	for _, a := range left {
+		s.admitted(a)
+	}
+	from.sendQueuePositions()
+
+	for _, a := range admitted {
+		s.admitted(a)
+	}
+
+	if position > 0 {
+		c.room.sendQueuePositions()
+	}
+}
+
+// PortalsChanged reloads the portals of spaceID into its room wherever one
+// is live.
+func (s *Server) PortalsChanged(spaceID string) {
+	if room := s.room(spaceID); room != nil {
+		s.reloadPortals(room)
+	}
+	s.publishSpace(spaceID, roomEvent{Kind: roomPortals})
+}
+
+// reloadPortals loads room's portals from the store.
+func (s *Server) reloadPortals(room *Room) {
+	portals, err := s.portals.ListBySpace(context.Background(), room.spaceID)
+	if err != nil {
+		log.Println("reload portals:", err)
+		return
+	}
+
+	room.mu.Lock()
+	room.portals = portals
+	room.mu.Unlock()
+}
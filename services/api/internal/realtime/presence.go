@@ -0,0 +1,77 @@
+package realtime
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+// PresenceStore shares which space each user is in with every node, for
+// their friends to find them. Each node keeps the entries of its own users
+// fresh; entries nobody refreshes are dropped. Refresh takes the spaces of
+// the users by user id.
+type PresenceStore interface {
+	Set(ctx context.Context, userID, spaceID, node string, at time.Time) error
+	Remove(ctx context.Context, userID, node string) error
+	Refresh(ctx context.Context, node string, spaces map[string]string, at time.Time) error
+	DeleteStale(ctx context.Context, before time.Time) error
+}
+
+// presenceInterval is how often a node refreshes the presence of its users,
+// well within the time they count as present for.
+const presenceInterval = service.PresenceTTL / 3
+
+// RunPresence keeps the presence of the users connected here fresh until
+// ctx is done. Refreshing rewrites every entry, which also mends one that a
+// user's leaving removed just as they joined again.
+func (s *Server) RunPresence(ctx context.Context) {
+	ticker := time.NewTicker(presenceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now().UTC()
+		if err := s.presence.Refresh(ctx, s.node, s.presentSpaces(), now); err != nil {
+			log.Println("refresh presence:", err)
+		}
+		if err := s.presence.DeleteStale(ctx, now.Add(-service.PresenceTTL)); err != nil {
+			log.Println("delete stale presence:", err)
+		}
+	}
+}
+
+// presentSpaces returns the spaces of the users that have a place in a room
+// and a connection here, by user id.
+func (s *Server) presentSpaces() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	spaces := make(map[string]string, len(s.clients))
+	for userID, c := range s.clients {
+		if c.room != nil && c.admitted.Load() && c.connection() != nil {
+			spaces[userID] = c.room.spaceID
+		}
+	}
+	return spaces
+}
+
+// track records that c is in spaceID.
+func (s *Server) track(c *Client, spaceID string) {
+	if err := s.presence.Set(context.Background(), c.userID, spaceID, s.node, time.Now().UTC()); err != nil {
+		log.Printf("track presence of %s: %v", c.userID, err)
+	}
+}
+
+// untrack records that userID is no longer connected here.
+func (s *Server) untrack(userID string) {
+	if err := s.presence.Remove(context.Background(), userID, s.node); err != nil {
+		log.Printf("untrack presence of %s: %v", userID, err)
+	}
+}
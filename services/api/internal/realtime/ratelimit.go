@@ -0,0 +1,234 @@
+package realtime
+
+import (
+	"log"
+	"maps"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+// rateLimit lets burst messages of a type through at once, refilling at
+// rate a second.
+type rateLimit struct {
+	rate  float64
+	burst int
+}
+
+// defaultRateLimits are the limits of the message types a space does not
+// override. Types not listed are not limited. Moves leave room above the
+// one step a tick anti-cheat allows, so only floods are dropped here.
+var defaultRateLimits = map[string]rateLimit{
+	MessageJoin:          {rate: 1, burst: 3},
+	MessageResume:        {rate: 1, burst: 3},
+	MessageMove:          {rate: 30, burst: 40},
+	MessageMoveTo:        {rate: 5, burst: 10},
+	MessageChat:          {rate: 2, burst: 5},
+	MessageEmote:         {rate: 2, burst: 5},
+	MessageDirectMessage: {rate: 2, burst: 5},
+	MessageKick:          {rate: 1, burst: 5},
+	MessageMute:          {rate: 1, burst: 5},
+	MessageBan:           {rate: 1, burst: 5},
+	messageInvalid:       {rate: 1, burst: 5},
+}
+
+// messageInvalid is the bucket shared by the frames that cannot be decoded
+// and the messages of unknown types, so replying to them is limited too.
+const messageInvalid = "invalid"
+
+// A client whose messages keep being dropped is muted for floodMute once
+// floodMuteStrikes of them are within floodWindow, and disconnected at
+// floodKickStrikes.
+const (
+	floodWindow      = 10 * time.Second
+	floodMuteStrikes = 10
+	floodKickStrikes = 30
+	floodMute        = 30 * time.Second
+)
+
+const floodReason = "flooding"
+
+// bucket holds the tokens left for one message type as of at.
+type bucket struct {
+	limit  rateLimit
+	tokens float64
+	at     time.Time
+
+	// warned is set once the client has been told the bucket ran dry, until
+	// a message gets through again.
+	warned bool
+}
+
+// take spends a token if there is one, or returns how long until there is.
+func (b *bucket) take(now time.Time) (time.Duration, bool) {
+	b.tokens = min(float64(b.limit.burst), b.tokens+now.Sub(b.at).Seconds()*b.limit.rate)
+	b.at = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - b.tokens) / b.limit.rate * float64(time.Second)), false
+}
+
+// limiter holds a client's buckets and the dropped messages counting
+// towards a mute or disconnect. It is guarded by the client's actions.
+type limiter struct {
+	limits  map[string]rateLimit
+	buckets map[string]*bucket
+
+	// overrides and role are what limits was configured from.
+	overrides []service.SpaceRateLimit
+	role      string
+
+	strikes      int
+	strikesSince time.Time
+}
+
+// configure applies the limits of a space to a user with role in it. The
+// tokens spent so far carry over, so moving between spaces refills
+// nothing.
+func (l *limiter) configure(overrides []service.SpaceRateLimit, role string) {
+	limits := maps.Clone(defaultRateLimits)
+	for _, o := range overrides {
+		if o.Role == "" {
+			limits[o.Message] = rateLimit{rate: o.Rate, burst: o.Burst}
+		}
+	}
+	for _, o := range overrides {
+		if o.Role != "" && o.Role == role {
+			limits[o.Message] = rateLimit{rate: o.Rate, burst: o.Burst}
+		}
+	}
+	l.limits = limits
+	l.overrides, l.role = overrides, role
+
+	for msgType, b := range l.buckets {
+		b.limit = limits[msgType]
+		b.tokens = min(b.tokens, float64(b.limit.burst))
+	}
+}
+
+// take spends a token of msgType's bucket. A message that is refused
+// returns how long until one is let through, whether the client has yet to
+// be told and how many messages it had refused within the flood window.
+func (l *limiter) take(msgType string, now time.Time) (retry time.Duration, ok, warn bool, strikes int) {
+	limits := l.limits
+	if limits == nil {
+		limits = defaultRateLimits
+	}
+	limit, limited := limits[msgType]
+	if !limited {
+		return 0, true, false, 0
+	}
+
+	b := l.buckets[msgType]
+	if b == nil {
+		if l.buckets == nil {
+			l.buckets = make(map[string]*bucket)
+		}
+		b = &bucket{limit: limit, tokens: float64(limit.burst), at: now}
+		l.buckets[msgType] = b
+	}
+
+	retry, ok = b.take(now)
+	if ok {
+		b.warned = false
+		return 0, true, false, 0
+	}
+	warn, b.warned = !b.warned, true
+
+	if now.Sub(l.strikesSince) > floodWindow {
+		l.strikes = 0
+		l.strikesSince = now
+	}
+	l.strikes++
+
+	return retry, false, warn, l.strikes
+}
+
+// allow reports whether c may send a message of msgType now. A message that
+// is not is dropped and counted against c, which is told the first time a
+// type runs dry, muted if it keeps on and in the end disconnected.
+// c.actions must be held.
+func (c *Client) allow(msgType string) bool {
+	now := time.Now()
+	retry, ok, warn, strikes := c.limits.take(msgType, now)
+	if ok {
+		return true
+	}
+
+	c.server.drop(msgType)
+	if warn {
+		c.notify(EventRateLimited, rateLimitedPayload{Type: msgType, RetryAfter: uint32(retry.Milliseconds())})
+	}
+
+	switch strikes {
+	case floodMuteStrikes:
+		c.floodMuted(now.Add(floodMute))
+	case floodKickStrikes:
+		c.floodKicked()
+	}
+	return false
+}
+
+// floodMuted keeps c from chatting until until. c.actions must be held.
+func (c *Client) floodMuted(until time.Time) {
+	c.server.floodMutes.Add(1)
+	c.floodMutedUntil.Store(until.UnixNano())
+
+	p := sanctionPayload{Reason: floodReason, Until: until.Format(time.RFC3339)}
+	if c.room != nil {
+		p.SpaceID = c.room.spaceID
+	}
+	c.send(EventMuted, p)
+}
+
+// floodKicked disconnects c for flooding. c.actions must be held.
+func (c *Client) floodKicked() {
+	c.server.floodKicks.Add(1)
+
+	p := sanctionPayload{Reason: floodReason}
+	if c.room != nil {
+		p.SpaceID = c.room.spaceID
+		log.Printf("ratelimit: kicked %s from space %s", c.userID, p.SpaceID)
+	}
+	c.send(EventKicked, p)
+	c.close()
+}
+
+// RateLimitsChanged applies spaceID's new rate limit overrides to the
+// clients in its room wherever one is live.
+func (s *Server) RateLimitsChanged(spaceID string, limits []service.SpaceRateLimit) {
+	if room := s.room(spaceID); room != nil {
+		room.setRateLimits(limits)
+	}
+	s.publishSpace(spaceID, roomEvent{Kind: roomRateLimits, RateLimits: limits})
+}
+
+// setRateLimits reconfigures the limiters of the clients connected here
+// that are in or waiting for r.
+func (r *Room) setRateLimits(limits []service.SpaceRateLimit) {
+	for _, c := range r.localClients() {
+		c.actions.Lock()
+		if c.room == r {
+			c.limits.configure(limits, c.limits.role)
+		}
+		c.actions.Unlock()
+	}
+}
+
+// drop counts a message refused by its rate limit.
+func (s *Server) drop(msgType string) {
+	s.droppedMu.Lock()
+	s.dropped[msgType]++
+	s.droppedMu.Unlock()
+}
+
+// droppedMessages returns how many messages of each type were refused.
+func (s *Server) droppedMessages() map[string]int64 {
+	s.droppedMu.Lock()
+	defer s.droppedMu.Unlock()
+
+	return maps.Clone(s.dropped)
+}
@@ -0,0 +1,246 @@
+package realtime
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// DefaultResumeGrace is how long a client that lost its connection keeps
+// its place, unless the server is given another grace period.
+const DefaultResumeGrace = 30 * time.Second
+
+// replayLimit is how many of the latest events a session keeps for
+// replaying on resume. A client that missed more has to join again.
+const replayLimit = 256
+
+var (
+	errSessionExpired = errors.New("session expired")
+	errEventsLost     = errors.New("missed events are no longer available")
+)
+
+// session is what lets a client be resumed on a new connection: the token
+// handed out for it and the events last sent to it, numbered by seq.
+type session struct {
+	token  string
+	seq    uint64
+	sent   []Message
+	closed bool
+
+	// expiry is running while the client waits to be resumed.
+	expiry *time.Timer
+}
+
+// record numbers msg and keeps it for replaying.
+func (s *session) record(msg Message) Message {
+	s.seq++
+	msg.Seq = s.seq
+
+	s.sent = append(s.sent, msg)
+	if len(s.sent) > replayLimit {
+		s.sent = s.sent[1:]
+	}
+	return msg
+}
+
+// since returns the events sent after seq, or false if some of them are
+// no longer kept.
+func (s *session) since(seq uint64) ([]Message, bool) {
+	if seq > s.seq || s.seq-seq > uint64(len(s.sent)) {
+		return nil, false
+	}
+	return s.sent[len(s.sent)-int(s.seq-seq):], true
+}
+
+func newResumeToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// SetResumeGrace sets how long a client that lost its connection stays in
+// its space, seen by everyone as if nothing happened, for a new connection
+// to resume it. Zero takes clients out as soon as they drop.
+func (s *Server) SetResumeGrace(d time.Duration) {
+	s.resumeGrace = d
+}
+
+// issueToken hands c a resume token. s.mu must be held.
+func (s *Server) issueToken(c *Client) error {
+	token, err := newResumeToken()
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	previous := c.session.token
+	c.session.token = token
+	c.writeMu.Unlock()
+
+	delete(s.sessions, previous)
+	s.sessions[token] = c
+	return nil
+}
+
+// resumeToken returns the token c can be resumed with.
+func (c *Client) resumeToken() string {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return c.session.token
+}
+
+// connection returns c's connection, or nil while it waits to be resumed.
+func (c *Client) connection() *outbox {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return c.out
+}
+
+// detach keeps c in its room without a connection after its connection
+// dropped. It reports false if c has to leave instead.
+func (s *Server) detach(c *Client) bool {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.session.closed || !c.admitted.Load() || s.resumeGrace <= 0 {
+		return false
+	}
+
+	c.out = nil
+	c.session.expiry = time.AfterFunc(s.resumeGrace, func() { s.expire(c) })
+	return true
+}
+
+// expire takes c out of its room if it is still waiting to be resumed.
+func (s *Server) expire(c *Client) {
+	c.actions.Lock()
+	defer c.actions.Unlock()
+
+	c.writeMu.Lock()
+	waiting := c.out == nil && c.session.expiry != nil
+	if waiting {
+		c.session.expiry.Stop()
+		c.session.expiry = nil
+		c.session.closed = true
+	}
+	c.writeMu.Unlock()
+
+	if waiting {
+		s.leave(c)
+	}
+}
+
+// resume moves out, which c has spoken for so far, over to the session p
+// names, and returns that session's client. A session whose space was
+// migrated here is instead taken up by c itself. c must not have joined a
+// space.
+func (s *Server) resume(c *Client, out *outbox, payload json.RawMessage) *Client {
+	var p resumePayload
+	if err := json.Unmarshal(payload, &p); err != nil || p.Token == "" {
+		c.sendError("invalid resume payload")
+		return nil
+	}
+
+	c.actions.Lock()
+	joined := c.room != nil
+	c.actions.Unlock()
+	if joined {
+		c.sendError("already joined a space")
+		return nil
+	}
+
+	s.mu.Lock()
+	resumed := s.sessions[p.Token]
+	moved, migrated := s.arrivalToken(p.Token)
+	s.mu.Unlock()
+	if resumed == nil && migrated {
+		s.resumeMigrated(c, moved)
+		return nil
+	}
+	if resumed == nil {
+		c.sendError(errSessionExpired.Error())
+		return nil
+	}
+
+	resumed.actions.Lock()
+	defer resumed.actions.Unlock()
+
+	// A walk does not carry over to the new connection.
+	r := resumed.room
+	r.mu.Lock()
+	resumed.path = nil
+	position := point{X: resumed.x, Y: resumed.y}
+	r.mu.Unlock()
+
+	token, err := newResumeToken()
+	if err != nil {
+		c.sendError("failed to resume session")
+		return nil
+	}
+
+	previous, err := resumed.attach(out, p.LastSeq, resumedPayload{
+		SpaceID:     r.spaceID,
+		ResumeToken: token,
+		Position:    position,
+	})
+	if err != nil {
+		c.sendError(err.Error())
+		return nil
+	}
+
+	// The old connection may not have noticed it dropped yet.
+	if previous != nil {
+		previous.close()
+	}
+
+	s.mu.Lock()
+	delete(s.sessions, p.Token)
+	s.sessions[token] = resumed
+	s.mu.Unlock()
+
+	return resumed
+}
+
+// attach puts c on out, tells the client it resumed and replays the events
+// sent after lastSeq. It returns c's previous connection, if any.
+func (c *Client) attach(out *outbox, lastSeq uint64, resumed resumedPayload) (*outbox, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.session.closed {
+		return nil, errSessionExpired
+	}
+
+	missed, ok := c.session.since(lastSeq)
+	if !ok {
+		return nil, errEventsLost
+	}
+
+	resumed.LastSeq = c.session.seq
+	data, err := json.Marshal(resumed)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.session.expiry != nil {
+		c.session.expiry.Stop()
+		c.session.expiry = nil
+	}
+
+	previous := c.out
+	c.out = out
+	c.session.token = resumed.ResumeToken
+
+	out.push(outgoing{msg: Message{Type: EventResumed, Payload: data}})
+	for _, msg := range missed {
+		out.push(outgoing{msg: msg})
+	}
+
+	return previous, nil
+}
@@ -0,0 +1,315 @@
+package realtime
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/navigation"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+// Room holds every client connected to one space, and those waiting for a
+// place once it is full.
+type Room struct {
+	server  *Server
+	spaceID string
+	width   int
+	height  int
+
+	mu       sync.RWMutex
+	capacity int
+	zones    []service.Zone
+	portals  []service.Portal
+	clients  map[string]*Client
+	queue    []queued
+
+	// view holds the admitted clients by position, and wide those of them
+	// that see the whole room.
+	view interestGrid
+	wide map[*Client]struct{}
+
+	// inputs are the moves queued for the next tick. Walks take a step
+	// every stepTicks ticks.
+	inputs    []input
+	ticks     uint64
+	stepTicks uint64
+	stop      chan struct{}
+
+	// Idle users are looked for every afkTicks ticks.
+	afkTicks uint64
+
+	// grid is covered by every obstacle in obstacles, which are keyed by
+	// id.
+	grid      *navigation.Grid
+	obstacles map[string]service.Obstacle
+}
+
+// queued is a client waiting for a place. Among equal priorities, clients
+// with an order, who kept their place in the queue of a migrated space, go
+// by it; everyone else waits behind them.
+type queued struct {
+	client   *Client
+	priority int
+	order    int
+}
+
+// admission is a client let into the room together with the users it sees,
+// those idle and the zones it starts in. The watchers are the users that
+// see it.
+type admission struct {
+	client   *Client
+	at       time.Time
+	users    []userPosition
+	idle     []string
+	watchers []*Client
+	zones    []zoneChange
+}
+
+func newRoom(s *Server, spaceID string, width, height int, l layout) *Room {
+	r := &Room{
+		server:    s,
+		spaceID:   spaceID,
+		width:     width,
+		height:    height,
+		zones:     l.zones,
+		portals:   l.portals,
+		clients:   make(map[string]*Client),
+		view:      make(interestGrid),
+		wide:      make(map[*Client]struct{}),
+		stepTicks: uint64(max(1, stepInterval/s.tick)),
+		afkTicks:  uint64(max(1, afkCheckInterval/s.tick)),
+		stop:      make(chan struct{}),
+		grid:      navigation.NewGrid(width, height),
+		obstacles: make(map[string]service.Obstacle),
+	}
+
+	for _, o := range l.obstacles {
+		r.addObstacle(o)
+	}
+
+	return r
+}
+
+func (r *Room) inBounds(x, y int) bool {
+	return x >= 0 && y >= 0 && x < r.width && y < r.height
+}
+
+// enter admits c if the room has space and nobody is waiting, and otherwise
+// queues it behind every client of higher priority, and of equal priority
+// unless c has an earlier order. A reconnecting user keeps their place. It
+// returns the clients admitted as a result, which may include others if
+// capacity grew, and c's 1-based queue position when it has to wait.
+func (r *Room) enter(c *Client, priority, order, capacity int) ([]admission, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.capacity = capacity
+
+	if _, ok := r.clients[c.userID]; ok {
+		return []admission{r.admit(c)}, 0
+	}
+
+	i := slices.IndexFunc(r.queue, func(q queued) bool { return q.client.userID == c.userID })
+	if i >= 0 {
+		r.queue[i].client = c
+	} else {
+		i = slices.IndexFunc(r.queue, func(q queued) bool {
+			return q.priority < priority || q.priority == priority && order > 0 && (q.order == 0 || q.order > order)
+		})
+		if i < 0 {
+			i = len(r.queue)
+		}
+		r.queue = slices.Insert(r.queue, i, queued{client: c, priority: priority, order: order})
+	}
+
+	admitted := r.fill()
+	if c.admitted.Load() {
+		return admitted, 0
+	}
+
+	return admitted, slices.IndexFunc(r.queue, func(q queued) bool { return q.client == c }) + 1
+}
+
+// remove takes c out of the room or its queue and admits waiting clients
+// into any freed places. It also returns the users that had c in view and
+// reports whether the room is now empty of clients connected here.
+func (r *Room) remove(c *Client) ([]admission, []*Client, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var watchers []*Client
+	if r.clients[c.userID] == c {
+		watchers = r.watching(c.x, c.y, c)
+		delete(r.clients, c.userID)
+		r.view.remove(c, c.x, c.y)
+		delete(r.wide, c)
+	}
+	r.queue = slices.DeleteFunc(r.queue, func(q queued) bool { return q.client == c })
+
+	admitted := r.fill()
+	return admitted, watchers, len(r.queue) == 0 && !r.hasLocalClients()
+}
+
+// hasLocalClients reports whether any client in the room is connected to
+// this node. r.mu must be held.
+func (r *Room) hasLocalClients() bool {
+	for _, c := range r.clients {
+		if c.node == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// fill admits clients from the head of the queue while there is space. r.mu
+// must be held.
+func (r *Room) fill() []admission {
+	var admitted []admission
+	for len(r.queue) > 0 && len(r.clients) < r.capacity {
+		c := r.queue[0].client
+		r.queue = r.queue[1:]
+		admitted = append(admitted, r.admit(c))
+	}
+	return admitted
+}
+
+// admit places c in the room. A client connected here keeps the time it
+// was first admitted across reconnects; a remote one comes with its own.
+// r.mu must be held.
+func (r *Room) admit(c *Client) admission {
+	previous := r.clients[c.userID]
+	if previous != nil {
+		r.view.remove(previous, previous.x, previous.y)
+		delete(r.wide, previous)
+	}
+	if c.node == "" {
+		if previous != nil && previous.node == "" {
+			c.admittedAt = previous.admittedAt
+		} else {
+			c.admittedAt = time.Now()
+		}
+	}
+
+	watchers := r.watching(c.x, c.y, c)
+	users := r.seenBy(c)
+
+	r.clients[c.userID] = c
+	r.view.add(c, c.x, c.y)
+	if c.seesAll() {
+		r.wide[c] = struct{}{}
+	}
+	c.steps, c.stepsAt = maxStepBurst, r.ticks
+	c.lastActive.Store(time.Now().UnixNano())
+	c.admitted.Store(true)
+
+	return admission{
+		client:   c,
+		at:       c.admittedAt,
+		users:    users,
+		idle:     r.idleUsers(),
+		watchers: watchers,
+		zones:    r.locate(c, c.x, c.y, nil),
+	}
+}
+
+// sendQueuePositions tells every waiting client where it is in the queue.
+func (r *Room) sendQueuePositions() {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i, q := range r.queue {
+		q.client.send(EventQueuePosition, queuePositionPayload{
+			SpaceID:  r.spaceID,
+			Position: i + 1,
+			Size:     len(r.queue),
+		})
+	}
+}
+
+// broadcast sends an event to every client except from, skipping those for
+// which skip returns true.
+func (r *Room) broadcast(from *Client, eventType string, payload any, skip func(*Client) bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, c := range r.clients {
+		if c == from || (skip != nil && skip(c)) {
+			continue
+		}
+		c.send(eventType, payload)
+	}
+}
+
+// move queues a move of c for the next tick, ending any walk in progress.
+// The tick rejects points c may not stand on and sends c through any portal
+// it steps on.
+func (r *Room) move(c *Client, x, y int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c.path = nil
+	r.inputs = append(r.inputs, input{client: c, x: x, y: y})
+}
+
+// teleport queues c to be put at x, y on the next tick.
+func (r *Room) teleport(c *Client, x, y int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c.path = nil
+	r.inputs = append(r.inputs, input{client: c, x: x, y: y, teleport: true})
+}
+
+// chat only reaches users in the same chat-scoped zone as the sender, or
+// outside every such zone if the sender is too.
+func (r *Room) chat(c *Client, message string) {
+	r.mu.RLock()
+	chatZone := c.chatZone
+	r.mu.RUnlock()
+
+	payload := userChatPayload{UserID: c.userID, Message: message}
+	r.broadcast(c, EventChat, payload, func(to *Client) bool {
+		return to.chatZone != chatZone || to.hides(c.userID)
+	})
+
+	r.server.publish(r, roomEvent{Kind: roomChat, UserID: c.userID, ChatZone: chatZone, Text: message})
+}
+
+func (r *Room) emote(c *Client, emote string) {
+	payload := userEmotePayload{UserID: c.userID, Emote: emote}
+	r.broadcast(c, EventEmote, payload, func(to *Client) bool {
+		return to.hides(c.userID)
+	})
+
+	r.server.publish(r, roomEvent{Kind: roomEmote, UserID: c.userID, Text: emote})
+}
+
+func (r *Room) directMessage(c *Client, toUserID, message string) {
+	r.mu.RLock()
+	to := r.clients[toUserID]
+	r.mu.RUnlock()
+
+	if to == nil {
+		c.sendError("user is not in this space")
+		return
+	}
+
+	// Blocks apply in both directions; a mute only hides the sender. The
+	// node of a remote user checks its side.
+	if c.blocks(toUserID) || to.blocks(c.userID) {
+		c.sendError("user is blocked")
+		return
+	}
+
+	if to.node != "" {
+		r.server.publish(r, roomEvent{Kind: roomDirectMessage, UserID: c.userID, To: toUserID, Text: message})
+		return
+	}
+
+	if to.hides(c.userID) {
+		return
+	}
+
+	to.send(EventDirectMessage, userChatPayload{UserID: c.userID, Message: message})
+}
@@ -0,0 +1,456 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+	"github.com/vaxxnsh/metaverse/api/internal/sharding"
+	"github.com/vaxxnsh/metaverse/api/wire"
+)
+
+// SpaceStore checks that a user may enter a space and returns it.
+type SpaceStore interface {
+	EnterSpace(ctx context.Context, userID, spaceID, password string) (*service.Space, error)
+	ReenterSpace(ctx context.Context, userID, spaceID string) (*service.Space, error)
+	MemberRole(ctx context.Context, spaceID, userID string) (string, error)
+}
+
+// Server accepts WebSocket connections and routes them into per-space rooms.
+type Server struct {
+	upgrader websocket.Upgrader
+	secret   string
+	users    middleware.UserStore
+
+	spaces    SpaceStore
+	blocks    BlockStore
+	zones     ZoneStore
+	portals   PortalStore
+	obstacles ObstacleStore
+	presence  PresenceStore
+
+	moderation Moderation
+
+	// broker shares rooms with the other nodes, which know this one as
+	// node.
+	broker Broker
+	node   string
+
+	// nodes is the registry through which the nodes share spaces out,
+	// and url where clients reach this one. ring is the live nodes as of
+	// the last heartbeat, which nodesMu serializes, and draining takes
+	// this node out of it.
+	nodes    NodeStore
+	url      string
+	nodesMu  sync.Mutex
+	ring     atomic.Pointer[sharding.Ring]
+	draining atomic.Bool
+
+	// tick is how often rooms apply moves, autoKick how many movement
+	// violations get a client kicked and resumeGrace how long a dropped
+	// client waits to be resumed. sendQueue and slowClientTimeout bound
+	// how far behind a connection may fall, pingInterval and pingTimeout
+	// how long a dead one goes unnoticed, and afkTimeout how long a user
+	// may send nothing before being shown as idle.
+	tick              time.Duration
+	autoKick          int
+	resumeGrace       time.Duration
+	sendQueue         int
+	slowClientTimeout time.Duration
+	pingInterval      time.Duration
+	pingTimeout       time.Duration
+	afkTimeout        time.Duration
+
+	mu      sync.Mutex
+	rooms   map[string]*Room
+	clients map[string]*Client
+
+	// sessions holds the clients that joined a space by resume token.
+	sessions map[string]*Client
+
+	// arrivals holds where the users of spaces migrated here stood, by
+	// space and user, and arrivalTokens whose session each resume token
+	// that came with them resumes.
+	arrivals      map[string]map[string]arrival
+	arrivalTokens map[string]arrivalKey
+
+	// dropped counts the messages refused by rate limits, by type, and
+	// floodMutes and floodKicks the clients muted and disconnected for
+	// flooding.
+	droppedMu  sync.Mutex
+	dropped    map[string]int64
+	floodMutes atomic.Int64
+	floodKicks atomic.Int64
+}
+
+func NewServer(
+	secret string,
+	users middleware.UserStore,
+	blocks BlockStore,
+	zones ZoneStore,
+	portals PortalStore,
+	obstacles ObstacleStore,
+	presence PresenceStore,
+) *Server {
+	return &Server{
+		upgrader: websocket.Upgrader{
+			Subprotocols: wire.Subprotocols,
+			CheckOrigin:  func(r *http.Request) bool { return true },
+		},
+		secret:            secret,
+		users:             users,
+		blocks:            blocks,
+		zones:             zones,
+		portals:           portals,
+		obstacles:         obstacles,
+		presence:          presence,
+		node:              uuid.NewString(),
+		tick:              time.Second / DefaultTickRate,
+		autoKick:          DefaultAutoKick,
+		resumeGrace:       DefaultResumeGrace,
+		sendQueue:         DefaultSendQueue,
+		slowClientTimeout: DefaultSlowClientTimeout,
+		pingInterval:      DefaultPingInterval,
+		pingTimeout:       DefaultPingTimeout,
+		afkTimeout:        DefaultAFKTimeout,
+		rooms:             make(map[string]*Room),
+		clients:           make(map[string]*Client),
+		sessions:          make(map[string]*Client),
+		arrivals:          make(map[string]map[string]arrival),
+		arrivalTokens:     make(map[string]arrivalKey),
+		dropped:           make(map[string]int64),
+	}
+}
+
+// SetSpaces must be called before serving. It is separate from NewServer
+// because the space service also reports back to the server.
+func (s *Server) SetSpaces(spaces SpaceStore) {
+	s.spaces = spaces
+}
+
+// SetTickRate sets how many times a second rooms apply moves and send
+// state deltas. It only applies to rooms created afterwards.
+func (s *Server) SetTickRate(hz int) {
+	if hz > 0 {
+		s.tick = time.Second / time.Duration(hz)
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println("websocket upgrade failed:", err)
+		return
+	}
+
+	c := newClient(s, conn)
+	go c.readLoop()
+}
+
+func (s *Server) join(c *Client, p joinPayload) {
+	if c.room != nil {
+		c.sendError("already joined a space")
+		return
+	}
+
+	ctx := context.Background()
+
+	claims, err := middleware.Authenticate(ctx, s.secret, s.users, p.Token)
+	if err != nil {
+		if !errors.Is(err, middleware.ErrInvalidToken) {
+			log.Printf("authenticate join: %v", err)
+		}
+		c.sendError("invalid token")
+		c.close()
+		return
+	}
+
+	c.stateDeltas.Store(p.StateDeltas)
+	c.admin = claims.Role == service.RoleAdmin
+
+	space, err := s.spaces.EnterSpace(ctx, claims.UserID, p.SpaceID, p.Password)
+	if err != nil {
+		c.sendError(joinError(err))
+		return
+	}
+
+	ticket := s.spawnTicket(p.SpawnTicket, claims.UserID, space.ID)
+
+	if owner, ok := s.elsewhere(space.ID); ok {
+		redirect := redirectPayload{SpaceID: space.ID, URL: owner.URL}
+		if ticket != nil {
+			redirect.SpawnTicket = p.SpawnTicket
+		}
+		c.send(EventRedirect, redirect)
+		return
+	}
+
+	s.enter(ctx, c, claims.UserID, space, s.spawn(ticket))
+}
+
+// enter puts userID, speaking on c, into space once they are allowed in,
+// at spawn or where they stood if the space was just migrated here.
+// c.actions must be held.
+func (s *Server) enter(ctx context.Context, c *Client, userID string, space *service.Space, spawn *point) {
+	c.userID = userID
+	if err := c.loadRelations(ctx); err != nil {
+		c.userID = ""
+		c.sendError("failed to load block list")
+		return
+	}
+
+	// EnterSpace already refuses banned users; this catches a ban issued
+	// since and loads any mute.
+	allowed, err := c.loadSanctions(ctx, space.ID)
+	if err != nil || !allowed {
+		c.userID = ""
+		if err != nil {
+			c.sendError("failed to load sanctions")
+		}
+		return
+	}
+
+	role, err := s.spaces.MemberRole(ctx, space.ID, c.userID)
+	if err != nil {
+		c.userID = ""
+		c.sendError(joinError(err))
+		return
+	}
+
+	l, err := s.loadLayout(ctx, space.ID)
+	if err != nil {
+		c.userID = ""
+		c.sendError(joinError(err))
+		return
+	}
+
+	s.mu.Lock()
+	if err := s.issueToken(c); err != nil {
+		s.mu.Unlock()
+		c.userID = ""
+		c.sendError("failed to join space")
+		return
+	}
+
+	if previous := s.clients[c.userID]; previous != nil {
+		// A user may only be connected once; the newest connection wins.
+		previous.close()
+	}
+	s.clients[c.userID] = c
+
+	// A user whose space was migrated here goes back where they stood,
+	// ahead of the queue, or to their place in it.
+	priority, order := queuePriority(role), 0
+	if a, ok := s.arrival(space.ID, c.userID); ok {
+		priority = arrivalPriority
+		if a.stateDeltas {
+			c.stateDeltas.Store(true)
+		}
+		c.admin = c.admin || a.admin
+		if a.queued > 0 {
+			priority, order = queuedArrivalPriority, a.queued
+		} else {
+			spawn = &a.at
+		}
+	}
+
+	admitted, position := s.enterRoom(c, space, role, l, spawn, priority, order)
+	s.mu.Unlock()
+
+	for _, a := range admitted {
+		s.admitted(a)
+	}
+
+	if position > 0 {
+		c.room.sendQueuePositions()
+	}
+}
+
+// layout is what a room loads from the database when it is created; live
+// rooms are kept current by ZonesChanged, PortalsChanged and the obstacle
+// events.
+type layout struct {
+	zones     []service.Zone
+	portals   []service.Portal
+	obstacles []service.Obstacle
+}
+
+func (s *Server) loadLayout(ctx context.Context, spaceID string) (layout, error) {
+	zones, err := s.zones.ListBySpace(ctx, spaceID)
+	if err != nil {
+		return layout{}, err
+	}
+
+	portals, err := s.portals.ListBySpace(ctx, spaceID)
+	if err != nil {
+		return layout{}, err
+	}
+
+	obstacles, err := s.obstacles.ListObstacles(ctx, spaceID)
+	if err != nil {
+		return layout{}, err
+	}
+
+	return layout{zones: zones, portals: portals, obstacles: obstacles}, nil
+}
+
+// enterRoom puts c into the room of space, creating it from l if needed, at
+// spawn or a random point, queueing it by priority and order if the room is
+// full. It returns the clients admitted as a result and c's queue position
+// if it has to wait. s.mu must be held.
+func (s *Server) enterRoom(c *Client, space *service.Space, role string, l layout, spawn *point, priority, order int) ([]admission, int) {
+	room := s.rooms[space.ID]
+	if room == nil {
+		room = newRoom(s, space.ID, space.Width, space.Height, l)
+		s.rooms[space.ID] = room
+		go s.runRoom(room)
+	}
+
+	c.room = room
+	c.role = role
+	c.limits.configure(space.RateLimits, role)
+	c.zones, c.chatZone = nil, ""
+	c.path = nil
+	c.x, c.y, c.spawnMoved = room.spawnPoint(c, spawn)
+
+	return room.enter(c, priority, order, space.MaxOccupancy())
+}
+
+// leaveRoom takes c out of its room, dropping the room once it is empty, and
+// returns the clients admitted into the freed place and those that had c in
+// view. s.mu must be held.
+func (s *Server) leaveRoom(c *Client) ([]admission, []*Client) {
+	admitted, watchers, empty := c.room.remove(c)
+	if empty && s.rooms[c.room.spaceID] == c.room {
+		delete(s.rooms, c.room.spaceID)
+		close(c.room.stop)
+	}
+	return admitted, watchers
+}
+
+// admitted announces a client that has just been given a place in its room.
+func (s *Server) admitted(a admission) {
+	c := a.client
+	s.track(c, c.room.spaceID)
+
+	c.send(EventSpaceJoined, spaceJoinedPayload{
+		UserID:      c.userID,
+		Spawn:       point{X: c.x, Y: c.y},
+		SpawnMoved:  c.spawnMoved,
+		Users:       a.users,
+		Idle:        a.idle,
+		ResumeToken: c.resumeToken(),
+	})
+
+	joined := userPosition{UserID: c.userID, X: c.x, Y: c.y}
+	sendAll(a.watchers, EventUserJoined, joined)
+	c.room.announceZones(a.zones)
+
+	s.publish(c.room, roomEvent{Kind: roomJoined, Users: []userPosition{joined}, At: a.at})
+}
+
+// disconnect handles out, which spoke for c, dropping. An admitted client
+// stays in its room for the resume grace period; any other leaves at once.
+func (s *Server) disconnect(c *Client, out *outbox) {
+	out.close()
+
+	// c has already been resumed on another connection if out is no
+	// longer its own.
+	if c.room == nil || c.connection() != out {
+		return
+	}
+
+	if !s.detach(c) {
+		s.leave(c)
+		return
+	}
+
+	// c stands still until it is resumed.
+	c.room.mu.Lock()
+	c.path = nil
+	c.room.mu.Unlock()
+}
+
+// leave takes c out of its room for good.
+func (s *Server) leave(c *Client) {
+	wasAdmitted := c.admitted.Load()
+
+	s.mu.Lock()
+	delete(s.sessions, c.resumeToken())
+	replacement := s.clients[c.userID]
+	if replacement == c {
+		delete(s.clients, c.userID)
+	}
+	admitted, watchers := s.leaveRoom(c)
+	reconnected := replacement != nil && replacement != c && replacement.room == c.room
+	s.mu.Unlock()
+
+	if replacement == c {
+		s.untrack(c.userID)
+	}
+
+	// A reconnect into the same space replaces the connection silently, and
+	// nobody saw a client that was still waiting in the queue.
+	if wasAdmitted && !reconnected {
+		sendAll(watchers, EventUserLeft, userLeftPayload{UserID: c.userID})
+		s.publish(c.room, roomEvent{Kind: roomLeft, UserID: c.userID})
+	}
+
+	for _, a := range admitted {
+		s.admitted(a)
+	}
+
+	if !reconnected {
+		c.room.sendQueuePositions()
+	}
+}
+
+// queuePriority orders a full space's queue so that moderators go first,
+// then members, then everyone else.
+func queuePriority(role string) int {
+	switch role {
+	case service.SpaceRoleOwner, service.SpaceRoleModerator:
+		return 2
+	case service.SpaceRoleMember:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// joinError is the message sent to a client whose join was refused.
+func joinError(err error) string {
+	switch err {
+	case service.ErrInvalidSpaceID, service.ErrSpaceNotFound:
+		return service.ErrSpaceNotFound.Error()
+	case service.ErrSpaceInviteOnly, service.ErrSpacePasswordRequired, service.ErrSpaceWrongPassword,
+		service.ErrSpaceBanned:
+		return err.Error()
+	default:
+		log.Println("join failed:", err)
+		return "failed to join space"
+	}
+}
+
+func (s *Server) client(userID string) *Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.clients[userID]
+}
+
+// room returns the live room of spaceID, or nil.
+func (s *Server) room(spaceID string) *Room {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rooms[spaceID]
+}
@@ -0,0 +1,129 @@
+package realtime
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/navigation"
+)
+
+// spawnTicketTTL is how long a spawn ticket can be joined with.
+const spawnTicketTTL = time.Minute
+
+// spawnTicket is a spawn point the server handed a user for joining a
+// space: where a portal or a migrated space puts them, or beside the user
+// they are joining. Clients only ever pass tickets back; a spawn point they
+// make up themselves would let them appear anywhere.
+type spawnTicket struct {
+	UserID  string `json:"userId"`
+	SpaceID string `json:"spaceId"`
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+
+	// Beside is the user to spawn next to, wherever they stand by the time
+	// the ticket is used, in place of X, Y.
+	Beside string `json:"beside,omitempty"`
+
+	Expiry int64 `json:"exp"`
+}
+
+// SignSpawn issues a ticket for userID to join spaceID at x, y.
+func SignSpawn(secret, userID, spaceID string, x, y int) string {
+	return signSpawn(secret, spawnTicket{UserID: userID, SpaceID: spaceID, X: x, Y: y})
+}
+
+// SpawnBeside issues a ticket for userID to join spaceID next to besideID.
+// It is resolved on the node serving the space when the ticket is used.
+func (s *Server) SpawnBeside(userID, spaceID, besideID string) string {
+	return signSpawn(s.secret, spawnTicket{UserID: userID, SpaceID: spaceID, Beside: besideID})
+}
+
+func signSpawn(secret string, t spawnTicket) string {
+	t.Expiry = time.Now().Add(spawnTicketTTL).Unix()
+
+	data, _ := json.Marshal(t)
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(spawnMAC(secret, payload))
+}
+
+// spawnMAC signs payload apart from JWTs made with the same secret, so that
+// neither can pass for the other.
+func spawnMAC(secret, payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("spawn." + payload))
+	return mac.Sum(nil)
+}
+
+// spawnTicket returns the spawn ticket in token if it is valid and was
+// issued for userID to join spaceID, or nil.
+func (s *Server) spawnTicket(token, userID, spaceID string) *spawnTicket {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, spawnMAC(s.secret, payload)) {
+		return nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil
+	}
+
+	var t spawnTicket
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil
+	}
+
+	if t.UserID != userID || t.SpaceID != spaceID || time.Now().Unix() > t.Expiry {
+		return nil
+	}
+	return &t
+}
+
+// spawn returns the point t puts its user at, or nil for a random one
+// because there is no ticket or the user it was to spawn beside is not in
+// a room of the space here.
+func (s *Server) spawn(t *spawnTicket) *point {
+	if t == nil {
+		return nil
+	}
+	if t.Beside == "" {
+		return &point{X: t.X, Y: t.Y}
+	}
+
+	r := s.room(t.SpaceID)
+	if r == nil {
+		return nil
+	}
+	return r.beside(t.Beside)
+}
+
+// beside returns the free tile nearest to where userID stands, for someone
+// joining them, or nil if userID is not here or has no free tile near.
+// Zones closed to non-members are avoided, as the joining user's role is not
+// known yet.
+func (r *Room) beside(userID string) *point {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c := r.clients[userID]
+	if c == nil {
+		return nil
+	}
+
+	at := navigation.Point{X: c.x, Y: c.y}
+	p, ok := navigation.Nearest(at, func(x, y int) bool {
+		return (x != at.X || y != at.Y) && r.walkable("", x, y)
+	})
+	if !ok {
+		return nil
+	}
+	return &point{X: p.X, Y: p.Y}
+}
@@ -0,0 +1,255 @@
+package realtime
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+// DefaultTickRate is how many times a second a room applies moves and sends
+// state deltas, unless the server is given another rate.
+const DefaultTickRate = 20
+
+// input is a move waiting for the room's next tick. Teleports are placed by
+// the server rather than asked for, so they skip the checks a move goes
+// through and never fire a portal.
+type input struct {
+	client   *Client
+	x        int
+	y        int
+	teleport bool
+}
+
+type rejection struct {
+	client  *Client
+	current point
+	reason  string
+}
+
+type traversal struct {
+	client *Client
+	portal *service.Portal
+}
+
+// run ticks the room every interval until it is stopped.
+func (r *Room) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.tick()
+		}
+	}
+}
+
+// tick applies the moves queued since the last tick in the order they
+// arrived, then the next step of every walk that is due, and sends each
+// client a single delta of what changed in its view. Every afkTicks it also
+// shows the users that went quiet as idle.
+func (r *Room) tick() {
+	r.mu.Lock()
+	r.ticks++
+	inputs := r.inputs
+	r.inputs = nil
+
+	t := &tickState{
+		room:   r,
+		now:    time.Now(),
+		to:     make(map[*Client]point),
+		halted: make(map[*Client]bool),
+	}
+	for _, in := range inputs {
+		t.apply(in)
+	}
+
+	for _, c := range r.dueWalkers() {
+		step := c.path[0]
+		c.path = c.path[1:]
+		c.nextStep = r.ticks + r.stepTicks
+
+		// A portal leaves c somewhere other than the step.
+		if !t.apply(input{client: c, x: step.X, y: step.Y}) || len(c.path) == 0 {
+			c.path = nil
+		}
+	}
+
+	deltas, zones := t.settle()
+	tick := r.ticks
+
+	var idle []*Client
+	if afk := r.server.afkTimeout; afk > 0 && tick%r.afkTicks == 0 {
+		idle = r.idleClients(t.now, afk)
+	}
+	r.mu.Unlock()
+
+	var moved []userPosition
+	for _, c := range t.moved {
+		if c.node != "" {
+			continue
+		}
+
+		p := t.to[c]
+		moved = append(moved, userPosition{UserID: c.userID, X: p.X, Y: p.Y})
+	}
+	r.server.publishMoved(r, moved)
+
+	for _, rej := range t.rejected {
+		rej.client.send(EventMovementRejected, movementRejectedPayload{
+			X:      rej.current.X,
+			Y:      rej.current.Y,
+			Reason: rej.reason,
+		})
+	}
+
+	for _, c := range t.kicked {
+		r.autoKicked(c)
+	}
+
+	for c, d := range deltas {
+		if d.empty() {
+			continue
+		}
+		c.sendDelta(d, tick)
+	}
+
+	r.announceZones(zones)
+	r.announceIdle(idle)
+
+	for _, tr := range t.travels {
+		go tr.client.server.traverse(tr.client, r, tr.portal)
+	}
+}
+
+// dueWalkers returns the clients whose walk takes a step this tick, in a
+// fixed order. r.mu must be held.
+func (r *Room) dueWalkers() []*Client {
+	var walkers []*Client
+	for _, c := range r.clients {
+		if len(c.path) > 0 && r.ticks >= c.nextStep {
+			walkers = append(walkers, c)
+		}
+	}
+
+	slices.SortFunc(walkers, func(a, b *Client) int {
+		return strings.Compare(a.userID, b.userID)
+	})
+	return walkers
+}
+
+// tickState collects the moves of one tick. Clients only take their final
+// position when the tick settles, so a client moving several times within a
+// tick is seen once.
+type tickState struct {
+	room *Room
+	now  time.Time
+
+	// moved holds the clients that moved, in the order they first did, and
+	// to where they ended up.
+	moved []*Client
+	to    map[*Client]point
+
+	rejected []rejection
+	travels  []traversal
+	kicked   []*Client
+
+	// halted holds the clients that went through a portal or were kicked,
+	// which make no further moves this tick.
+	halted map[*Client]bool
+}
+
+// apply validates a move and records it. It reports whether c may keep
+// moving this tick, which it may not once a move is rejected or takes it
+// onto a portal.
+func (t *tickState) apply(in input) bool {
+	r, c := t.room, in.client
+
+	// c may have left, gone through a portal or been kicked since queueing
+	// the move.
+	if r.clients[c.userID] != c || t.halted[c] {
+		return false
+	}
+
+	if !in.teleport {
+		if reason := r.checkStep(c, t.position(c), in.x, in.y); reason != "" {
+			t.reject(c, reason)
+			return false
+		}
+	}
+
+	if _, ok := t.to[c]; !ok {
+		t.moved = append(t.moved, c)
+	}
+	t.to[c] = point{X: in.x, Y: in.y}
+
+	if in.teleport {
+		return true
+	}
+
+	if portal := r.portalAt(in.x, in.y); portal != nil {
+		t.travels = append(t.travels, traversal{client: c, portal: portal})
+		t.halted[c] = true
+		return false
+	}
+	return true
+}
+
+// reject sends c back to its authoritative position. Running into a wall
+// is not held against c, as a client predicting moves against a layout
+// that just changed does that honestly.
+func (t *tickState) reject(c *Client, reason string) {
+	t.rejected = append(t.rejected, rejection{client: c, current: t.position(c), reason: reason})
+
+	if reason != rejectBlocked && c.violate(t.now) {
+		t.kicked = append(t.kicked, c)
+		t.halted[c] = true
+	}
+}
+
+// position is where c stands as of the moves applied so far.
+func (t *tickState) position(c *Client) point {
+	if p, ok := t.to[c]; ok {
+		return p
+	}
+	return point{X: c.x, Y: c.y}
+}
+
+// settle moves every client that moved to its final position and returns
+// what each client saw change and the zone boundaries crossed.
+func (t *tickState) settle() (deltas, []zoneChange) {
+	r := t.room
+	ds := make(deltas)
+	var zones []zoneChange
+
+	for _, c := range t.moved {
+		p := t.to[c]
+		if p.X == c.x && p.Y == c.y {
+			continue
+		}
+
+		r.changeView(c, p.X, p.Y).record(c, ds)
+		zones = append(zones, r.locate(c, p.X, p.Y, nil)...)
+	}
+
+	return ds, zones
+}
+
+// traverse sends c through a portal it stepped on in r, unless it has left
+// r since.
+func (s *Server) traverse(c *Client, r *Room, portal *service.Portal) {
+	c.actions.Lock()
+	defer c.actions.Unlock()
+
+	r.mu.RLock()
+	here := c.room == r && r.clients[c.userID] == c
+	r.mu.RUnlock()
+
+	if here {
+		s.travel(c, portal)
+	}
+}
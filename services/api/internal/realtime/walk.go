@@ -0,0 +1,44 @@
+package realtime
+
+import (
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/navigation"
+)
+
+// stepInterval is how long a walk spends on each tile.
+const stepInterval = 150 * time.Millisecond
+
+// moveTo walks c to x, y along the shortest path, replacing any walk in
+// progress. The room's tick takes the steps, each validated again like a
+// move, and the walk stops at the first rejected step or portal.
+func (c *Client) moveTo(x, y int) {
+	r := c.room
+	r.mu.Lock()
+	c.path = nil
+	start := navigation.Point{X: c.x, Y: c.y}
+	path := navigation.FindPath(start, navigation.Point{X: x, Y: y}, func(x, y int) bool {
+		return r.walkable(c.role, x, y)
+	})
+
+	steps := make([]point, len(path))
+	for i, p := range path {
+		steps[i] = point{X: p.X, Y: p.Y}
+	}
+	if len(steps) > 0 {
+		c.path = steps
+		c.nextStep = r.ticks + r.stepTicks
+	}
+	r.mu.Unlock()
+
+	if path == nil {
+		c.send(EventMovementRejected, movementRejectedPayload{
+			X:      start.X,
+			Y:      start.Y,
+			Reason: rejectUnreachable,
+		})
+		return
+	}
+
+	c.send(EventPath, pathPayload{Steps: steps})
+}
@@ -0,0 +1,169 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math/rand"
+	"slices"
+
+	"github.com/vaxxnsh/metaverse/api/internal/navigation"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+// ZoneStore loads the zones defined in a space.
+type ZoneStore interface {
+	ListBySpace(ctx context.Context, spaceID string) ([]service.Zone, error)
+}
+
+// spawnAttempts bounds the search for a random spawn point the user may
+// stand on.
+const spawnAttempts = 20
+
+// zoneChange is a client crossing a zone boundary.
+type zoneChange struct {
+	client  *Client
+	zone    *service.Zone
+	entered bool
+}
+
+// allows reports whether a user with role may stand at x, y. r.mu must be
+// held.
+func (r *Room) allows(role string, x, y int) bool {
+	for i := range r.zones {
+		if r.zones[i].Contains(x, y) && !r.zones[i].Admits(role) {
+			return false
+		}
+	}
+	return true
+}
+
+// spawnPoint picks where c appears: the requested point, or the nearest one
+// c may stand on if it is blocked, otherwise a random one. It reports
+// whether a requested point was moved.
+func (r *Room) spawnPoint(c *Client, requested *point) (int, int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	walkable := func(x, y int) bool { return r.walkable(c.role, x, y) }
+
+	if requested != nil {
+		if p, ok := navigation.Nearest(navigation.Point(*requested), walkable); ok {
+			return p.X, p.Y, p.X != requested.X || p.Y != requested.Y
+		}
+	}
+
+	x, y := rand.Intn(r.width), rand.Intn(r.height)
+	for i := 0; i < spawnAttempts && !walkable(x, y); i++ {
+		x, y = rand.Intn(r.width), rand.Intn(r.height)
+	}
+	return x, y, requested != nil
+}
+
+// locate updates the zones c is in for its position at x, y and returns the
+// boundaries it crossed. Zones c left that no longer exist are looked up in
+// removed. r.mu must be held for writing.
+func (r *Room) locate(c *Client, x, y int, removed []service.Zone) []zoneChange {
+	var (
+		changes  []zoneChange
+		inside   []string
+		chatZone string
+	)
+
+	for i := range r.zones {
+		z := &r.zones[i]
+		if !z.Contains(x, y) {
+			continue
+		}
+
+		inside = append(inside, z.ID)
+		if chatZone == "" && z.ChatScope == service.ZoneChatZone {
+			chatZone = z.ID
+		}
+		if !slices.Contains(c.zones, z.ID) {
+			changes = append(changes, zoneChange{client: c, zone: z, entered: true})
+		}
+	}
+
+	for _, id := range c.zones {
+		if slices.Contains(inside, id) {
+			continue
+		}
+		z := findZone(r.zones, id)
+		if z == nil {
+			z = findZone(removed, id)
+		}
+		if z == nil {
+			z = &service.Zone{ID: id}
+		}
+		changes = append(changes, zoneChange{client: c, zone: z, entered: false})
+	}
+
+	c.zones = inside
+	c.chatZone = chatZone
+	return changes
+}
+
+func findZone(zones []service.Zone, id string) *service.Zone {
+	for i := range zones {
+		if zones[i].ID == id {
+			return &zones[i]
+		}
+	}
+	return nil
+}
+
+// setZones replaces the room's zones and returns the boundaries every client
+// crossed as a result.
+func (r *Room) setZones(zones []service.Zone) []zoneChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed := r.zones
+	r.zones = zones
+
+	var changes []zoneChange
+	for _, c := range r.clients {
+		changes = append(changes, r.locate(c, c.x, c.y, removed)...)
+	}
+	return changes
+}
+
+// announceZones tells everyone in the room about boundary crossings.
+func (r *Room) announceZones(changes []zoneChange) {
+	for _, ch := range changes {
+		eventType := EventZoneLeft
+		if ch.entered {
+			eventType = EventZoneEntered
+		}
+
+		r.broadcast(nil, eventType, zoneEventPayload{
+			UserID:     ch.client.userID,
+			ZoneID:     ch.zone.ID,
+			Name:       ch.zone.Name,
+			ChatScope:  ch.zone.ChatScope,
+			Audio:      ch.zone.Audio,
+			Properties: json.RawMessage(ch.zone.Properties),
+		}, nil)
+	}
+}
+
+// ZonesChanged reloads the zones of spaceID into its room wherever one is
+// live.
+func (s *Server) ZonesChanged(spaceID string) {
+	if room := s.room(spaceID); room != nil {
+		s.reloadZones(room)
+	}
+	s.publishSpace(spaceID, roomEvent{Kind: roomZones})
+}
+
+// reloadZones loads room's zones from the store.
+func (s *Server) reloadZones(room *Room) {
+	zones, err := s.zones.ListBySpace(context.Background(), room.spaceID)
+	if err != nil {
+		log.Println("reload zones:", err)
+		return
+	}
+
+	room.announceZones(room.setZones(zones))
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type psqlBlockRepository struct {
+	queries *db.Queries
+}
+
+func NewBlockRepository(queries *db.Queries) *psqlBlockRepository {
+	return &psqlBlockRepository{
+		queries: queries,
+	}
+}
+
+func (r *psqlBlockRepository) Upsert(ctx context.Context, block *service.Block) error {
+	userID, err := toUUID(block.UserID)
+	if err != nil {
+		return err
+	}
+
+	targetID, err := toUUID(block.TargetID)
+	if err != nil {
+		return err
+	}
+
+	err = r.queries.UpsertUserBlock(ctx, db.UpsertUserBlockParams{
+		UserID:    userID,
+		BlockedID: targetID,
+		Kind:      block.Kind,
+		CreatedAt: toTimestamp(block.CreatedAt),
+	})
+	if isPgError(err, pgForeignKeyViolation) {
+		return service.ErrUserNotFound
+	}
+
+	return err
+}
+
+func (r *psqlBlockRepository) Delete(ctx context.Context, userID, targetID string) (bool, error) {
+	id, err := toUUID(userID)
+	if err != nil {
+		return false, err
+	}
+
+	blockedID, err := toUUID(targetID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.DeleteUserBlock(ctx, db.DeleteUserBlockParams{
+		UserID:    id,
+		BlockedID: blockedID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+func (r *psqlBlockRepository) ListByUser(ctx context.Context, userID string) ([]service.Block, error) {
+	id, err := toUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListUserBlocks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBlocks(rows), nil
+}
+
+func (r *psqlBlockRepository) ListInvolving(ctx context.Context, userID string) ([]service.Block, error) {
+	id, err := toUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListUserBlocksInvolving(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBlocks(rows), nil
+}
+
+func (r *psqlBlockRepository) IsBlocked(ctx context.Context, userID, otherID string) (bool, error) {
+	id, err := toUUID(userID)
+	if err != nil {
+		return false, err
+	}
+
+	other, err := toUUID(otherID)
+	if err != nil {
+		return false, err
+	}
+
+	return r.queries.IsBlocked(ctx, db.IsBlockedParams{
+		UserID:  id,
+		OtherID: other,
+	})
+}
+
+func toBlocks(rows []db.UserBlock) []service.Block {
+	blocks := make([]service.Block, 0, len(rows))
+	for _, row := range rows {
+		blocks = append(blocks, service.Block{
+			UserID:    uuidString(row.UserID),
+			TargetID:  uuidString(row.BlockedID),
+			Kind:      row.Kind,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+	return blocks
+}
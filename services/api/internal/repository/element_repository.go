@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type psqlElementRepository struct {
+	queries *db.Queries
+}
+
+func NewElementRepository(queries *db.Queries) *psqlElementRepository {
+	return &psqlElementRepository{
+		queries: queries,
+	}
+}
+
+func (r *psqlElementRepository) SoftDelete(ctx context.Context, id string, at time.Time) (bool, error) {
+	elementID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.SoftDeleteElement(ctx, db.SoftDeleteElementParams{
+		ID:        elementID,
+		DeletedAt: toTimestamp(at),
+	})
+	return n > 0, err
+}
+
+func (r *psqlElementRepository) Restore(ctx context.Context, id string) (bool, error) {
+	elementID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.RestoreElement(ctx, db.RestoreElementParams{
+		ID:        elementID,
+		UpdatedAt: toTimestamp(time.Now().UTC()),
+	})
+	return n > 0, err
+}
+
+func (r *psqlElementRepository) ListDeleted(ctx context.Context) ([]service.Element, error) {
+	rows, err := r.queries.ListDeletedElements(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]service.Element, 0, len(rows))
+	for _, row := range rows {
+		elements = append(elements, *toElement(row))
+	}
+
+	return elements, nil
+}
+
+func (r *psqlElementRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.PurgeDeletedElements(ctx, toTimestamp(before))
+}
+
+func (r *psqlElementRepository) Create(ctx context.Context, e *service.Element) error {
+	id, err := toUUID(e.ID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.CreateElement(ctx, db.CreateElementParams{
+		ID:        id,
+		ImageUrl:  e.ImageURL,
+		Width:     int32(e.Width),
+		Height:    int32(e.Height),
+		Static:    e.Static,
+		CreatedAt: toTimestamp(e.CreatedAt),
+		UpdatedAt: toTimestamp(e.UpdatedAt),
+	})
+}
+
+// GetByHash returns the oldest live element with the given content hash.
+func (r *psqlElementRepository) GetByHash(ctx context.Context, hash string) (*service.Element, error) {
+	row, err := r.queries.GetElementByHash(ctx, pgtype.Text{String: hash, Valid: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toElement(row), nil
+}
+
+// ListBySpace returns the elements placed in a space, including catalog
+// elements that have since been deleted.
+func (r *psqlElementRepository) ListBySpace(ctx context.Context, spaceID string) ([]service.Element, error) {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListSpaceCatalogElements(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]service.Element, 0, len(rows))
+	for _, row := range rows {
+		elements = append(elements, *toElement(db.Element(row)))
+	}
+
+	return elements, nil
+}
+
+func toElement(row db.Element) *service.Element {
+	return &service.Element{
+		ID:        uuidString(row.ID),
+		ImageURL:  row.ImageUrl,
+		Width:     int(row.Width),
+		Height:    int(row.Height),
+		Static:    row.Static,
+		CreatedAt: row.CreatedAt.Time,
+		UpdatedAt: row.UpdatedAt.Time,
+		DeletedAt: row.DeletedAt.Time,
+
+		ContentHash: row.ContentHash.String,
+	}
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type psqlFriendRepository struct {
+	queries *db.Queries
+}
+
+func NewFriendRepository(queries *db.Queries) *psqlFriendRepository {
+	return &psqlFriendRepository{
+		queries: queries,
+	}
+}
+
+// CreateRequest returns service.ErrUserNotFound if the receiver does not
+// exist or is in the trash.
+func (r *psqlFriendRepository) CreateRequest(ctx context.Context, req *service.FriendRequest) error {
+	id, err := toUUID(req.ID)
+	if err != nil {
+		return err
+	}
+
+	senderID, err := toUUID(req.SenderID)
+	if err != nil {
+		return err
+	}
+
+	receiverID, err := toUUID(req.ReceiverID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.queries.CreateFriendRequest(ctx, db.CreateFriendRequestParams{
+		ID:         id,
+		SenderID:   senderID,
+		ReceiverID: receiverID,
+		Status:     req.Status,
+		CreatedAt:  toTimestamp(req.CreatedAt),
+		UpdatedAt:  toTimestamp(req.UpdatedAt),
+	})
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows), isPgError(err, pgForeignKeyViolation):
+		return service.ErrUserNotFound
+	case isPgError(err, pgUniqueViolation):
+		return service.ErrFriendRequestExists
+	}
+
+	return err
+}
+
+func (r *psqlFriendRepository) GetRequest(ctx context.Context, id string) (*service.FriendRequest, error) {
+	requestID, err := toUUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.GetFriendRequest(ctx, requestID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toFriendRequest(row), nil
+}
+
+// UpdateRequestStatus only moves a pending request, returning
+// service.ErrFriendRequestResolved for one resolved meanwhile.
+func (r *psqlFriendRepository) UpdateRequestStatus(ctx context.Context, id, status string) (*service.FriendRequest, error) {
+	requestID, err := toUUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.UpdateFriendRequestStatus(ctx, db.UpdateFriendRequestStatusParams{
+		ID:        requestID,
+		Status:    status,
+		UpdatedAt: toTimestamp(time.Now().UTC()),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, service.ErrFriendRequestResolved
+	}
+	if isPgError(err, pgUniqueViolation) {
+		return nil, service.ErrFriendRequestExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toFriendRequest(row), nil
+}
+
+func (r *psqlFriendRepository) ListPendingRequests(ctx context.Context, userID string) ([]service.FriendRequest, error) {
+	id, err := toUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListPendingFriendRequests(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]service.FriendRequest, 0, len(rows))
+	for _, row := range rows {
+		requests = append(requests, *toFriendRequest(row))
+	}
+
+	return requests, nil
+}
+
+// ListFriends returns userID's friends, with the space of those whose
+// presence was refreshed after presentSince.
+func (r *psqlFriendRepository) ListFriends(ctx context.Context, userID string, presentSince time.Time) ([]service.Friend, error) {
+	id, err := toUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListFriends(ctx, db.ListFriendsParams{
+		UserID:       id,
+		PresentSince: toTimestamp(presentSince),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	friends := make([]service.Friend, 0, len(rows))
+	for _, row := range rows {
+		friends = append(friends, service.Friend{
+			ID:                 uuidString(row.ID),
+			Name:               row.Name,
+			AvatarID:           uuidString(row.AvatarID),
+			PresenceVisibility: row.PresenceVisibility,
+			FriendsSince:       row.FriendsSince.Time,
+			SpaceID:            uuidString(row.SpaceID),
+		})
+	}
+
+	return friends, nil
+}
+
+func (r *psqlFriendRepository) SetPresenceVisibility(ctx context.Context, userID, visibility string) error {
+	id, err := toUUID(userID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.UpdateUserPresenceVisibility(ctx, db.UpdateUserPresenceVisibilityParams{
+		ID:                 id,
+		PresenceVisibility: visibility,
+		UpdatedAt:          toTimestamp(time.Now().UTC()),
+	})
+}
+
+func toFriendRequest(row db.FriendRequest) *service.FriendRequest {
+	return &service.FriendRequest{
+		ID:         uuidString(row.ID),
+		SenderID:   uuidString(row.SenderID),
+		ReceiverID: uuidString(row.ReceiverID),
+		Status:     row.Status,
+		CreatedAt:  row.CreatedAt.Time,
+		UpdatedAt:  row.UpdatedAt.Time,
+	}
+}
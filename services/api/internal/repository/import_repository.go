@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type psqlImportRepository struct {
+	queries *db.Queries
+}
+
+func NewImportRepository(queries *db.Queries) *psqlImportRepository {
+	return &psqlImportRepository{
+		queries: queries,
+	}
+}
+
+func (r *psqlImportRepository) Create(ctx context.Context, imp *service.UserImport) error {
+	id, err := toUUID(imp.ID)
+	if err != nil {
+		return err
+	}
+
+	createdBy, err := toUUID(imp.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.queries.CreateUserImport(ctx, db.CreateUserImportParams{
+		ID:        id,
+		CreatedBy: createdBy,
+		Status:    imp.Status,
+		DryRun:    imp.DryRun,
+		CreatedAt: toTimestamp(imp.CreatedAt),
+		UpdatedAt: toTimestamp(imp.UpdatedAt),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range imp.Rows {
+		err := r.queries.CreateUserImportRow(ctx, db.CreateUserImportRowParams{
+			ImportID:  id,
+			RowNumber: int32(row.Number),
+			Name:      row.Name,
+			Email:     row.Email,
+			Role:      row.Role,
+			AvatarID:  row.AvatarID,
+			Status:    row.Status,
+			Message:   row.Message,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *psqlImportRepository) Get(ctx context.Context, id string) (*service.UserImport, error) {
+	importID, err := toUUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.GetUserImport(ctx, importID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListUserImportRows(ctx, importID)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := toUserImport(row)
+	imp.Rows = toUserImportRows(rows)
+
+	return &imp, nil
+}
+
+func (r *psqlImportRepository) ListUnfinished(ctx context.Context) ([]service.UserImport, error) {
+	rows, err := r.queries.ListUnfinishedUserImports(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := make([]service.UserImport, 0, len(rows))
+	for _, row := range rows {
+		imports = append(imports, toUserImport(row))
+	}
+
+	return imports, nil
+}
+
+func (r *psqlImportRepository) ListPendingRows(ctx context.Context, importID string) ([]service.UserImportRow, error) {
+	id, err := toUUID(importID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListPendingUserImportRows(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserImportRows(rows), nil
+}
+
+// Claim marks the import running unless another node already runs it,
+// which it has if it touched the import since staleBefore.
+func (r *psqlImportRepository) Claim(ctx context.Context, id string, staleBefore time.Time) (bool, error) {
+	importID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.ClaimUserImport(ctx, db.ClaimUserImportParams{
+		UpdatedAt:   toTimestamp(time.Now().UTC()),
+		ID:          importID,
+		StaleBefore: toTimestamp(staleBefore),
+	})
+	return n > 0, err
+}
+
+func (r *psqlImportRepository) UpdateStatus(ctx context.Context, id, status string, finishedAt time.Time) error {
+	importID, err := toUUID(id)
+	if err != nil {
+		return err
+	}
+
+	var finished pgtype.Timestamp
+	if !finishedAt.IsZero() {
+		finished = toTimestamp(finishedAt)
+	}
+
+	return r.queries.UpdateUserImportStatus(ctx, db.UpdateUserImportStatusParams{
+		ID:         importID,
+		Status:     status,
+		UpdatedAt:  toTimestamp(time.Now().UTC()),
+		FinishedAt: finished,
+	})
+}
+
+func (r *psqlImportRepository) UpdateRow(ctx context.Context, importID string, row *service.UserImportRow) error {
+	id, err := toUUID(importID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.UpdateUserImportRow(ctx, db.UpdateUserImportRowParams{
+		ImportID:  id,
+		RowNumber: int32(row.Number),
+		Status:    row.Status,
+		Message:   row.Message,
+	})
+}
+
+func toUserImport(row db.UserImport) service.UserImport {
+	return service.UserImport{
+		ID:         uuidString(row.ID),
+		CreatedBy:  uuidString(row.CreatedBy),
+		Status:     row.Status,
+		DryRun:     row.DryRun,
+		CreatedAt:  row.CreatedAt.Time,
+		UpdatedAt:  row.UpdatedAt.Time,
+		FinishedAt: row.FinishedAt.Time,
+	}
+}
+
+func toUserImportRows(rows []db.UserImportRow) []service.UserImportRow {
+	result := make([]service.UserImportRow, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, service.UserImportRow{
+			Number:   int(row.RowNumber),
+			Name:     row.Name,
+			Email:    row.Email,
+			Role:     row.Role,
+			AvatarID: row.AvatarID,
+			Status:   row.Status,
+			Message:  row.Message,
+		})
+	}
+	return result
+}
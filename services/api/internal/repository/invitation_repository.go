@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type psqlInvitationRepository struct {
+	pool    Beginner
+	queries *db.Queries
+}
+
+func NewInvitationRepository(pool Beginner, queries *db.Queries) *psqlInvitationRepository {
+	return &psqlInvitationRepository{
+		pool:    pool,
+		queries: queries,
+	}
+}
+
+func (r *psqlInvitationRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	return r.queries.UserEmailExists(ctx, email)
+}
+
+// Invite creates the invited user and inv for them in one transaction, so
+// that no user is left without an invitation. If a user with the same
+// email, compared case-insensitively, was invited before and has yet to
+// accept, user is filled in with theirs and inv replaces their pending
+// invitation. It reports false when the email belongs to anyone else.
+func (r *psqlInvitationRepository) Invite(
+	ctx context.Context,
+	user *service.User,
+	invitee service.Invitee,
+	inv *service.Invitation,
+) (bool, error) {
+
+	id, err := toUUID(user.ID)
+	if err != nil {
+		return false, err
+	}
+
+	var avatarID pgtype.UUID
+	if invitee.AvatarID != "" {
+		if avatarID, err = toUUID(invitee.AvatarID); err != nil {
+			return false, err
+		}
+	}
+
+	invited := false
+	err = inTx(ctx, r.pool, r.queries, func(q *db.Queries) error {
+		row, err := q.CreateInvitedUser(ctx, db.CreateInvitedUserParams{
+			ID:        id,
+			Name:      user.Name,
+			Email:     user.Email,
+			AvatarID:  avatarID,
+			Role:      invitee.Role,
+			CreatedAt: toTimestamp(user.CreatedAt),
+			UpdatedAt: toTimestamp(user.UpdatedAt),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			row, err = q.GetInvitedUserForUpdate(ctx, user.Email)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := q.DeletePendingUserInvitations(ctx, row.ID); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		*user = *toUser(row)
+		inv.UserID = user.ID
+
+		invitationID, err := toUUID(inv.ID)
+		if err != nil {
+			return err
+		}
+
+		err = q.CreateUserInvitation(ctx, db.CreateUserInvitationParams{
+			ID:        invitationID,
+			UserID:    row.ID,
+			TokenHash: inv.TokenHash,
+			ExpiresAt: toTimestamp(inv.ExpiresAt),
+			CreatedAt: toTimestamp(inv.CreatedAt),
+		})
+		if err != nil {
+			return err
+		}
+
+		invited = true
+		return nil
+	})
+
+	return invited, err
+}
+
+func (r *psqlInvitationRepository) GetPendingInvitation(ctx context.Context, tokenHash string) (*service.Invitation, error) {
+	row, err := r.queries.GetPendingUserInvitation(ctx, tokenHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.Invitation{
+		ID:        uuidString(row.ID),
+		UserID:    uuidString(row.UserID),
+		TokenHash: row.TokenHash,
+		ExpiresAt: row.ExpiresAt.Time,
+		CreatedAt: row.CreatedAt.Time,
+	}, nil
+}
+
+// AcceptInvitation claims the invitation and sets the password in one
+// transaction, claiming it first so a token can only ever be used once.
+func (r *psqlInvitationRepository) AcceptInvitation(
+	ctx context.Context,
+	inv *service.Invitation,
+	passwordHash string,
+	at time.Time,
+) (bool, error) {
+
+	id, err := toUUID(inv.ID)
+	if err != nil {
+		return false, err
+	}
+
+	userID, err := toUUID(inv.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	accepted := false
+	err = inTx(ctx, r.pool, r.queries, func(q *db.Queries) error {
+		n, err := q.AcceptUserInvitation(ctx, db.AcceptUserInvitationParams{
+			ID:         id,
+			AcceptedAt: toTimestamp(at),
+		})
+		if err != nil || n == 0 {
+			return err
+		}
+
+		err = q.SetUserPassword(ctx, db.SetUserPasswordParams{
+			ID:        userID,
+			Password:  passwordHash,
+			UpdatedAt: toTimestamp(at),
+		})
+		if err != nil {
+			return err
+		}
+
+		accepted = true
+		return nil
+	})
+
+	return accepted, err
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/sharding"
+)
+
+type psqlNodeRepository struct {
+	queries *db.Queries
+}
+
+func NewNodeRepository(queries *db.Queries) *psqlNodeRepository {
+	return &psqlNodeRepository{
+		queries: queries,
+	}
+}
+
+func (r *psqlNodeRepository) Heartbeat(ctx context.Context, node sharding.Node) error {
+	return r.queries.UpsertRealtimeNode(ctx, db.UpsertRealtimeNodeParams{
+		ID:          node.ID,
+		Url:         node.URL,
+		Draining:    node.Draining,
+		HeartbeatAt: toTimestamp(node.HeartbeatAt),
+	})
+}
+
+// ListLive returns the nodes with a heartbeat after since, draining or not.
+func (r *psqlNodeRepository) ListLive(ctx context.Context, since time.Time) ([]sharding.Node, error) {
+	rows, err := r.queries.ListLiveRealtimeNodes(ctx, toTimestamp(since))
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]sharding.Node, 0, len(rows))
+	for _, row := range rows {
+		nodes = append(nodes, sharding.Node{
+			ID:          row.ID,
+			URL:         row.Url,
+			Draining:    row.Draining,
+			HeartbeatAt: row.HeartbeatAt.Time,
+		})
+	}
+	return nodes, nil
+}
+
+func (r *psqlNodeRepository) Delete(ctx context.Context, id string) error {
+	return r.queries.DeleteRealtimeNode(ctx, id)
+}
+
+func (r *psqlNodeRepository) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.DeleteStaleRealtimeNodes(ctx, toTimestamp(before))
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type psqlPortalRepository struct {
+	queries *db.Queries
+}
+
+func NewPortalRepository(queries *db.Queries) *psqlPortalRepository {
+	return &psqlPortalRepository{
+		queries: queries,
+	}
+}
+
+func (r *psqlPortalRepository) Upsert(ctx context.Context, portal *service.Portal) error {
+	elementID, err := toUUID(portal.ElementID)
+	if err != nil {
+		return err
+	}
+
+	spaceID, err := toUUID(portal.SpaceID)
+	if err != nil {
+		return err
+	}
+
+	destinationID, err := toUUID(portal.DestinationID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.UpsertSpacePortal(ctx, db.UpsertSpacePortalParams{
+		ElementID:          elementID,
+		SpaceID:            spaceID,
+		DestinationSpaceID: destinationID,
+		DestinationX:       int32(portal.Spawn.X),
+		DestinationY:       int32(portal.Spawn.Y),
+		CreatedAt:          toTimestamp(portal.CreatedAt),
+		UpdatedAt:          toTimestamp(portal.UpdatedAt),
+	})
+}
+
+func (r *psqlPortalRepository) Delete(ctx context.Context, spaceID, elementID string) (bool, error) {
+	sid, err := toUUID(spaceID)
+	if err != nil {
+		return false, err
+	}
+
+	eid, err := toUUID(elementID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.DeleteSpacePortal(ctx, db.DeleteSpacePortalParams{
+		ElementID: eid,
+		SpaceID:   sid,
+	})
+	return n > 0, err
+}
+
+// ListBySpace skips portals whose element is in the trash.
+func (r *psqlPortalRepository) ListBySpace(ctx context.Context, spaceID string) ([]service.Portal, error) {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListSpacePortals(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	portals := make([]service.Portal, 0, len(rows))
+	for _, row := range rows {
+		portals = append(portals, service.Portal{
+			ElementID:     uuidString(row.ElementID),
+			SpaceID:       uuidString(row.SpaceID),
+			X:             int(row.X),
+			Y:             int(row.Y),
+			DestinationID: uuidString(row.DestinationSpaceID),
+			Spawn:         service.Point{X: int(row.DestinationX), Y: int(row.DestinationY)},
+			CreatedAt:     row.CreatedAt.Time,
+			UpdatedAt:     row.UpdatedAt.Time,
+		})
+	}
+
+	return portals, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type psqlPreferencesRepository struct {
+	queries *db.Queries
+}
+
+func NewPreferencesRepository(queries *db.Queries) *psqlPreferencesRepository {
+	return &psqlPreferencesRepository{
+		queries: queries,
+	}
+}
+
+func (r *psqlPreferencesRepository) Get(ctx context.Context, userID string) (*service.Preferences, error) {
+	id, err := toUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.GetUserPreferences(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toPreferences(row), nil
+}
+
+func (r *psqlPreferencesRepository) Insert(ctx context.Context, userID string, document []byte) (*service.Preferences, error) {
+	id, err := toUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.InsertUserPreferences(ctx, db.InsertUserPreferencesParams{
+		UserID:    id,
+		Document:  document,
+		UpdatedAt: toTimestamp(time.Now().UTC()),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if isPgError(err, pgForeignKeyViolation) {
+		return nil, service.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toPreferences(row), nil
+}
+
+func (r *psqlPreferencesRepository) Update(
+	ctx context.Context,
+	userID string,
+	document []byte,
+	expectedVersion int,
+) (*service.Preferences, error) {
+
+	id, err := toUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.UpdateUserPreferences(ctx, db.UpdateUserPreferencesParams{
+		UserID:          id,
+		Document:        document,
+		UpdatedAt:       toTimestamp(time.Now().UTC()),
+		ExpectedVersion: int32(expectedVersion),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toPreferences(row), nil
+}
+
+func toPreferences(row db.UserPreference) *service.Preferences {
+	return &service.Preferences{
+		UserID:    uuidString(row.UserID),
+		Version:   int(row.Version),
+		Document:  row.Document,
+		UpdatedAt: row.UpdatedAt.Time,
+	}
+}
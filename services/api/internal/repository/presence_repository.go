@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+)
+
+type psqlPresenceRepository struct {
+	queries *db.Queries
+}
+
+func NewPresenceRepository(queries *db.Queries) *psqlPresenceRepository {
+	return &psqlPresenceRepository{
+		queries: queries,
+	}
+}
+
+func (r *psqlPresenceRepository) Set(ctx context.Context, userID, spaceID, node string, at time.Time) error {
+	uid, err := toUUID(userID)
+	if err != nil {
+		return err
+	}
+
+	sid, err := toUUID(spaceID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.SetUserPresence(ctx, db.SetUserPresenceParams{
+		UserID:  uid,
+		SpaceID: sid,
+		NodeID:  node,
+		SeenAt:  toTimestamp(at),
+	})
+}
+
+// Remove drops userID's presence unless another node has taken it over.
+func (r *psqlPresenceRepository) Remove(ctx context.Context, userID, node string) error {
+	uid, err := toUUID(userID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.DeleteUserPresence(ctx, db.DeleteUserPresenceParams{
+		UserID: uid,
+		NodeID: node,
+	})
+}
+
+// Refresh writes the presence of every user in spaces, keyed by user id, as
+// seen by node at at.
+func (r *psqlPresenceRepository) Refresh(ctx context.Context, node string, spaces map[string]string, at time.Time) error {
+	userIDs := make([]pgtype.UUID, 0, len(spaces))
+	spaceIDs := make([]pgtype.UUID, 0, len(spaces))
+	for userID, spaceID := range spaces {
+		uid, err := toUUID(userID)
+		if err != nil {
+			return err
+		}
+
+		sid, err := toUUID(spaceID)
+		if err != nil {
+			return err
+		}
+
+		userIDs = append(userIDs, uid)
+		spaceIDs = append(spaceIDs, sid)
+	}
+
+	return r.queries.RefreshUserPresence(ctx, db.RefreshUserPresenceParams{
+		UserIds:  userIDs,
+		SpaceIds: spaceIDs,
+		NodeID:   node,
+		SeenAt:   toTimestamp(at),
+	})
+}
+
+func (r *psqlPresenceRepository) DeleteStale(ctx context.Context, before time.Time) error {
+	return r.queries.DeleteStaleUserPresence(ctx, toTimestamp(before))
+}
@@ -0,0 +1,447 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+func (r *psqlSpaceRepository) SetVisibility(ctx context.Context, id, visibility, passwordHash string) (bool, error) {
+	spaceID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.UpdateSpaceVisibility(ctx, db.UpdateSpaceVisibilityParams{
+		ID:           spaceID,
+		Visibility:   visibility,
+		PasswordHash: passwordHash,
+		UpdatedAt:    toTimestamp(time.Now().UTC()),
+	})
+	return n > 0, err
+}
+
+func (r *psqlSpaceRepository) SetCapacity(ctx context.Context, id string, capacity int) (bool, error) {
+	spaceID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	var c pgtype.Int4
+	if capacity > 0 {
+		c = pgtype.Int4{Int32: int32(capacity), Valid: true}
+	}
+
+	n, err := r.queries.UpdateSpaceCapacity(ctx, db.UpdateSpaceCapacityParams{
+		ID:        spaceID,
+		Capacity:  c,
+		UpdatedAt: toTimestamp(time.Now().UTC()),
+	})
+	return n > 0, err
+}
+
+func (r *psqlSpaceRepository) SetRateLimits(ctx context.Context, id string, limits []service.SpaceRateLimit) (bool, error) {
+	spaceID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	if limits == nil {
+		limits = []service.SpaceRateLimit{}
+	}
+	data, err := json.Marshal(limits)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.UpdateSpaceRateLimits(ctx, db.UpdateSpaceRateLimitsParams{
+		ID:         spaceID,
+		RateLimits: data,
+		UpdatedAt:  toTimestamp(time.Now().UTC()),
+	})
+	return n > 0, err
+}
+
+func (r *psqlSpaceRepository) GetMember(ctx context.Context, spaceID, userID string) (*service.SpaceMember, error) {
+	sid, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	uid, err := toUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.GetSpaceMember(ctx, db.GetSpaceMemberParams{
+		SpaceID: sid,
+		UserID:  uid,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toSpaceMember(row), nil
+}
+
+func (r *psqlSpaceRepository) ListMembers(ctx context.Context, spaceID string) ([]service.SpaceMember, error) {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListSpaceMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]service.SpaceMember, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, *toSpaceMember(row))
+	}
+
+	return members, nil
+}
+
+// AddMember leaves an existing membership, and its role, untouched.
+func (r *psqlSpaceRepository) AddMember(ctx context.Context, member *service.SpaceMember) error {
+	sid, err := toUUID(member.SpaceID)
+	if err != nil {
+		return err
+	}
+
+	uid, err := toUUID(member.UserID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.AddSpaceMember(ctx, db.AddSpaceMemberParams{
+		SpaceID:   sid,
+		UserID:    uid,
+		Role:      member.Role,
+		CreatedAt: toTimestamp(member.CreatedAt),
+	})
+}
+
+func (r *psqlSpaceRepository) UpsertMember(ctx context.Context, member *service.SpaceMember) (*service.SpaceMember, error) {
+	sid, err := toUUID(member.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	uid, err := toUUID(member.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.UpsertSpaceMember(ctx, db.UpsertSpaceMemberParams{
+		SpaceID:   sid,
+		UserID:    uid,
+		Role:      member.Role,
+		CreatedAt: toTimestamp(member.CreatedAt),
+	})
+	if isPgError(err, pgForeignKeyViolation) {
+		return nil, service.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toSpaceMember(row), nil
+}
+
+func (r *psqlSpaceRepository) RemoveMember(ctx context.Context, spaceID, userID string) (bool, error) {
+	sid, err := toUUID(spaceID)
+	if err != nil {
+		return false, err
+	}
+
+	uid, err := toUUID(userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.DeleteSpaceMember(ctx, db.DeleteSpaceMemberParams{
+		SpaceID: sid,
+		UserID:  uid,
+	})
+	return n > 0, err
+}
+
+func (r *psqlSpaceRepository) CreateInvitation(ctx context.Context, inv *service.SpaceInvitation) error {
+	id, err := toUUID(inv.ID)
+	if err != nil {
+		return err
+	}
+
+	spaceID, err := toUUID(inv.SpaceID)
+	if err != nil {
+		return err
+	}
+
+	createdBy, err := toUUID(inv.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	var expiresAt pgtype.Timestamp
+	if !inv.ExpiresAt.IsZero() {
+		expiresAt = toTimestamp(inv.ExpiresAt)
+	}
+
+	var maxUses pgtype.Int4
+	if inv.MaxUses > 0 {
+		maxUses = pgtype.Int4{Int32: int32(inv.MaxUses), Valid: true}
+	}
+
+	_, err = r.queries.CreateSpaceInvitation(ctx, db.CreateSpaceInvitationParams{
+		ID:        id,
+		SpaceID:   spaceID,
+		Code:      inv.Code,
+		CreatedBy: createdBy,
+		ExpiresAt: expiresAt,
+		MaxUses:   maxUses,
+		CreatedAt: toTimestamp(inv.CreatedAt),
+	})
+	return err
+}
+
+func (r *psqlSpaceRepository) ListInvitations(ctx context.Context, spaceID string) ([]service.SpaceInvitation, error) {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListSpaceInvitations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]service.SpaceInvitation, 0, len(rows))
+	for _, row := range rows {
+		invitations = append(invitations, *toSpaceInvitation(row))
+	}
+
+	return invitations, nil
+}
+
+func (r *psqlSpaceRepository) DeleteInvitation(ctx context.Context, spaceID, id string) (bool, error) {
+	sid, err := toUUID(spaceID)
+	if err != nil {
+		return false, err
+	}
+
+	invitationID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.DeleteSpaceInvitation(ctx, db.DeleteSpaceInvitationParams{
+		ID:      invitationID,
+		SpaceID: sid,
+	})
+	return n > 0, err
+}
+
+// GetInvitationByCode returns the invitation with code, or nil if there is
+// none.
+func (r *psqlSpaceRepository) GetInvitationByCode(ctx context.Context, code string) (*service.SpaceInvitation, error) {
+	row, err := r.queries.GetSpaceInvitationByCode(ctx, code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toSpaceInvitation(row), nil
+}
+
+// UseInvitation counts one use of the invitation with code and adds member
+// to its space in one transaction, so that no use is counted without the
+// membership. It returns nil if the invitation has expired or has no uses
+// left.
+func (r *psqlSpaceRepository) UseInvitation(ctx context.Context, code string, member *service.SpaceMember) (*service.SpaceInvitation, error) {
+	uid, err := toUUID(member.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var inv *service.SpaceInvitation
+	err = inTx(ctx, r.pool, r.queries, func(q *db.Queries) error {
+		row, err := q.UseSpaceInvitation(ctx, db.UseSpaceInvitationParams{
+			Code: code,
+			Now:  toTimestamp(member.CreatedAt),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = q.AddSpaceMember(ctx, db.AddSpaceMemberParams{
+			SpaceID:   row.SpaceID,
+			UserID:    uid,
+			Role:      member.Role,
+			CreatedAt: toTimestamp(member.CreatedAt),
+		})
+		if err != nil {
+			return err
+		}
+
+		inv = toSpaceInvitation(row)
+		member.SpaceID = inv.SpaceID
+		return nil
+	})
+
+	return inv, err
+}
+
+func toSpaceMember(row db.SpaceMember) *service.SpaceMember {
+	return &service.SpaceMember{
+		SpaceID:   uuidString(row.SpaceID),
+		UserID:    uuidString(row.UserID),
+		Role:      row.Role,
+		CreatedAt: row.CreatedAt.Time,
+	}
+}
+
+func toSpaceInvitation(row db.SpaceInvitation) *service.SpaceInvitation {
+	return &service.SpaceInvitation{
+		ID:        uuidString(row.ID),
+		SpaceID:   uuidString(row.SpaceID),
+		Code:      row.Code,
+		CreatedBy: uuidString(row.CreatedBy),
+		ExpiresAt: row.ExpiresAt.Time,
+		MaxUses:   int(row.MaxUses.Int32),
+		Uses:      int(row.Uses),
+		CreatedAt: row.CreatedAt.Time,
+	}
+}
+
+func (r *psqlSpaceRepository) UpsertSanction(ctx context.Context, sanction *service.SpaceSanction) error {
+	spaceID, err := toUUID(sanction.SpaceID)
+	if err != nil {
+		return err
+	}
+
+	userID, err := toUUID(sanction.UserID)
+	if err != nil {
+		return err
+	}
+
+	moderatorID, err := toUUID(sanction.ModeratorID)
+	if err != nil {
+		return err
+	}
+
+	var expiresAt pgtype.Timestamp
+	if !sanction.ExpiresAt.IsZero() {
+		expiresAt = toTimestamp(sanction.ExpiresAt)
+	}
+
+	_, err = r.queries.UpsertSpaceSanction(ctx, db.UpsertSpaceSanctionParams{
+		SpaceID:     spaceID,
+		UserID:      userID,
+		Kind:        sanction.Kind,
+		Reason:      sanction.Reason,
+		ModeratorID: moderatorID,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   toTimestamp(sanction.CreatedAt),
+	})
+	if isPgError(err, pgForeignKeyViolation) {
+		return service.ErrUserNotFound
+	}
+	return err
+}
+
+func (r *psqlSpaceRepository) DeleteSanction(ctx context.Context, spaceID, userID, kind string) (bool, error) {
+	sid, err := toUUID(spaceID)
+	if err != nil {
+		return false, err
+	}
+
+	uid, err := toUUID(userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.DeleteSpaceSanction(ctx, db.DeleteSpaceSanctionParams{
+		SpaceID: sid,
+		UserID:  uid,
+		Kind:    kind,
+	})
+	return n > 0, err
+}
+
+func (r *psqlSpaceRepository) ListActiveSanctions(ctx context.Context, spaceID string, at time.Time) ([]service.SpaceSanction, error) {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListActiveSpaceSanctions(ctx, db.ListActiveSpaceSanctionsParams{
+		SpaceID: id,
+		Now:     toTimestamp(at),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toSpaceSanctions(rows), nil
+}
+
+func (r *psqlSpaceRepository) ListActiveUserSanctions(
+	ctx context.Context,
+	spaceID string,
+	userID string,
+	at time.Time,
+) ([]service.SpaceSanction, error) {
+
+	sid, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	uid, err := toUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListActiveUserSpaceSanctions(ctx, db.ListActiveUserSpaceSanctionsParams{
+		SpaceID: sid,
+		UserID:  uid,
+		Now:     toTimestamp(at),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toSpaceSanctions(rows), nil
+}
+
+func toSpaceSanctions(rows []db.SpaceSanction) []service.SpaceSanction {
+	sanctions := make([]service.SpaceSanction, 0, len(rows))
+	for _, row := range rows {
+		sanctions = append(sanctions, service.SpaceSanction{
+			SpaceID:     uuidString(row.SpaceID),
+			UserID:      uuidString(row.UserID),
+			Kind:        row.Kind,
+			Reason:      row.Reason,
+			ModeratorID: uuidString(row.ModeratorID),
+			ExpiresAt:   row.ExpiresAt.Time,
+			CreatedAt:   row.CreatedAt.Time,
+		})
+	}
+	return sanctions
+}
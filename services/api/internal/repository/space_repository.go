@@ -0,0 +1,367 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type psqlSpaceRepository struct {
+	pool    Beginner
+	queries *db.Queries
+}
+
+func NewSpaceRepository(pool Beginner, queries *db.Queries) *psqlSpaceRepository {
+	return &psqlSpaceRepository{
+		pool:    pool,
+		queries: queries,
+	}
+}
+
+func (r *psqlSpaceRepository) GetByID(ctx context.Context, id string) (*service.Space, error) {
+	spaceID, err := toUUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.GetSpace(ctx, spaceID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toSpace(row), nil
+}
+
+// Create inserts a space. The owner's membership is added separately.
+func (r *psqlSpaceRepository) Create(ctx context.Context, space *service.Space) error {
+	id, err := toUUID(space.ID)
+	if err != nil {
+		return err
+	}
+
+	creatorID, err := toUUID(space.CreatorID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.CreateSpace(ctx, db.CreateSpaceParams{
+		ID:         id,
+		Name:       space.Name,
+		Width:      int32(space.Width),
+		Height:     int32(space.Height),
+		Thumbnail:  pgtype.Text{String: space.Thumbnail, Valid: space.Thumbnail != ""},
+		CreatorID:  creatorID,
+		Visibility: space.Visibility,
+		Capacity:   pgtype.Int4{Int32: int32(space.Capacity), Valid: space.Capacity > 0},
+		CreatedAt:  toTimestamp(space.CreatedAt),
+		UpdatedAt:  toTimestamp(space.UpdatedAt),
+	})
+}
+
+func (r *psqlSpaceRepository) GetDeleted(ctx context.Context, id string) (*service.Space, error) {
+	spaceID, err := toUUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.GetDeletedSpace(ctx, spaceID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toSpace(row), nil
+}
+
+func (r *psqlSpaceRepository) SoftDelete(ctx context.Context, id string, at time.Time) (bool, error) {
+	spaceID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.SoftDeleteSpace(ctx, db.SoftDeleteSpaceParams{
+		ID:        spaceID,
+		DeletedAt: toTimestamp(at),
+	})
+	return n > 0, err
+}
+
+func (r *psqlSpaceRepository) Restore(ctx context.Context, id string) (bool, error) {
+	spaceID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.RestoreSpace(ctx, db.RestoreSpaceParams{
+		ID:        spaceID,
+		UpdatedAt: toTimestamp(time.Now().UTC()),
+	})
+	return n > 0, err
+}
+
+func (r *psqlSpaceRepository) ListDeleted(ctx context.Context, creatorID string) ([]service.Space, error) {
+	id, err := toUUID(creatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListDeletedSpaces(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	spaces := make([]service.Space, 0, len(rows))
+	for _, row := range rows {
+		spaces = append(spaces, *toSpace(row))
+	}
+
+	return spaces, nil
+}
+
+func (r *psqlSpaceRepository) GetElement(ctx context.Context, id string) (*service.SpaceElement, error) {
+	elementID, err := toUUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.GetSpaceElement(ctx, elementID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toSpaceElement(row), nil
+}
+
+func (r *psqlSpaceRepository) GetDeletedElement(ctx context.Context, id string) (*service.SpaceElement, error) {
+	elementID, err := toUUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.GetDeletedSpaceElement(ctx, elementID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toSpaceElement(row), nil
+}
+
+func (r *psqlSpaceRepository) SoftDeleteElement(ctx context.Context, id string, at time.Time) (bool, error) {
+	elementID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.SoftDeleteSpaceElement(ctx, db.SoftDeleteSpaceElementParams{
+		ID:        elementID,
+		DeletedAt: toTimestamp(at),
+	})
+	return n > 0, err
+}
+
+func (r *psqlSpaceRepository) RestoreElement(ctx context.Context, id string) (bool, error) {
+	elementID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.RestoreSpaceElement(ctx, elementID)
+	return n > 0, err
+}
+
+func (r *psqlSpaceRepository) ListDeletedElements(ctx context.Context, spaceID string) ([]service.SpaceElement, error) {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListDeletedSpaceElements(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]service.SpaceElement, 0, len(rows))
+	for _, row := range rows {
+		elements = append(elements, *toSpaceElement(row))
+	}
+
+	return elements, nil
+}
+
+func (r *psqlSpaceRepository) ListElements(ctx context.Context, spaceID string) ([]service.SpaceElement, error) {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListSpaceElements(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]service.SpaceElement, 0, len(rows))
+	for _, row := range rows {
+		elements = append(elements, *toSpaceElement(row))
+	}
+
+	return elements, nil
+}
+
+// CreateElement places a catalog element in a space. Elements in the trash
+// cannot be placed.
+func (r *psqlSpaceRepository) CreateElement(ctx context.Context, el *service.SpaceElement) error {
+	id, err := toUUID(el.ID)
+	if err != nil {
+		return err
+	}
+
+	spaceID, err := toUUID(el.SpaceID)
+	if err != nil {
+		return err
+	}
+
+	elementID, err := toUUID(el.ElementID)
+	if err != nil {
+		return err
+	}
+
+	n, err := r.queries.CreateSpaceElement(ctx, db.CreateSpaceElementParams{
+		ID:        id,
+		SpaceID:   spaceID,
+		ElementID: elementID,
+		X:         int32(el.X),
+		Y:         int32(el.Y),
+		CreatedAt: toTimestamp(el.CreatedAt),
+	})
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return service.ErrElementNotFound
+	}
+
+	return nil
+}
+
+// GetObstacle returns the footprint of a placed element, in the trash or
+// not, or nil if it is not static or its catalog element is in the trash.
+func (r *psqlSpaceRepository) GetObstacle(ctx context.Context, id string) (*service.Obstacle, error) {
+	elementID, err := toUUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.GetSpaceObstacle(ctx, elementID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.Obstacle{
+		ID:      uuidString(row.ID),
+		SpaceID: uuidString(row.SpaceID),
+		X:       int(row.X),
+		Y:       int(row.Y),
+		Width:   int(row.Width),
+		Height:  int(row.Height),
+	}, nil
+}
+
+func (r *psqlSpaceRepository) ListObstacles(ctx context.Context, spaceID string) ([]service.Obstacle, error) {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListSpaceObstacles(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	obstacles := make([]service.Obstacle, 0, len(rows))
+	for _, row := range rows {
+		obstacles = append(obstacles, service.Obstacle{
+			ID:      uuidString(row.ID),
+			SpaceID: uuidString(row.SpaceID),
+			X:       int(row.X),
+			Y:       int(row.Y),
+			Width:   int(row.Width),
+			Height:  int(row.Height),
+		})
+	}
+
+	return obstacles, nil
+}
+
+// PurgeDeleted hard-deletes placed elements and then spaces that have been
+// in the trash since before the cutoff.
+func (r *psqlSpaceRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	cutoff := toTimestamp(before)
+
+	elements, err := r.queries.PurgeDeletedSpaceElements(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	spaces, err := r.queries.PurgeDeletedSpaces(ctx, cutoff)
+	if err != nil {
+		return elements, err
+	}
+
+	return elements + spaces, nil
+}
+
+func toSpace(row db.Space) *service.Space {
+	space := &service.Space{
+		ID:        uuidString(row.ID),
+		Name:      row.Name,
+		Width:     int(row.Width),
+		Height:    int(row.Height),
+		Thumbnail: row.Thumbnail.String,
+		CreatorID: uuidString(row.CreatorID),
+		CreatedAt: row.CreatedAt.Time,
+		UpdatedAt: row.UpdatedAt.Time,
+		DeletedAt: row.DeletedAt.Time,
+
+		Visibility:   row.Visibility,
+		PasswordHash: row.PasswordHash,
+		Capacity:     int(row.Capacity.Int32),
+	}
+
+	// Only SetRateLimits writes the column, so it always decodes.
+	json.Unmarshal(row.RateLimits, &space.RateLimits)
+
+	return space
+}
+
+func toSpaceElement(row db.SpaceElement) *service.SpaceElement {
+	return &service.SpaceElement{
+		ID:        uuidString(row.ID),
+		SpaceID:   uuidString(row.SpaceID),
+		ElementID: uuidString(row.ElementID),
+		X:         int(row.X),
+		Y:         int(row.Y),
+		CreatedAt: row.CreatedAt.Time,
+		DeletedAt: row.DeletedAt.Time,
+	}
+}
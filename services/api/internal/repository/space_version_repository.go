@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+// Space versions live on psqlSpaceRepository alongside the elements they
+// snapshot.
+
+// EditLayout runs fn with a repository bound to a transaction that holds a
+// lock on the space, so that the edits of its layout, and the versions
+// recording them, happen one at a time. The repository fn gets must not be
+// used to start another edit. It returns service.ErrSpaceNotFound for a space
+// that does not exist or is in the trash.
+func (r *psqlSpaceRepository) EditLayout(
+	ctx context.Context,
+	spaceID string,
+	fn func(repo service.SpaceRepository) error,
+) error {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return err
+	}
+
+	return inTx(ctx, r.pool, r.queries, func(q *db.Queries) error {
+		_, err := q.LockSpace(ctx, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return service.ErrSpaceNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		return fn(&psqlSpaceRepository{queries: q})
+	})
+}
+
+func (r *psqlSpaceRepository) CreateVersion(ctx context.Context, v *service.SpaceVersion) error {
+	spaceID, err := toUUID(v.SpaceID)
+	if err != nil {
+		return err
+	}
+
+	var authorID pgtype.UUID
+	if v.AuthorID != "" {
+		if authorID, err = toUUID(v.AuthorID); err != nil {
+			return err
+		}
+	}
+
+	elements, err := json.Marshal(v.Elements)
+	if err != nil {
+		return err
+	}
+
+	err = r.queries.CreateSpaceVersion(ctx, db.CreateSpaceVersionParams{
+		SpaceID:         spaceID,
+		Version:         int32(v.Version),
+		AuthorID:        authorID,
+		Action:          v.Action,
+		RestoredVersion: pgtype.Int4{Int32: int32(v.RestoredVersion), Valid: v.RestoredVersion > 0},
+		Elements:        elements,
+		CreatedAt:       toTimestamp(v.CreatedAt),
+	})
+	if isPgError(err, pgUniqueViolation) {
+		return service.ErrSpaceVersionConflict
+	}
+
+	return err
+}
+
+func (r *psqlSpaceRepository) GetVersion(ctx context.Context, spaceID string, version int) (*service.SpaceVersion, error) {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.GetSpaceVersion(ctx, db.GetSpaceVersionParams{
+		SpaceID: id,
+		Version: int32(version),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toSpaceVersion(row)
+}
+
+func (r *psqlSpaceRepository) GetLatestVersion(ctx context.Context, spaceID string) (*service.SpaceVersion, error) {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.GetLatestSpaceVersion(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toSpaceVersion(row)
+}
+
+// ListVersions returns up to limit versions, newest first.
+func (r *psqlSpaceRepository) ListVersions(ctx context.Context, spaceID string, limit int) ([]service.SpaceVersion, error) {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListSpaceVersions(ctx, db.ListSpaceVersionsParams{
+		SpaceID: id,
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]service.SpaceVersion, 0, len(rows))
+	for _, row := range rows {
+		v, err := toSpaceVersion(row)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *v)
+	}
+
+	return versions, nil
+}
+
+// UpsertElement places an element with a known id, bringing it back from
+// the trash or moving it if it already exists. It reports false, placing
+// nothing, when the catalog element has been purged.
+func (r *psqlSpaceRepository) UpsertElement(ctx context.Context, el *service.SpaceElement) (bool, error) {
+	id, err := toUUID(el.ID)
+	if err != nil {
+		return false, err
+	}
+
+	spaceID, err := toUUID(el.SpaceID)
+	if err != nil {
+		return false, err
+	}
+
+	elementID, err := toUUID(el.ElementID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.UpsertSpaceElement(ctx, db.UpsertSpaceElementParams{
+		ID:        id,
+		SpaceID:   spaceID,
+		ElementID: elementID,
+		X:         int32(el.X),
+		Y:         int32(el.Y),
+		CreatedAt: toTimestamp(el.CreatedAt),
+	})
+	if isPgError(err, pgForeignKeyViolation) {
+		return false, service.ErrElementNotFound
+	}
+
+	return n > 0, err
+}
+
+func toSpaceVersion(row db.SpaceVersion) (*service.SpaceVersion, error) {
+	v := &service.SpaceVersion{
+		SpaceID:         uuidString(row.SpaceID),
+		Version:         int(row.Version),
+		AuthorID:        uuidString(row.AuthorID),
+		Action:          row.Action,
+		RestoredVersion: int(row.RestoredVersion.Int32),
+		CreatedAt:       row.CreatedAt.Time,
+	}
+
+	if err := json.Unmarshal(row.Elements, &v.Elements); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
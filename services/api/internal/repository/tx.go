@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+)
+
+// Beginner starts the transactions a repository runs several statements
+// in; *pgxpool.Pool is one.
+type Beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// inTx runs fn with queries bound to a transaction started on pool,
+// committing it if fn succeeds and rolling it back otherwise.
+func inTx(ctx context.Context, pool Beginner, queries *db.Queries, fn func(q *db.Queries) error) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(queries.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
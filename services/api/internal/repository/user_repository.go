@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5"
 	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
 )
 
 type psqlUserRepository struct {
@@ -18,23 +20,114 @@ func NewUserRepository(queries *db.Queries) *psqlUserRepository {
 	}
 }
 
-func (r *psqlUserRepository) Create(
-	ctx context.Context,
-	id pgtype.UUID,
-	name, email, password string,
-) (db.User, error) {
-
-	now := pgtype.Timestamp{
-		Time:  time.Now(),
-		Valid: true,
+func (r *psqlUserRepository) Create(ctx context.Context, user *service.User) error {
+	id, err := toUUID(user.ID)
+	if err != nil {
+		return err
 	}
 
-	return r.queries.CreateUser(ctx, db.CreateUserParams{
+	now := toTimestamp(user.CreatedAt)
+	_, err = r.queries.CreateUser(ctx, db.CreateUserParams{
 		ID:        id,
-		Name:      name,
-		Email:     email,
-		Password:  password,
+		Name:      user.Name,
+		Email:     user.Email,
 		CreatedAt: now,
 		UpdatedAt: now,
 	})
+	return err
+}
+
+// Active reports whether the user exists and is not soft-deleted.
+func (r *psqlUserRepository) Active(ctx context.Context, id string) (bool, error) {
+	userID, err := toUUID(id)
+	if err != nil {
+		return false, nil
+	}
+
+	return r.queries.UserActive(ctx, userID)
+}
+
+// GetByID returns nil for users that do not exist or are soft-deleted.
+func (r *psqlUserRepository) GetByID(ctx context.Context, id string) (*service.User, error) {
+	userID, err := toUUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.GetUser(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toUser(row), nil
+}
+
+func (r *psqlUserRepository) GetByEmail(ctx context.Context, email string) (*service.User, error) {
+	row, err := r.queries.GetUserByEmail(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toUser(row), nil
+}
+
+func (r *psqlUserRepository) SoftDelete(ctx context.Context, id string, at time.Time) (bool, error) {
+	userID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.SoftDeleteUser(ctx, db.SoftDeleteUserParams{
+		ID:        userID,
+		DeletedAt: toTimestamp(at),
+	})
+	return n > 0, err
+}
+
+func (r *psqlUserRepository) Restore(ctx context.Context, id string) (bool, error) {
+	userID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.RestoreUser(ctx, db.RestoreUserParams{
+		ID:        userID,
+		UpdatedAt: toTimestamp(time.Now().UTC()),
+	})
+	return n > 0, err
+}
+
+func (r *psqlUserRepository) ListDeleted(ctx context.Context) ([]service.User, error) {
+	rows, err := r.queries.ListDeletedUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]service.User, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, *toUser(row))
+	}
+
+	return users, nil
+}
+
+func (r *psqlUserRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.PurgeDeletedUsers(ctx, toTimestamp(before))
+}
+
+func toUser(row db.User) *service.User {
+	return &service.User{
+		ID:        uuidString(row.ID),
+		Email:     row.Email,
+		Name:      row.Name,
+		CreatedAt: row.CreatedAt.Time,
+		UpdatedAt: row.UpdatedAt.Time,
+		DeletedAt: row.DeletedAt.Time,
+	}
 }
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vaxxnsh/metaverse/api/internal/db"
+	"github.com/vaxxnsh/metaverse/api/internal/service"
+)
+
+type psqlZoneRepository struct {
+	queries *db.Queries
+}
+
+func NewZoneRepository(queries *db.Queries) *psqlZoneRepository {
+	return &psqlZoneRepository{
+		queries: queries,
+	}
+}
+
+func (r *psqlZoneRepository) Create(ctx context.Context, zone *service.Zone) error {
+	id, err := toUUID(zone.ID)
+	if err != nil {
+		return err
+	}
+
+	spaceID, err := toUUID(zone.SpaceID)
+	if err != nil {
+		return err
+	}
+
+	points, err := json.Marshal(zone.Points)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.queries.CreateSpaceZone(ctx, db.CreateSpaceZoneParams{
+		ID:         id,
+		SpaceID:    spaceID,
+		Name:       zone.Name,
+		Shape:      zone.Shape,
+		Points:     points,
+		Properties: zone.Properties,
+		ChatScope:  zone.ChatScope,
+		Audio:      zone.Audio,
+		MinRole:    zone.MinRole,
+		CreatedAt:  toTimestamp(zone.CreatedAt),
+		UpdatedAt:  toTimestamp(zone.UpdatedAt),
+	})
+	return err
+}
+
+// Update returns nil when the zone does not exist in the space.
+func (r *psqlZoneRepository) Update(ctx context.Context, zone *service.Zone) (*service.Zone, error) {
+	id, err := toUUID(zone.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	spaceID, err := toUUID(zone.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := json.Marshal(zone.Points)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.queries.UpdateSpaceZone(ctx, db.UpdateSpaceZoneParams{
+		ID:         id,
+		SpaceID:    spaceID,
+		Name:       zone.Name,
+		Shape:      zone.Shape,
+		Points:     points,
+		Properties: zone.Properties,
+		ChatScope:  zone.ChatScope,
+		Audio:      zone.Audio,
+		MinRole:    zone.MinRole,
+		UpdatedAt:  toTimestamp(zone.UpdatedAt),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toZone(row)
+}
+
+func (r *psqlZoneRepository) Delete(ctx context.Context, spaceID, id string) (bool, error) {
+	sid, err := toUUID(spaceID)
+	if err != nil {
+		return false, err
+	}
+
+	zoneID, err := toUUID(id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries.DeleteSpaceZone(ctx, db.DeleteSpaceZoneParams{
+		ID:      zoneID,
+		SpaceID: sid,
+	})
+	return n > 0, err
+}
+
+func (r *psqlZoneRepository) ListBySpace(ctx context.Context, spaceID string) ([]service.Zone, error) {
+	id, err := toUUID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListSpaceZones(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	zones := make([]service.Zone, 0, len(rows))
+	for _, row := range rows {
+		zone, err := toZone(row)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, *zone)
+	}
+
+	return zones, nil
+}
+
+func toZone(row db.SpaceZone) (*service.Zone, error) {
+	zone := &service.Zone{
+		ID:         uuidString(row.ID),
+		SpaceID:    uuidString(row.SpaceID),
+		Name:       row.Name,
+		Shape:      row.Shape,
+		Properties: row.Properties,
+		ChatScope:  row.ChatScope,
+		Audio:      row.Audio,
+		MinRole:    row.MinRole,
+		CreatedAt:  row.CreatedAt.Time,
+		UpdatedAt:  row.UpdatedAt.Time,
+	}
+
+	if err := json.Unmarshal(row.Points, &zone.Points); err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
@@ -1,52 +1,117 @@
-package user
+package router
 
 import (
 	"net/http"
 
-	"github.com/gin-gonic/gin"
-	"github.com/vaxxnsh/metaverse/api/internal/service"
+	"github.com/vaxxnsh/metaverse/api/internal/handlers"
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
 )
 
-type Handler struct {
-	service service.Service
-}
+// SetupRouter registers the REST endpoints. All of them but accepting an
+// invitation, whose token stands in for a password the user has yet to set,
+// need a bearer token signed with secret for a user that is still in users,
+// and those under /api/v1/admin the admin role.
+func SetupRouter(
+	secret string,
+	users middleware.UserStore,
+	userHandler *handlers.UserHandler,
+	friendHandler *handlers.FriendHandler,
+	blockHandler *handlers.BlockHandler,
+	preferencesHandler *handlers.PreferencesHandler,
+	spaceHandler *handlers.SpaceHandler,
+	elementHandler *handlers.ElementHandler,
+	importHandler *handlers.ImportHandler,
+	invitationHandler *handlers.InvitationHandler,
+	moderationHandler *handlers.ModerationHandler,
+	zoneHandler *handlers.ZoneHandler,
+	portalHandler *handlers.PortalHandler,
+	pathHandler *handlers.PathHandler,
+	spaceBundleHandler *handlers.SpaceBundleHandler,
+) http.Handler {
+	mux := http.NewServeMux()
 
-func NewHandler(s service.Service) *Handler {
-	return &Handler{service: s}
-}
+	auth := middleware.Auth(secret, users)
+	user := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, auth(h))
+	}
+	admin := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, auth(middleware.RequireAdmin(h)))
+	}
 
-type createUserRequest struct {
-	Email string `json:"email"`
-	Name  string `json:"name"`
-}
+	user("POST /users", userHandler.CreateUser)
+	user("GET /users", userHandler.GetUserByID)
+	admin("DELETE /api/v1/admin/user/{id}", userHandler.DeleteUser)
+	admin("GET /api/v1/admin/user/trash", userHandler.ListDeletedUsers)
+	admin("POST /api/v1/admin/user/{id}/restore", userHandler.RestoreUser)
 
-func (h *Handler) CreateUser(c *gin.Context) {
-	var req createUserRequest
+	user("POST /api/v1/friends/requests", friendHandler.SendRequest)
+	user("GET /api/v1/friends/requests", friendHandler.ListRequests)
+	user("POST /api/v1/friends/requests/{id}/accept", friendHandler.AcceptRequest)
+	user("POST /api/v1/friends/requests/{id}/decline", friendHandler.DeclineRequest)
+	user("DELETE /api/v1/friends/requests/{id}", friendHandler.CancelRequest)
+	user("GET /api/v1/friends", friendHandler.ListFriends)
+	user("POST /api/v1/friends/{id}/join", friendHandler.JoinFriend)
+	user("PUT /api/v1/user/presence", friendHandler.SetPresenceVisibility)
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid request body",
-		})
-		return
-	}
+	user("POST /api/v1/user/blocks", blockHandler.Block)
+	user("POST /api/v1/user/mutes", blockHandler.Mute)
+	user("DELETE /api/v1/user/blocks/{id}", blockHandler.Remove)
+	user("GET /api/v1/user/blocks", blockHandler.List)
 
-	user, err := h.service.CreateUser(c.Request.Context(), req.Email, req.Name)
-	if err != nil {
-		switch err {
-		case service.ErrInvalidEmail, service.ErrInvalidName:
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		case service.ErrUserExists:
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
-		return
-	}
+	user("GET /api/v1/user/preferences", preferencesHandler.Get)
+	user("PATCH /api/v1/user/preferences", preferencesHandler.Patch)
+
+	user("GET /api/v1/space/{id}", spaceHandler.GetSpace)
+	user("DELETE /api/v1/space/{id}", spaceHandler.DeleteSpace)
+	user("GET /api/v1/space/trash", spaceHandler.ListDeletedSpaces)
+	user("POST /api/v1/space/{id}/restore", spaceHandler.RestoreSpace)
+	user("POST /api/v1/space/element", spaceHandler.AddSpaceElement)
+	user("DELETE /api/v1/space/element", spaceHandler.DeleteSpaceElement)
+	user("GET /api/v1/space/{id}/trash", spaceHandler.ListDeletedSpaceElements)
+	user("POST /api/v1/space/element/{id}/restore", spaceHandler.RestoreSpaceElement)
+	user("PUT /api/v1/space/{id}/visibility", spaceHandler.SetSpaceVisibility)
+	user("PUT /api/v1/space/{id}/capacity", spaceHandler.SetSpaceCapacity)
+	user("PUT /api/v1/space/{id}/rate-limits", spaceHandler.SetSpaceRateLimits)
+	user("GET /api/v1/space/{id}/members", spaceHandler.ListSpaceMembers)
+	user("PUT /api/v1/space/{id}/members/{userId}", spaceHandler.SetSpaceMemberRole)
+	user("DELETE /api/v1/space/{id}/members/{userId}", spaceHandler.RemoveSpaceMember)
+	user("POST /api/v1/space/{id}/invitations", spaceHandler.CreateSpaceInvitation)
+	user("GET /api/v1/space/{id}/invitations", spaceHandler.ListSpaceInvitations)
+	user("DELETE /api/v1/space/{id}/invitations/{invitationId}", spaceHandler.RevokeSpaceInvitation)
+	user("POST /api/v1/space/invitations/{code}/accept", spaceHandler.AcceptSpaceInvitation)
+	user("GET /api/v1/space/{id}/versions", spaceHandler.ListSpaceVersions)
+	user("GET /api/v1/space/{id}/versions/diff", spaceHandler.DiffSpaceVersions)
+	user("POST /api/v1/space/{id}/versions/{version}/rollback", spaceHandler.RollbackSpace)
+
+	admin("DELETE /api/v1/admin/element/{id}", elementHandler.DeleteElement)
+	admin("GET /api/v1/admin/element/trash", elementHandler.ListDeletedElements)
+	admin("POST /api/v1/admin/element/{id}/restore", elementHandler.RestoreElement)
+
+	admin("POST /api/v1/admin/users/import", importHandler.StartUserImport)
+	admin("GET /api/v1/admin/users/import/{id}", importHandler.GetUserImport)
+	mux.HandleFunc("POST /api/v1/invitations/accept", invitationHandler.AcceptInvitation)
+
+	user("POST /api/v1/space/{id}/kick", moderationHandler.Kick)
+	user("POST /api/v1/space/{id}/mutes", moderationHandler.Mute)
+	user("DELETE /api/v1/space/{id}/mutes/{userId}", moderationHandler.Unmute)
+	user("POST /api/v1/space/{id}/bans", moderationHandler.Ban)
+	user("DELETE /api/v1/space/{id}/bans/{userId}", moderationHandler.Unban)
+	user("GET /api/v1/space/{id}/sanctions", moderationHandler.ListSanctions)
+
+	user("GET /api/v1/space/{id}/zones", zoneHandler.ListZones)
+	user("POST /api/v1/space/{id}/zones", zoneHandler.CreateZone)
+	user("PUT /api/v1/space/{id}/zones/{zoneId}", zoneHandler.UpdateZone)
+	user("DELETE /api/v1/space/{id}/zones/{zoneId}", zoneHandler.DeleteZone)
+
+	user("GET /api/v1/space/{id}/portals", portalHandler.ListPortals)
+	user("PUT /api/v1/space/{id}/portals/{elementId}", portalHandler.SetPortal)
+	user("DELETE /api/v1/space/{id}/portals/{elementId}", portalHandler.DeletePortal)
+
+	user("GET /api/v1/space/{id}/path", pathHandler.FindPath)
+
+	user("GET /api/v1/space/{id}/export", spaceBundleHandler.ExportSpace)
+	user("POST /api/v1/space/import", spaceBundleHandler.ImportSpace)
+	user("POST /api/v1/space/import/tiled", spaceBundleHandler.ImportTiledMap)
 
-	c.JSON(http.StatusCreated, gin.H{
-		"id":         user.ID,
-		"email":      user.Email,
-		"name":       user.Name,
-		"created_at": user.CreatedAt,
-	})
+	return mux
 }
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BlockService interface {
+	Block(ctx context.Context, userID, targetID string) (*Block, error)
+	Mute(ctx context.Context, userID, targetID string) (*Block, error)
+	Remove(ctx context.Context, userID, targetID string) error
+	List(ctx context.Context, userID string) ([]Block, error)
+	IsBlocked(ctx context.Context, userID, otherID string) (bool, error)
+}
+
+type BlockRepository interface {
+	Upsert(ctx context.Context, block *Block) error
+	Delete(ctx context.Context, userID, targetID string) (bool, error)
+	ListByUser(ctx context.Context, userID string) ([]Block, error)
+	ListInvolving(ctx context.Context, userID string) ([]Block, error)
+	IsBlocked(ctx context.Context, userID, otherID string) (bool, error)
+}
+
+// BlockListener is told when the block list between two users changes so
+// that live connections can pick up the new rules without rejoining.
+type BlockListener interface {
+	BlocksChanged(userID, targetID string)
+}
+
+const (
+	// BlockKindBlock hides the target's chat and emotes and stops all direct
+	// contact in both directions.
+	BlockKindBlock = "block"
+
+	// BlockKindMute only hides the target's chat and emotes.
+	BlockKindMute = "mute"
+)
+
+type Block struct {
+	UserID    string
+	TargetID  string
+	Kind      string
+	CreatedAt time.Time
+}
+
+type blockService struct {
+	repository BlockRepository
+	listener   BlockListener
+}
+
+func NewBlockService(r BlockRepository, l BlockListener) BlockService {
+	return &blockService{
+		repository: r,
+		listener:   l,
+	}
+}
+
+var (
+	ErrInvalidBlockTarget = errors.New("cannot block yourself")
+	ErrBlockNotFound      = errors.New("block not found")
+	ErrUserBlocked        = errors.New("user is blocked")
+)
+
+func (s *blockService) Block(ctx context.Context, userID, targetID string) (*Block, error) {
+	return s.put(ctx, userID, targetID, BlockKindBlock)
+}
+
+func (s *blockService) Mute(ctx context.Context, userID, targetID string) (*Block, error) {
+	return s.put(ctx, userID, targetID, BlockKindMute)
+}
+
+func (s *blockService) put(ctx context.Context, userID, targetID, kind string) (*Block, error) {
+	if uuid.Validate(targetID) != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	if userID == targetID {
+		return nil, ErrInvalidBlockTarget
+	}
+
+	b := &Block{
+		UserID:    userID,
+		TargetID:  targetID,
+		Kind:      kind,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.repository.Upsert(ctx, b); err != nil {
+		return nil, err
+	}
+
+	s.listener.BlocksChanged(userID, targetID)
+
+	return b, nil
+}
+
+func (s *blockService) Remove(ctx context.Context, userID, targetID string) error {
+	if uuid.Validate(targetID) != nil {
+		return ErrInvalidUserID
+	}
+
+	removed, err := s.repository.Delete(ctx, userID, targetID)
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		return ErrBlockNotFound
+	}
+
+	s.listener.BlocksChanged(userID, targetID)
+
+	return nil
+}
+
+func (s *blockService) List(ctx context.Context, userID string) ([]Block, error) {
+	return s.repository.ListByUser(ctx, userID)
+}
+
+func (s *blockService) IsBlocked(ctx context.Context, userID, otherID string) (bool, error) {
+	return s.repository.IsBlocked(ctx, userID, otherID)
+}
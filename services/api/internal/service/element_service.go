@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ElementService manages the admin catalog of elements that can be placed
+// in spaces.
+type ElementService interface {
+	DeleteElement(ctx context.Context, id string) error
+	ListDeletedElements(ctx context.Context) ([]Element, error)
+	RestoreElement(ctx context.Context, id string) error
+}
+
+type ElementRepository interface {
+	SoftDelete(ctx context.Context, id string, at time.Time) (bool, error)
+	Restore(ctx context.Context, id string) (bool, error)
+	ListDeleted(ctx context.Context) ([]Element, error)
+	Create(ctx context.Context, e *Element) error
+	GetByHash(ctx context.Context, hash string) (*Element, error)
+	ListBySpace(ctx context.Context, spaceID string) ([]Element, error)
+}
+
+type Element struct {
+	ID       string
+	ImageURL string
+	Width    int
+	Height   int
+	Static   bool
+
+	// ContentHash is ElementHash of the element, kept up to date by the
+	// database and used to find identical elements when importing spaces.
+	ContentHash string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt time.Time
+}
+
+type elementService struct {
+	repository ElementRepository
+}
+
+func NewElementService(r ElementRepository) ElementService {
+	return &elementService{
+		repository: r,
+	}
+}
+
+var (
+	ErrElementNotFound = errors.New("element not found")
+)
+
+func (s *elementService) DeleteElement(ctx context.Context, id string) error {
+	if uuid.Validate(id) != nil {
+		return ErrElementNotFound
+	}
+
+	deleted, err := s.repository.SoftDelete(ctx, id, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrElementNotFound
+	}
+
+	return nil
+}
+
+func (s *elementService) ListDeletedElements(ctx context.Context) ([]Element, error) {
+	return s.repository.ListDeleted(ctx)
+}
+
+func (s *elementService) RestoreElement(ctx context.Context, id string) error {
+	if uuid.Validate(id) != nil {
+		return ErrElementNotFound
+	}
+
+	restored, err := s.repository.Restore(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !restored {
+		return ErrElementNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FriendService interface {
+	SendRequest(ctx context.Context, senderID, receiverID string) (*FriendRequest, error)
+	AcceptRequest(ctx context.Context, userID, requestID string) (*FriendRequest, error)
+	DeclineRequest(ctx context.Context, userID, requestID string) (*FriendRequest, error)
+	CancelRequest(ctx context.Context, userID, requestID string) (*FriendRequest, error)
+	ListRequests(ctx context.Context, userID string) ([]FriendRequest, error)
+	ListFriends(ctx context.Context, userID string) ([]Friend, error)
+	JoinFriend(ctx context.Context, userID, friendID string) (*JoinTarget, error)
+	SetPresenceVisibility(ctx context.Context, userID, visibility string) error
+}
+
+type FriendRepository interface {
+	CreateRequest(ctx context.Context, req *FriendRequest) error
+	GetRequest(ctx context.Context, id string) (*FriendRequest, error)
+	UpdateRequestStatus(ctx context.Context, id, status string) (*FriendRequest, error)
+	ListPendingRequests(ctx context.Context, userID string) ([]FriendRequest, error)
+	ListFriends(ctx context.Context, userID string, presentSince time.Time) ([]Friend, error)
+	SetPresenceVisibility(ctx context.Context, userID, visibility string) error
+}
+
+// PresenceTTL is how long a user counts as being in a space after the
+// real-time node they are connected to last refreshed their presence.
+const PresenceTTL = 30 * time.Second
+
+// BlockChecker reports whether either user has blocked the other.
+type BlockChecker interface {
+	IsBlocked(ctx context.Context, userID, otherID string) (bool, error)
+}
+
+// SpawnIssuer issues the spawn tickets with which a user joins a space next
+// to another user there.
+type SpawnIssuer interface {
+	SpawnBeside(userID, spaceID, besideID string) string
+}
+
+const (
+	FriendRequestPending   = "pending"
+	FriendRequestAccepted  = "accepted"
+	FriendRequestDeclined  = "declined"
+	FriendRequestCancelled = "cancelled"
+)
+
+const (
+	PresenceVisibleToFriends = "friends"
+	PresenceHidden           = "hidden"
+)
+
+type FriendRequest struct {
+	ID         string
+	SenderID   string
+	ReceiverID string
+	Status     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type Friend struct {
+	ID                 string
+	Name               string
+	AvatarID           string
+	PresenceVisibility string
+	FriendsSince       time.Time
+
+	// SpaceID is empty when the friend is offline or hides their presence.
+	SpaceID string
+}
+
+// JoinTarget is the space a user should join to be with a friend, and the
+// spawn ticket that puts them next to the friend there.
+type JoinTarget struct {
+	SpaceID     string
+	SpawnTicket string
+}
+
+type friendService struct {
+	repository FriendRepository
+	blocks     BlockChecker
+	spawns     SpawnIssuer
+}
+
+func NewFriendService(r FriendRepository, b BlockChecker, sp SpawnIssuer) FriendService {
+	return &friendService{
+		repository: r,
+		blocks:     b,
+		spawns:     sp,
+	}
+}
+
+var (
+	ErrInvalidUserID          = errors.New("invalid user id")
+	ErrInvalidFriendRequest   = errors.New("cannot send a friend request to yourself")
+	ErrFriendRequestExists    = errors.New("friend request already exists")
+	ErrFriendRequestNotFound  = errors.New("friend request not found")
+	ErrFriendRequestResolved  = errors.New("friend request is no longer pending")
+	ErrNotFriends             = errors.New("users are not friends")
+	ErrFriendOffline          = errors.New("friend is not in a space")
+	ErrInvalidVisibility      = errors.New("invalid presence visibility")
+	ErrFriendRequestForbidden = errors.New("friend request belongs to another user")
+)
+
+func (s *friendService) SendRequest(
+	ctx context.Context,
+	senderID string,
+	receiverID string,
+) (*FriendRequest, error) {
+
+	if uuid.Validate(receiverID) != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	if senderID == receiverID {
+		return nil, ErrInvalidFriendRequest
+	}
+
+	blocked, err := s.blocks.IsBlocked(ctx, senderID, receiverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if blocked {
+		return nil, ErrUserBlocked
+	}
+
+	now := time.Now().UTC()
+	req := &FriendRequest{
+		ID:         uuid.NewString(),
+		SenderID:   senderID,
+		ReceiverID: receiverID,
+		Status:     FriendRequestPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.repository.CreateRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (s *friendService) AcceptRequest(ctx context.Context, userID, requestID string) (*FriendRequest, error) {
+	return s.resolve(ctx, requestID, FriendRequestAccepted, func(req *FriendRequest) bool {
+		return req.ReceiverID == userID
+	})
+}
+
+func (s *friendService) DeclineRequest(ctx context.Context, userID, requestID string) (*FriendRequest, error) {
+	return s.resolve(ctx, requestID, FriendRequestDeclined, func(req *FriendRequest) bool {
+		return req.ReceiverID == userID
+	})
+}
+
+func (s *friendService) CancelRequest(ctx context.Context, userID, requestID string) (*FriendRequest, error) {
+	return s.resolve(ctx, requestID, FriendRequestCancelled, func(req *FriendRequest) bool {
+		return req.SenderID == userID
+	})
+}
+
+// resolve moves a pending request to status if allowed reports that the
+// caller is the party entitled to do so.
+func (s *friendService) resolve(
+	ctx context.Context,
+	requestID string,
+	status string,
+	allowed func(*FriendRequest) bool,
+) (*FriendRequest, error) {
+
+	if uuid.Validate(requestID) != nil {
+		return nil, ErrFriendRequestNotFound
+	}
+
+	req, err := s.repository.GetRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		return nil, ErrFriendRequestNotFound
+	}
+
+	if !allowed(req) {
+		return nil, ErrFriendRequestForbidden
+	}
+
+	if req.Status != FriendRequestPending {
+		return nil, ErrFriendRequestResolved
+	}
+
+	return s.repository.UpdateRequestStatus(ctx, requestID, status)
+}
+
+func (s *friendService) ListRequests(ctx context.Context, userID string) ([]FriendRequest, error) {
+	return s.repository.ListPendingRequests(ctx, userID)
+}
+
+func (s *friendService) ListFriends(ctx context.Context, userID string) ([]Friend, error) {
+	friends, err := s.repository.ListFriends(ctx, userID, time.Now().UTC().Add(-PresenceTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range friends {
+		if friends[i].PresenceVisibility == PresenceHidden {
+			friends[i].SpaceID = ""
+		}
+	}
+
+	return friends, nil
+}
+
+func (s *friendService) JoinFriend(ctx context.Context, userID, friendID string) (*JoinTarget, error) {
+	if uuid.Validate(friendID) != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	friends, err := s.ListFriends(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var friend *Friend
+	for i := range friends {
+		if friends[i].ID == friendID {
+			friend = &friends[i]
+			break
+		}
+	}
+
+	if friend == nil {
+		return nil, ErrNotFriends
+	}
+
+	if friend.SpaceID == "" {
+		return nil, ErrFriendOffline
+	}
+
+	// The node serving the space finds the free tile nearest the friend
+	// when the user joins with the ticket.
+	return &JoinTarget{
+		SpaceID:     friend.SpaceID,
+		SpawnTicket: s.spawns.SpawnBeside(userID, friend.SpaceID, friendID),
+	}, nil
+}
+
+func (s *friendService) SetPresenceVisibility(ctx context.Context, userID, visibility string) error {
+	if visibility != PresenceVisibleToFriends && visibility != PresenceHidden {
+		return ErrInvalidVisibility
+	}
+
+	return s.repository.SetPresenceVisibility(ctx, userID, visibility)
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportService interface {
+	StartUserImport(ctx context.Context, adminID string, file io.Reader, dryRun bool) (*UserImport, error)
+	GetUserImport(ctx context.Context, id string) (*UserImport, error)
+	ResumeUserImports(ctx context.Context) error
+}
+
+type ImportRepository interface {
+	Create(ctx context.Context, imp *UserImport) error
+	Get(ctx context.Context, id string) (*UserImport, error)
+	ListUnfinished(ctx context.Context) ([]UserImport, error)
+	ListPendingRows(ctx context.Context, importID string) ([]UserImportRow, error)
+	Claim(ctx context.Context, id string, staleBefore time.Time) (bool, error)
+	UpdateStatus(ctx context.Context, id, status string, finishedAt time.Time) error
+	UpdateRow(ctx context.Context, importID string, row *UserImportRow) error
+}
+
+const (
+	ImportPending   = "pending"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+)
+
+const (
+	ImportRowPending = "pending"
+	ImportRowCreated = "created"
+	ImportRowSkipped = "skipped"
+	ImportRowInvalid = "invalid"
+
+	// ImportRowValid marks a row that would have been created had the
+	// import not been a dry run.
+	ImportRowValid = "valid"
+)
+
+const maxImportRows = 10000
+
+// An import is run by the node that claimed it, which touches it at least
+// every importClaimRenewal. One left untouched for importClaimTimeout was
+// abandoned and may be taken over.
+const (
+	importClaimRenewal = 30 * time.Second
+	importClaimTimeout = 2 * time.Minute
+)
+
+type UserImport struct {
+	ID         string
+	CreatedBy  string
+	Status     string
+	DryRun     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FinishedAt time.Time
+	Rows       []UserImportRow
+}
+
+// Summary counts rows by status.
+func (imp *UserImport) Summary() map[string]int {
+	summary := map[string]int{}
+	for _, row := range imp.Rows {
+		summary[row.Status]++
+	}
+	return summary
+}
+
+type UserImportRow struct {
+	// Number is the 1-based line in the file, counting the header.
+	Number   int
+	Name     string
+	Email    string
+	Role     string
+	AvatarID string
+	Status   string
+	Message  string
+}
+
+type importService struct {
+	repository  ImportRepository
+	invitations InvitationService
+	users       InvitationRepository
+}
+
+func NewImportService(r ImportRepository, i InvitationService, u InvitationRepository) ImportService {
+	return &importService{
+		repository:  r,
+		invitations: i,
+		users:       u,
+	}
+}
+
+var (
+	ErrInvalidImportFile  = errors.New("invalid csv file")
+	ErrImportTooLarge     = fmt.Errorf("csv file has more than %d rows", maxImportRows)
+	ErrUserImportNotFound = errors.New("user import not found")
+)
+
+// StartUserImport stores every row of file as pending and processes them in
+// the background. Only the header and the CSV syntax are checked up front;
+// per-row problems are reported on the rows themselves.
+func (s *importService) StartUserImport(
+	ctx context.Context,
+	adminID string,
+	file io.Reader,
+	dryRun bool,
+) (*UserImport, error) {
+
+	rows, err := parseUserImport(file)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	imp := &UserImport{
+		ID:        uuid.NewString(),
+		CreatedBy: adminID,
+		Status:    ImportPending,
+		DryRun:    dryRun,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Rows:      rows,
+	}
+
+	if err := s.repository.Create(ctx, imp); err != nil {
+		return nil, err
+	}
+
+	// The request context ends with the response, the import does not.
+	go s.run(context.Background(), imp.ID, dryRun)
+
+	return imp, nil
+}
+
+func (s *importService) GetUserImport(ctx context.Context, id string) (*UserImport, error) {
+	if uuid.Validate(id) != nil {
+		return nil, ErrUserImportNotFound
+	}
+
+	imp, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if imp == nil {
+		return nil, ErrUserImportNotFound
+	}
+
+	return imp, nil
+}
+
+// ResumeUserImports restarts imports interrupted by a shutdown. Rows that
+// were already processed keep their result. An import another node is
+// still running is left to it.
+func (s *importService) ResumeUserImports(ctx context.Context) error {
+	imports, err := s.repository.ListUnfinished(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, imp := range imports {
+		go s.run(context.Background(), imp.ID, imp.DryRun)
+	}
+
+	return nil
+}
+
+// run processes the import if this node can claim it.
+func (s *importService) run(ctx context.Context, importID string, dryRun bool) {
+	claimed, err := s.repository.Claim(ctx, importID, time.Now().UTC().Add(-importClaimTimeout))
+	if err != nil {
+		log.Printf("user import %s: %v", importID, err)
+		return
+	}
+
+	if !claimed {
+		return
+	}
+
+	status := ImportCompleted
+	if err := s.process(ctx, importID, dryRun); err != nil {
+		log.Printf("user import %s failed: %v", importID, err)
+		status = ImportFailed
+	}
+
+	if err := s.repository.UpdateStatus(ctx, importID, status, time.Now().UTC()); err != nil {
+		log.Printf("user import %s: %v", importID, err)
+	}
+}
+
+func (s *importService) process(ctx context.Context, importID string, dryRun bool) error {
+	rows, err := s.repository.ListPendingRows(ctx, importID)
+	if err != nil {
+		return err
+	}
+
+	renewed := time.Now()
+	for i := range rows {
+		if time.Since(renewed) > importClaimRenewal {
+			if err := s.repository.UpdateStatus(ctx, importID, ImportRunning, time.Time{}); err != nil {
+				return err
+			}
+			renewed = time.Now()
+		}
+
+		row := &rows[i]
+		if err := s.processRow(ctx, row, dryRun); err != nil {
+			return err
+		}
+
+		if err := s.repository.UpdateRow(ctx, importID, row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processRow settles the status of a row that passed validation. Email is
+// the idempotency key: a user that already exists is never touched, so the
+// same file can be imported again safely; one who has yet to accept their
+// invitation is only sent a new one. A row the database refuses is marked
+// invalid rather than failing the import.
+func (s *importService) processRow(ctx context.Context, row *UserImportRow, dryRun bool) error {
+	if dryRun {
+		exists, err := s.users.EmailExists(ctx, row.Email)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			row.Status, row.Message = ImportRowSkipped, ErrUserExists.Error()
+		} else {
+			row.Status = ImportRowValid
+		}
+		return nil
+	}
+
+	_, err := s.invitations.Invite(ctx, Invitee{
+		Name:     row.Name,
+		Email:    row.Email,
+		Role:     row.Role,
+		AvatarID: row.AvatarID,
+	})
+
+	switch {
+	case err == nil:
+		row.Status = ImportRowCreated
+	case errors.Is(err, ErrUserExists), errors.Is(err, ErrInvitationResent):
+		row.Status, row.Message = ImportRowSkipped, err.Error()
+	case errors.Is(err, ErrInvitationNotSent):
+		row.Status, row.Message = ImportRowCreated, err.Error()
+	default:
+		log.Printf("user import row %d: %v", row.Number, err)
+		row.Status, row.Message = ImportRowInvalid, "user could not be created"
+	}
+
+	return nil
+}
+
+// parseUserImport reads a CSV with a header naming its columns. name and
+// email are required; role and avatar are optional. Rows failing validation
+// come back already marked invalid.
+func parseUserImport(file io.Reader) ([]UserImportRow, error) {
+	r := csv.NewReader(file)
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidImportFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		switch name {
+		case "name", "email", "role", "avatar":
+			if _, dup := columns[name]; dup {
+				return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidImportFile, name)
+			}
+			columns[name] = i
+		default:
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidImportFile, name)
+		}
+	}
+
+	for _, required := range []string{"name", "email"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidImportFile, required)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []UserImportRow
+	seen := map[string]bool{}
+
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, ErrImportTooLarge
+		}
+
+		line, _ := r.FieldPos(0)
+		row := UserImportRow{
+			Number:   line,
+			Name:     field(record, "name"),
+			Email:    field(record, "email"),
+			Role:     strings.ToLower(field(record, "role")),
+			AvatarID: field(record, "avatar"),
+			Status:   ImportRowPending,
+		}
+
+		if row.Role == "" {
+			row.Role = RoleUser
+		}
+
+		if msg := validateImportRow(&row); msg != "" {
+			row.Status, row.Message = ImportRowInvalid, msg
+		} else if key := strings.ToLower(row.Email); seen[key] {
+			row.Status, row.Message = ImportRowSkipped, "duplicate email in file"
+		} else {
+			seen[key] = true
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows after the header", ErrInvalidImportFile)
+	}
+
+	return rows, nil
+}
+
+// validateImportRow normalizes row in place and describes the first
+// problem found, or returns "" when the row is valid.
+func validateImportRow(row *UserImportRow) string {
+	if row.Name == "" {
+		return ErrInvalidName.Error()
+	}
+
+	addr, err := mail.ParseAddress(row.Email)
+	if err != nil || addr.Name != "" {
+		return ErrInvalidEmail.Error()
+	}
+	row.Email = addr.Address
+
+	if row.Role != RoleUser && row.Role != RoleAdmin {
+		return fmt.Sprintf("invalid role %q", row.Role)
+	}
+
+	if row.AvatarID != "" && uuid.Validate(row.AvatarID) != nil {
+		return "invalid avatar id"
+	}
+
+	return ""
+}
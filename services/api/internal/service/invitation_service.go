@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type InvitationService interface {
+	Invite(ctx context.Context, invitee Invitee) (*User, error)
+	AcceptInvitation(ctx context.Context, token, password string) error
+}
+
+type InvitationRepository interface {
+	EmailExists(ctx context.Context, email string) (bool, error)
+	Invite(ctx context.Context, user *User, invitee Invitee, inv *Invitation) (bool, error)
+	GetPendingInvitation(ctx context.Context, tokenHash string) (*Invitation, error)
+	AcceptInvitation(ctx context.Context, inv *Invitation, passwordHash string, at time.Time) (bool, error)
+}
+
+// Mailer delivers plain-text email.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// Invitee is a user created on someone else's behalf. They choose their own
+// password by accepting the invitation emailed to them.
+type Invitee struct {
+	Name     string
+	Email    string
+	Role     string
+	AvatarID string
+}
+
+type Invitation struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+const minPasswordLength = 8
+
+type invitationService struct {
+	repository InvitationRepository
+	mailer     Mailer
+	acceptURL  string
+	ttl        time.Duration
+}
+
+// NewInvitationService sends invitation links of the form
+// acceptURL?token=... that stay valid for ttl.
+func NewInvitationService(r InvitationRepository, m Mailer, acceptURL string, ttl time.Duration) InvitationService {
+	return &invitationService{
+		repository: r,
+		mailer:     m,
+		acceptURL:  acceptURL,
+		ttl:        ttl,
+	}
+}
+
+var (
+	ErrInvitationNotFound = errors.New("invitation not found or already used")
+	ErrInvitationExpired  = errors.New("invitation has expired")
+	ErrInvitationNotSent  = errors.New("invitation email could not be sent")
+	ErrInvitationResent   = errors.New("user exists, their pending invitation was sent again")
+	ErrWeakPassword       = fmt.Errorf("password must be at least %d characters", minPasswordLength)
+)
+
+// Invite creates the user and emails them an invitation. It returns
+// ErrUserExists when the email is already taken, except by a user invited
+// before who has yet to accept: they are sent a new invitation, replacing
+// the old one, and returned together with ErrInvitationResent. If the user
+// was invited but the email failed, the user is returned together with
+// ErrInvitationNotSent.
+func (s *invitationService) Invite(ctx context.Context, invitee Invitee) (*User, error) {
+	token, err := newInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	id := uuid.NewString()
+	user := &User{
+		ID:        id,
+		Email:     invitee.Email,
+		Name:      invitee.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	inv := &Invitation{
+		ID:        uuid.NewString(),
+		TokenHash: hashInvitationToken(token),
+		ExpiresAt: now.Add(s.ttl),
+		CreatedAt: now,
+	}
+
+	invited, err := s.repository.Invite(ctx, user, invitee, inv)
+	if err != nil {
+		return nil, err
+	}
+
+	if !invited {
+		return nil, ErrUserExists
+	}
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\nAn account has been created for you. Choose a password to sign in:\n\n%s?token=%s\n\nThis link expires on %s.\n",
+		user.Name,
+		s.acceptURL,
+		url.QueryEscape(token),
+		inv.ExpiresAt.Format(time.RFC1123),
+	)
+
+	if err := s.mailer.Send(ctx, user.Email, "You have been invited to the metaverse", body); err != nil {
+		return user, fmt.Errorf("%w: %v", ErrInvitationNotSent, err)
+	}
+
+	if user.ID != id {
+		return user, ErrInvitationResent
+	}
+
+	return user, nil
+}
+
+func (s *invitationService) AcceptInvitation(ctx context.Context, token, password string) error {
+	if len(password) < minPasswordLength {
+		return ErrWeakPassword
+	}
+
+	inv, err := s.repository.GetPendingInvitation(ctx, hashInvitationToken(token))
+	if err != nil {
+		return err
+	}
+
+	if inv == nil {
+		return ErrInvitationNotFound
+	}
+
+	now := time.Now().UTC()
+	if now.After(inv.ExpiresAt) {
+		return ErrInvitationExpired
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	accepted, err := s.repository.AcceptInvitation(ctx, inv, string(hash), now)
+	if err != nil {
+		return err
+	}
+
+	if !accepted {
+		return ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+func newInvitationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashInvitationToken is what gets stored, so a leaked table cannot be used
+// to take over invited accounts.
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
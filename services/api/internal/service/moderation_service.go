@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ModerationService interface {
+	Kick(ctx context.Context, moderatorID, spaceID, userID, reason string) error
+	Mute(ctx context.Context, moderatorID, spaceID, userID, reason string, d time.Duration) (*SpaceSanction, error)
+	Unmute(ctx context.Context, moderatorID, spaceID, userID string) error
+	Ban(ctx context.Context, moderatorID, spaceID, userID, reason string, d time.Duration) (*SpaceSanction, error)
+	Unban(ctx context.Context, moderatorID, spaceID, userID string) error
+	ListSanctions(ctx context.Context, moderatorID, spaceID string) ([]SpaceSanction, error)
+	ActiveSanctions(ctx context.Context, spaceID, userID string) ([]SpaceSanction, error)
+}
+
+// ModerationListener is told about moderator actions so connected clients
+// can be notified and disconnected.
+type ModerationListener interface {
+	// UserKicked disconnects userID from spaceID and reports whether they
+	// were connected to it.
+	UserKicked(spaceID, userID, reason string) bool
+	SanctionsChanged(spaceID, userID string)
+}
+
+const (
+	SanctionMute = "mute"
+	SanctionBan  = "ban"
+)
+
+// SpaceSanction is a mute or ban of a user in one space. A zero ExpiresAt
+// lasts until it is lifted.
+type SpaceSanction struct {
+	SpaceID     string
+	UserID      string
+	Kind        string
+	Reason      string
+	ModeratorID string
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+}
+
+type moderationService struct {
+	repository SpaceRepository
+	listener   ModerationListener
+}
+
+func NewModerationService(r SpaceRepository, l ModerationListener) ModerationService {
+	return &moderationService{
+		repository: r,
+		listener:   l,
+	}
+}
+
+var (
+	ErrInvalidSanctionDuration = errors.New("invalid sanction duration")
+	ErrCannotModerate          = errors.New("cannot moderate this user")
+	ErrUserNotInSpace          = errors.New("user is not in this space")
+	ErrSanctionNotFound        = errors.New("sanction not found")
+)
+
+func (s *moderationService) Kick(ctx context.Context, moderatorID, spaceID, userID, reason string) error {
+	if _, err := s.authorize(ctx, moderatorID, spaceID, userID); err != nil {
+		return err
+	}
+
+	if !s.listener.UserKicked(spaceID, userID, reason) {
+		return ErrUserNotInSpace
+	}
+
+	return nil
+}
+
+// Mute silences userID's chat in the space for d.
+func (s *moderationService) Mute(
+	ctx context.Context,
+	moderatorID string,
+	spaceID string,
+	userID string,
+	reason string,
+	d time.Duration,
+) (*SpaceSanction, error) {
+
+	if d <= 0 {
+		return nil, ErrInvalidSanctionDuration
+	}
+
+	return s.sanction(ctx, moderatorID, spaceID, userID, SanctionMute, reason, d)
+}
+
+func (s *moderationService) Unmute(ctx context.Context, moderatorID, spaceID, userID string) error {
+	return s.lift(ctx, moderatorID, spaceID, userID, SanctionMute)
+}
+
+// Ban keeps userID out of the space for d, or until lifted when d is zero,
+// and ends their membership.
+func (s *moderationService) Ban(
+	ctx context.Context,
+	moderatorID string,
+	spaceID string,
+	userID string,
+	reason string,
+	d time.Duration,
+) (*SpaceSanction, error) {
+
+	if d < 0 {
+		return nil, ErrInvalidSanctionDuration
+	}
+
+	sanction, err := s.sanction(ctx, moderatorID, spaceID, userID, SanctionBan, reason, d)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repository.RemoveMember(ctx, spaceID, userID); err != nil {
+		return nil, err
+	}
+
+	return sanction, nil
+}
+
+func (s *moderationService) Unban(ctx context.Context, moderatorID, spaceID, userID string) error {
+	return s.lift(ctx, moderatorID, spaceID, userID, SanctionBan)
+}
+
+func (s *moderationService) ListSanctions(ctx context.Context, moderatorID, spaceID string) ([]SpaceSanction, error) {
+	if _, err := moderatedSpace(ctx, s.repository, moderatorID, spaceID); err != nil {
+		return nil, err
+	}
+
+	return s.repository.ListActiveSanctions(ctx, spaceID, time.Now().UTC())
+}
+
+func (s *moderationService) ActiveSanctions(ctx context.Context, spaceID, userID string) ([]SpaceSanction, error) {
+	return s.repository.ListActiveUserSanctions(ctx, spaceID, userID, time.Now().UTC())
+}
+
+func (s *moderationService) sanction(
+	ctx context.Context,
+	moderatorID string,
+	spaceID string,
+	userID string,
+	kind string,
+	reason string,
+	d time.Duration,
+) (*SpaceSanction, error) {
+
+	if _, err := s.authorize(ctx, moderatorID, spaceID, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	sanction := &SpaceSanction{
+		SpaceID:     spaceID,
+		UserID:      userID,
+		Kind:        kind,
+		Reason:      reason,
+		ModeratorID: moderatorID,
+		CreatedAt:   now,
+	}
+	if d > 0 {
+		sanction.ExpiresAt = now.Add(d)
+	}
+
+	if err := s.repository.UpsertSanction(ctx, sanction); err != nil {
+		return nil, err
+	}
+
+	s.listener.SanctionsChanged(spaceID, userID)
+
+	return sanction, nil
+}
+
+func (s *moderationService) lift(ctx context.Context, moderatorID, spaceID, userID, kind string) error {
+	if _, err := s.authorize(ctx, moderatorID, spaceID, userID); err != nil {
+		return err
+	}
+
+	deleted, err := s.repository.DeleteSanction(ctx, spaceID, userID, kind)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrSanctionNotFound
+	}
+
+	s.listener.SanctionsChanged(spaceID, userID)
+
+	return nil
+}
+
+// authorize checks that moderatorID may act on userID in the space. Only the
+// owner can act on moderators, and nobody can act on the owner.
+func (s *moderationService) authorize(ctx context.Context, moderatorID, spaceID, userID string) (*Space, error) {
+	if uuid.Validate(userID) != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	space, err := moderatedSpace(ctx, s.repository, moderatorID, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID == moderatorID || userID == space.CreatorID {
+		return nil, ErrCannotModerate
+	}
+
+	if space.CreatorID != moderatorID {
+		role, err := spaceRole(ctx, s.repository, space, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		if role == SpaceRoleModerator {
+			return nil, ErrCannotModerate
+		}
+	}
+
+	return space, nil
+}
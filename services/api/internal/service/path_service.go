@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vaxxnsh/metaverse/api/internal/navigation"
+)
+
+// PathService answers path queries for tools, over the same walkability
+// rules the real-time server enforces.
+type PathService interface {
+	FindPath(ctx context.Context, userID, spaceID, password string, from, to Point) ([]Point, error)
+}
+
+type ObstacleRepository interface {
+	ListObstacles(ctx context.Context, spaceID string) ([]Obstacle, error)
+}
+
+type pathService struct {
+	spaces    SpaceService
+	obstacles ObstacleRepository
+	zones     ZoneRepository
+}
+
+func NewPathService(spaces SpaceService, obstacles ObstacleRepository, zones ZoneRepository) PathService {
+	return &pathService{
+		spaces:    spaces,
+		obstacles: obstacles,
+		zones:     zones,
+	}
+}
+
+var (
+	ErrInvalidPathPoint = errors.New("path points must be walkable tiles of the space")
+	ErrNoPath           = errors.New("no path between the points")
+)
+
+// FindPath returns the steps from one tile to another, excluding the start,
+// for userID walking the space with their role.
+func (s *pathService) FindPath(ctx context.Context, userID, spaceID, password string, from, to Point) ([]Point, error) {
+	space, err := s.spaces.EnterSpace(ctx, userID, spaceID, password)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.spaces.MemberRole(ctx, spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	obstacles, err := s.obstacles.ListObstacles(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := s.zones.ListBySpace(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	grid := navigation.NewGrid(space.Width, space.Height)
+	for _, o := range obstacles {
+		grid.Cover(o.X, o.Y, o.Width, o.Height, 1)
+	}
+
+	walkable := func(x, y int) bool {
+		if !grid.Open(x, y) {
+			return false
+		}
+		for i := range zones {
+			if zones[i].Contains(x, y) && !zones[i].Admits(role) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if !walkable(from.X, from.Y) || !walkable(to.X, to.Y) {
+		return nil, ErrInvalidPathPoint
+	}
+
+	path := navigation.FindPath(navigation.Point(from), navigation.Point(to), walkable)
+	if path == nil {
+		return nil, ErrNoPath
+	}
+
+	steps := make([]Point, len(path))
+	for i, p := range path {
+		steps[i] = Point(p)
+	}
+
+	return steps, nil
+}
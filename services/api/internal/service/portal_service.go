@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PortalService interface {
+	ListPortals(ctx context.Context, userID, spaceID, password string) ([]Portal, error)
+	SetPortal(ctx context.Context, userID, spaceID, elementID, destinationID string, spawn Point) (*Portal, error)
+	DeletePortal(ctx context.Context, userID, spaceID, elementID string) error
+}
+
+type PortalRepository interface {
+	Upsert(ctx context.Context, portal *Portal) error
+	Delete(ctx context.Context, spaceID, elementID string) (bool, error)
+	ListBySpace(ctx context.Context, spaceID string) ([]Portal, error)
+}
+
+// PortalListener is told when the portals of a space change so live rooms
+// can pick them up.
+type PortalListener interface {
+	PortalsChanged(spaceID string)
+}
+
+// Portal turns a placed element into a link: stepping on the element's tile
+// moves the user to Spawn in the destination space, which may be the same
+// space for a teleporter.
+type Portal struct {
+	ElementID     string
+	SpaceID       string
+	X             int
+	Y             int
+	DestinationID string
+	Spawn         Point
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type portalService struct {
+	repository PortalRepository
+	spaces     SpaceService
+	listener   PortalListener
+}
+
+func NewPortalService(r PortalRepository, spaces SpaceService, l PortalListener) PortalService {
+	return &portalService{
+		repository: r,
+		spaces:     spaces,
+		listener:   l,
+	}
+}
+
+var (
+	ErrPortalNotFound           = errors.New("portal not found")
+	ErrInvalidPortalDestination = errors.New("invalid portal destination")
+)
+
+// ListPortals returns the portals of a space to anyone allowed into it.
+func (s *portalService) ListPortals(ctx context.Context, userID, spaceID, password string) ([]Portal, error) {
+	if _, err := s.spaces.EnterSpace(ctx, userID, spaceID, password); err != nil {
+		return nil, err
+	}
+
+	return s.repository.ListBySpace(ctx, spaceID)
+}
+
+// SetPortal links an element of a space the user manages to a destination
+// the user may enter themselves.
+func (s *portalService) SetPortal(
+	ctx context.Context,
+	userID, spaceID, elementID, destinationID string,
+	spawn Point,
+) (*Portal, error) {
+	_, elements, err := s.spaces.GetSpace(ctx, userID, spaceID, "")
+	if err != nil {
+		return nil, managedSpaceError(err)
+	}
+
+	if err := s.checkManager(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+
+	var element *SpaceElement
+	for i := range elements {
+		if elements[i].ID == elementID {
+			element = &elements[i]
+		}
+	}
+	if element == nil {
+		return nil, ErrSpaceElementNotFound
+	}
+
+	destination, err := s.spaces.EnterSpace(ctx, userID, destinationID, "")
+	switch err {
+	case nil:
+	case ErrInvalidSpaceID, ErrSpaceNotFound, ErrSpaceInviteOnly, ErrSpacePasswordRequired, ErrSpaceBanned:
+		return nil, ErrInvalidPortalDestination
+	default:
+		return nil, err
+	}
+
+	if spawn.X < 0 || spawn.Y < 0 || spawn.X >= destination.Width || spawn.Y >= destination.Height {
+		return nil, ErrInvalidPortalDestination
+	}
+
+	// A teleporter onto its own tile would fire forever.
+	if destination.ID == spaceID && spawn.X == element.X && spawn.Y == element.Y {
+		return nil, ErrInvalidPortalDestination
+	}
+
+	now := time.Now().UTC()
+	portal := &Portal{
+		ElementID:     element.ID,
+		SpaceID:       spaceID,
+		X:             element.X,
+		Y:             element.Y,
+		DestinationID: destination.ID,
+		Spawn:         spawn,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.repository.Upsert(ctx, portal); err != nil {
+		return nil, err
+	}
+
+	s.listener.PortalsChanged(spaceID)
+
+	return portal, nil
+}
+
+func (s *portalService) DeletePortal(ctx context.Context, userID, spaceID, elementID string) error {
+	if uuid.Validate(elementID) != nil {
+		return ErrPortalNotFound
+	}
+
+	if _, err := s.spaces.EnterSpace(ctx, userID, spaceID, ""); err != nil {
+		return managedSpaceError(err)
+	}
+
+	if err := s.checkManager(ctx, userID, spaceID); err != nil {
+		return err
+	}
+
+	deleted, err := s.repository.Delete(ctx, spaceID, elementID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrPortalNotFound
+	}
+
+	s.listener.PortalsChanged(spaceID)
+
+	return nil
+}
+
+// checkManager allows owners and moderators of spaceID to edit its portals.
+func (s *portalService) checkManager(ctx context.Context, userID, spaceID string) error {
+	role, err := s.spaces.MemberRole(ctx, spaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	if role != SpaceRoleOwner && role != SpaceRoleModerator {
+		return ErrSpaceForbidden
+	}
+
+	return nil
+}
+
+// managedSpaceError reports a space the user may not enter as forbidden when
+// they try to manage its contents.
+func managedSpaceError(err error) error {
+	if err == ErrSpaceInviteOnly || err == ErrSpacePasswordRequired {
+		return ErrSpaceForbidden
+	}
+	return err
+}
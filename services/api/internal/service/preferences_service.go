@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/jsonschema"
+)
+
+type PreferencesService interface {
+	Get(ctx context.Context, userID string) (*Preferences, error)
+	Patch(ctx context.Context, userID string, ifMatch *int, patch []byte) (*Preferences, error)
+}
+
+type PreferencesRepository interface {
+	Get(ctx context.Context, userID string) (*Preferences, error)
+
+	// Insert creates the first version of a user's document and returns nil
+	// if one already exists.
+	Insert(ctx context.Context, userID string, document []byte) (*Preferences, error)
+
+	// Update replaces the document only if it is still at expectedVersion
+	// and returns nil otherwise.
+	Update(ctx context.Context, userID string, document []byte, expectedVersion int) (*Preferences, error)
+}
+
+// Preferences is a user's settings document. Version starts at 0 for users
+// who have never saved anything and increases by one on every write.
+type Preferences struct {
+	UserID    string
+	Version   int
+	Document  json.RawMessage
+	UpdatedAt time.Time
+}
+
+// PreferencesValidationError lists every way a document fails the
+// preferences schema.
+type PreferencesValidationError struct {
+	Errors []jsonschema.ValidationError
+}
+
+func (e *PreferencesValidationError) Error() string {
+	return "preferences do not match schema"
+}
+
+//go:embed preferences.schema.json
+var preferencesSchemaJSON []byte
+
+var preferencesSchema = mustParseSchema(preferencesSchemaJSON)
+
+func mustParseSchema(data []byte) *jsonschema.Schema {
+	s, err := jsonschema.Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+type preferencesService struct {
+	repository PreferencesRepository
+}
+
+func NewPreferencesService(r PreferencesRepository) PreferencesService {
+	return &preferencesService{
+		repository: r,
+	}
+}
+
+var (
+	ErrInvalidMergePatch   = errors.New("merge patch must be a JSON object")
+	ErrPreferencesConflict = errors.New("preferences were modified by another request")
+)
+
+func (s *preferencesService) Get(ctx context.Context, userID string) (*Preferences, error) {
+	prefs, err := s.repository.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if prefs == nil {
+		return &Preferences{
+			UserID:   userID,
+			Version:  0,
+			Document: json.RawMessage("{}"),
+		}, nil
+	}
+
+	return prefs, nil
+}
+
+// Patch applies an RFC 7396 JSON Merge Patch to the user's document. When
+// ifMatch is set the write only succeeds if the stored version still equals
+// it; either way a concurrent write between read and update is rejected.
+func (s *preferencesService) Patch(
+	ctx context.Context,
+	userID string,
+	ifMatch *int,
+	patch []byte,
+) (*Preferences, error) {
+
+	var patchDoc any
+	if err := json.Unmarshal(patch, &patchDoc); err != nil {
+		return nil, ErrInvalidMergePatch
+	}
+
+	if _, ok := patchDoc.(map[string]any); !ok {
+		return nil, ErrInvalidMergePatch
+	}
+
+	current, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ifMatch != nil && *ifMatch != current.Version {
+		return nil, ErrPreferencesConflict
+	}
+
+	var doc any
+	if err := json.Unmarshal(current.Document, &doc); err != nil {
+		return nil, err
+	}
+
+	doc = mergePatch(doc, patchDoc)
+
+	if errs := preferencesSchema.Validate(doc); len(errs) > 0 {
+		return nil, &PreferencesValidationError{Errors: errs}
+	}
+
+	document, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *Preferences
+	if current.Version == 0 {
+		updated, err = s.repository.Insert(ctx, userID, document)
+	} else {
+		updated, err = s.repository.Update(ctx, userID, document, current.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if updated == nil {
+		return nil, ErrPreferencesConflict
+	}
+
+	return updated, nil
+}
+
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any)
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+
+	return t
+}
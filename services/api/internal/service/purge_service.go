@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// TrashPurger permanently deletes rows that were soft-deleted before a
+// cutoff.
+type TrashPurger interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Purger empties the trash of every resource type once items have been
+// there longer than the retention window.
+type Purger struct {
+	purgers   []TrashPurger
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewPurger(retention, interval time.Duration, purgers ...TrashPurger) *Purger {
+	return &Purger{
+		purgers:   purgers,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Run purges once immediately and then on every interval until ctx is done.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	cutoff := time.Now().UTC().Add(-p.retention)
+
+	for _, purger := range p.purgers {
+		n, err := purger.PurgeDeleted(ctx, cutoff)
+		if err != nil {
+			log.Println("trash purge failed:", err)
+			continue
+		}
+
+		if n > 0 {
+			log.Printf("trash purge removed %d rows", n)
+		}
+	}
+}
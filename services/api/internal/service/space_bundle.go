@@ -0,0 +1,661 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SpaceBundleService moves a designed space between environments as a
+// portable bundle: the space, its zones, placed elements and portals, and
+// the catalog elements they use.
+type SpaceBundleService interface {
+	ExportSpace(ctx context.Context, userID, spaceID string) (*SpaceBundle, error)
+	ImportSpace(ctx context.Context, userID string, file io.Reader) (*SpaceImport, error)
+	ImportTiledMap(ctx context.Context, userID string, file io.Reader, opts TiledImportOptions) (*SpaceImport, error)
+}
+
+const (
+	SpaceBundleFormat  = "metaverse-space"
+	SpaceBundleVersion = 1
+
+	// SpaceBundleFile is the name of the bundle inside a zip export.
+	SpaceBundleFile = "space.json"
+)
+
+const (
+	maxSpaceBundleSize = 10 << 20
+	maxSpaceDimension  = 1000
+)
+
+// Kinds of conflict reported by an import. Conflicting entries are skipped,
+// except catalog elements whose hash is recomputed from their content.
+const (
+	BundleConflictElementHash = "element-hash"
+	BundleConflictPlacement   = "placement"
+	BundleConflictZone        = "zone"
+	BundleConflictPortal      = "portal"
+)
+
+// SpaceBundle is the portable form of a space. IDs are those of the
+// exporting environment and are only used to link entries to each other.
+type SpaceBundle struct {
+	Format     string          `json:"format"`
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exportedAt"`
+	Space      BundleSpace     `json:"space"`
+	Elements   []BundleElement `json:"elements"`
+	Placements []LayoutElement `json:"placements"`
+	Zones      []BundleZone    `json:"zones"`
+	Portals    []BundlePortal  `json:"portals"`
+}
+
+type BundleSpace struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+	Capacity  int    `json:"capacity,omitempty"`
+}
+
+// BundleElement is a catalog element. Hash identifies its content across
+// environments; see ElementHash.
+type BundleElement struct {
+	ID       string `json:"id"`
+	ImageURL string `json:"imageUrl"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Static   bool   `json:"static"`
+	Hash     string `json:"hash"`
+}
+
+type BundleZone struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Shape      string          `json:"shape"`
+	Points     []Point         `json:"points"`
+	Properties json.RawMessage `json:"properties,omitempty"`
+	ChatScope  string          `json:"chatScope,omitempty"`
+	Audio      string          `json:"audio,omitempty"`
+	MinRole    string          `json:"minRole,omitempty"`
+}
+
+// BundlePortal links a placement to a destination, which is either the
+// bundle's own space or a space in the importing environment.
+type BundlePortal struct {
+	ElementID          string `json:"elementId"`
+	DestinationSpaceID string `json:"destinationSpaceId"`
+	Spawn              Point  `json:"spawn"`
+}
+
+// SpaceImport reports what an import created. Elements maps each catalog
+// element of the bundle to the one used in this environment.
+type SpaceImport struct {
+	Space     *Space
+	Elements  []ImportedElement
+	Conflicts []BundleConflict
+}
+
+type ImportedElement struct {
+	BundleID     string
+	ElementID    string
+	Deduplicated bool
+}
+
+// BundleConflict is an entry of the bundle that could not be imported as
+// is. ID is the entry's id in the bundle.
+type BundleConflict struct {
+	Kind    string
+	ID      string
+	Message string
+}
+
+// SpaceBundleError explains why a bundle was rejected.
+type SpaceBundleError struct {
+	Message string
+}
+
+func (e *SpaceBundleError) Error() string {
+	return ErrInvalidSpaceBundle.Error() + ": " + e.Message
+}
+
+func (e *SpaceBundleError) Unwrap() error {
+	return ErrInvalidSpaceBundle
+}
+
+var (
+	ErrInvalidSpaceBundle  = errors.New("invalid space bundle")
+	ErrSpaceBundleTooLarge = errors.New("space bundle is too large")
+)
+
+// ElementHash returns the content hash of a catalog element, which is the
+// same for identical elements in any environment. The database computes the
+// same hash for stored elements.
+func ElementHash(imageURL string, width, height int, static bool) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s|%dx%d|%t", imageURL, width, height, static))
+	return hex.EncodeToString(sum[:])
+}
+
+type spaceBundleService struct {
+	spaces     SpaceService
+	repository SpaceRepository
+	elements   ElementRepository
+	zones      ZoneRepository
+	portals    PortalRepository
+}
+
+func NewSpaceBundleService(
+	spaces SpaceService,
+	r SpaceRepository,
+	elements ElementRepository,
+	zones ZoneRepository,
+	portals PortalRepository,
+) SpaceBundleService {
+	return &spaceBundleService{
+		spaces:     spaces,
+		repository: r,
+		elements:   elements,
+		zones:      zones,
+		portals:    portals,
+	}
+}
+
+// ExportSpace bundles a space for its owner. Elements in the trash are left
+// out.
+func (s *spaceBundleService) ExportSpace(ctx context.Context, userID, spaceID string) (*SpaceBundle, error) {
+	if uuid.Validate(spaceID) != nil {
+		return nil, ErrInvalidSpaceID
+	}
+
+	space, err := s.repository.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if space == nil {
+		return nil, ErrSpaceNotFound
+	}
+
+	if space.CreatorID != userID {
+		return nil, ErrSpaceForbidden
+	}
+
+	catalog, err := s.elements.ListBySpace(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	placements, err := s.repository.ListElements(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := s.zones.ListBySpace(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	portals, err := s.portals.ListBySpace(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &SpaceBundle{
+		Format:     SpaceBundleFormat,
+		Version:    SpaceBundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Space: BundleSpace{
+			ID:        space.ID,
+			Name:      space.Name,
+			Width:     space.Width,
+			Height:    space.Height,
+			Thumbnail: space.Thumbnail,
+			Capacity:  space.Capacity,
+		},
+		Elements:   make([]BundleElement, 0, len(catalog)),
+		Placements: make([]LayoutElement, 0, len(placements)),
+		Zones:      make([]BundleZone, 0, len(zones)),
+		Portals:    make([]BundlePortal, 0, len(portals)),
+	}
+
+	for _, e := range catalog {
+		bundle.Elements = append(bundle.Elements, BundleElement{
+			ID:       e.ID,
+			ImageURL: e.ImageURL,
+			Width:    e.Width,
+			Height:   e.Height,
+			Static:   e.Static,
+			Hash:     ElementHash(e.ImageURL, e.Width, e.Height, e.Static),
+		})
+	}
+
+	for _, el := range placements {
+		bundle.Placements = append(bundle.Placements, LayoutElement{
+			ID:        el.ID,
+			ElementID: el.ElementID,
+			X:         el.X,
+			Y:         el.Y,
+		})
+	}
+
+	for _, z := range zones {
+		bundle.Zones = append(bundle.Zones, BundleZone{
+			ID:         z.ID,
+			Name:       z.Name,
+			Shape:      z.Shape,
+			Points:     z.Points,
+			Properties: z.Properties,
+			ChatScope:  z.ChatScope,
+			Audio:      z.Audio,
+			MinRole:    z.MinRole,
+		})
+	}
+
+	for _, p := range portals {
+		bundle.Portals = append(bundle.Portals, BundlePortal{
+			ElementID:          p.ElementID,
+			DestinationSpaceID: p.DestinationID,
+			Spawn:              p.Spawn,
+		})
+	}
+
+	return bundle, nil
+}
+
+// WriteSpaceBundleZip writes bundle as a zip archive holding SpaceBundleFile.
+func WriteSpaceBundleZip(w io.Writer, bundle *SpaceBundle) error {
+	zw := zip.NewWriter(w)
+
+	f, err := zw.Create(SpaceBundleFile)
+	if err != nil {
+		return err
+	}
+
+	if err := json.NewEncoder(f).Encode(bundle); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// ImportSpace creates a new space owned by userID from a bundle, given as
+// JSON or as a zip export. Every entry gets a fresh id. Catalog elements
+// are reused when an identical one exists, matched by content hash. The new
+// space is invite-only, since bundles carry no access settings.
+func (s *spaceBundleService) ImportSpace(ctx context.Context, userID string, file io.Reader) (*SpaceImport, error) {
+	bundle, err := readSpaceBundle(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateSpaceBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	return s.importBundle(ctx, userID, bundle, nil)
+}
+
+// importBundle creates a space from a validated bundle, adding to conflicts
+// already found while building it.
+func (s *spaceBundleService) importBundle(
+	ctx context.Context,
+	userID string,
+	bundle *SpaceBundle,
+	conflicts []BundleConflict,
+) (*SpaceImport, error) {
+	imp := &SpaceImport{
+		Elements:  make([]ImportedElement, 0, len(bundle.Elements)),
+		Conflicts: append([]BundleConflict{}, conflicts...),
+	}
+	conflict := func(kind, id, format string, args ...any) {
+		imp.Conflicts = append(imp.Conflicts, BundleConflict{
+			Kind:    kind,
+			ID:      id,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	elementIDs, err := s.importElements(ctx, bundle, imp, conflict)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	space := &Space{
+		ID:         uuid.NewString(),
+		Name:       bundle.Space.Name,
+		Width:      bundle.Space.Width,
+		Height:     bundle.Space.Height,
+		Thumbnail:  bundle.Space.Thumbnail,
+		CreatorID:  userID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Visibility: SpaceInviteOnly,
+		Capacity:   bundle.Space.Capacity,
+	}
+
+	if err := s.repository.Create(ctx, space); err != nil {
+		return nil, err
+	}
+
+	err = s.importLayout(ctx, bundle, space, elementIDs, conflict)
+	if err != nil {
+		// Leave the half-built space to be purged with the trash.
+		if _, delErr := s.repository.SoftDelete(ctx, space.ID, time.Now().UTC()); delErr != nil {
+			log.Println("discard imported space:", delErr)
+		}
+		return nil, err
+	}
+
+	imp.Space = space
+	return imp, nil
+}
+
+// importElements finds or creates the catalog elements of a bundle and
+// returns the local id for each bundle id.
+func (s *spaceBundleService) importElements(
+	ctx context.Context,
+	bundle *SpaceBundle,
+	imp *SpaceImport,
+	conflict func(kind, id, format string, args ...any),
+) (map[string]string, error) {
+	ids := make(map[string]string, len(bundle.Elements))
+
+	for _, e := range bundle.Elements {
+		if _, ok := ids[e.ID]; ok {
+			continue
+		}
+
+		hash := ElementHash(e.ImageURL, e.Width, e.Height, e.Static)
+		if e.Hash != hash {
+			conflict(BundleConflictElementHash, e.ID, "hash does not match the element, using %s", hash)
+		}
+
+		existing, err := s.elements.GetByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing != nil {
+			ids[e.ID] = existing.ID
+			imp.Elements = append(imp.Elements, ImportedElement{
+				BundleID:     e.ID,
+				ElementID:    existing.ID,
+				Deduplicated: true,
+			})
+			continue
+		}
+
+		now := time.Now().UTC()
+		created := &Element{
+			ID:          uuid.NewString(),
+			ImageURL:    e.ImageURL,
+			Width:       e.Width,
+			Height:      e.Height,
+			Static:      e.Static,
+			ContentHash: hash,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+
+		if err := s.elements.Create(ctx, created); err != nil {
+			return nil, err
+		}
+
+		ids[e.ID] = created.ID
+		imp.Elements = append(imp.Elements, ImportedElement{
+			BundleID:  e.ID,
+			ElementID: created.ID,
+		})
+	}
+
+	return ids, nil
+}
+
+// importLayout adds the placements, zones and portals of a bundle to the
+// newly created space, skipping and reporting those that do not fit.
+func (s *spaceBundleService) importLayout(
+	ctx context.Context,
+	bundle *SpaceBundle,
+	space *Space,
+	elementIDs map[string]string,
+	conflict func(kind, id, format string, args ...any),
+) error {
+	now := time.Now().UTC()
+
+	err := s.repository.AddMember(ctx, &SpaceMember{
+		SpaceID:   space.ID,
+		UserID:    space.CreatorID,
+		Role:      SpaceRoleOwner,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	placements := make(map[string]*SpaceElement, len(bundle.Placements))
+	for _, p := range bundle.Placements {
+		elementID, ok := elementIDs[p.ElementID]
+		switch {
+		case !ok:
+			conflict(BundleConflictPlacement, p.ID, "element %s is not in the bundle", p.ElementID)
+			continue
+		case p.X < 0 || p.Y < 0 || p.X >= space.Width || p.Y >= space.Height:
+			conflict(BundleConflictPlacement, p.ID, "position %d,%d is outside the space", p.X, p.Y)
+			continue
+		case placements[p.ID] != nil:
+			conflict(BundleConflictPlacement, p.ID, "placement appears more than once")
+			continue
+		}
+
+		el := &SpaceElement{
+			ID:        uuid.NewString(),
+			SpaceID:   space.ID,
+			ElementID: elementID,
+			X:         p.X,
+			Y:         p.Y,
+			CreatedAt: now,
+		}
+
+		if err := s.repository.CreateElement(ctx, el); err != nil {
+			return err
+		}
+		placements[p.ID] = el
+	}
+
+	for _, z := range bundle.Zones {
+		zone := &Zone{
+			ID:         uuid.NewString(),
+			SpaceID:    space.ID,
+			Name:       z.Name,
+			Shape:      z.Shape,
+			Points:     z.Points,
+			Properties: z.Properties,
+			ChatScope:  z.ChatScope,
+			Audio:      z.Audio,
+			MinRole:    z.MinRole,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+
+		if err := validateZone(zone, space); err != nil {
+			conflict(BundleConflictZone, z.ID, "%s", err)
+			continue
+		}
+
+		if err := s.zones.Create(ctx, zone); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range bundle.Portals {
+		el := placements[p.ElementID]
+		if el == nil {
+			conflict(BundleConflictPortal, p.ElementID, "portal element was not imported")
+			continue
+		}
+
+		destination, err := s.portalDestination(ctx, space, bundle.Space.ID, p.DestinationSpaceID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case destination == nil:
+			conflict(BundleConflictPortal, p.ElementID, "destination %s is not available here", p.DestinationSpaceID)
+			continue
+		case p.Spawn.X < 0 || p.Spawn.Y < 0 || p.Spawn.X >= destination.Width || p.Spawn.Y >= destination.Height:
+			conflict(BundleConflictPortal, p.ElementID, "spawn %d,%d is outside the destination", p.Spawn.X, p.Spawn.Y)
+			continue
+		case destination.ID == space.ID && p.Spawn.X == el.X && p.Spawn.Y == el.Y:
+			conflict(BundleConflictPortal, p.ElementID, "teleporter spawns on its own tile")
+			continue
+		}
+
+		err = s.portals.Upsert(ctx, &Portal{
+			ElementID:     el.ID,
+			SpaceID:       space.ID,
+			X:             el.X,
+			Y:             el.Y,
+			DestinationID: destination.ID,
+			Spawn:         p.Spawn,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// portalDestination resolves a portal destination of a bundle: its own
+// space becomes the imported one, and any other must be a space the
+// importer can enter here. It returns nil if the destination is unusable.
+func (s *spaceBundleService) portalDestination(ctx context.Context, space *Space, bundleSpaceID, destinationID string) (*Space, error) {
+	if destinationID == bundleSpaceID {
+		return space, nil
+	}
+
+	destination, err := s.spaces.EnterSpace(ctx, space.CreatorID, destinationID, "")
+	switch err {
+	case nil:
+		return destination, nil
+	case ErrInvalidSpaceID, ErrSpaceNotFound, ErrSpaceInviteOnly, ErrSpacePasswordRequired, ErrSpaceBanned:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// readSpaceBundle decodes a bundle given as JSON or as a zip export.
+func readSpaceBundle(file io.Reader) (*SpaceBundle, error) {
+	data, err := readBundleFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		if data, err = unzipSpaceBundle(data); err != nil {
+			return nil, err
+		}
+	}
+
+	var bundle SpaceBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, &SpaceBundleError{Message: "malformed JSON"}
+	}
+
+	return &bundle, nil
+}
+
+// readBundleFile reads an uploaded file of at most maxSpaceBundleSize.
+func readBundleFile(file io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(file, maxSpaceBundleSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxSpaceBundleSize {
+		return nil, ErrSpaceBundleTooLarge
+	}
+
+	return data, nil
+}
+
+func unzipSpaceBundle(data []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, &SpaceBundleError{Message: "malformed zip"}
+	}
+
+	for _, f := range zr.File {
+		if f.Name != SpaceBundleFile {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, &SpaceBundleError{Message: "malformed zip"}
+		}
+		defer rc.Close()
+
+		content, err := io.ReadAll(io.LimitReader(rc, maxSpaceBundleSize+1))
+		if err != nil {
+			return nil, &SpaceBundleError{Message: "malformed zip"}
+		}
+		if len(content) > maxSpaceBundleSize {
+			return nil, ErrSpaceBundleTooLarge
+		}
+
+		return content, nil
+	}
+
+	return nil, &SpaceBundleError{Message: "zip has no " + SpaceBundleFile}
+}
+
+func validateSpaceBundle(b *SpaceBundle) error {
+	invalid := func(msg string) error {
+		return &SpaceBundleError{Message: msg}
+	}
+
+	if b.Format != SpaceBundleFormat {
+		return invalid("format must be " + SpaceBundleFormat)
+	}
+
+	if b.Version != SpaceBundleVersion {
+		return invalid(fmt.Sprintf("unsupported version %d", b.Version))
+	}
+
+	if b.Space.Name == "" {
+		return invalid("space needs a name")
+	}
+
+	if b.Space.Width <= 0 || b.Space.Height <= 0 || b.Space.Width > maxSpaceDimension || b.Space.Height > maxSpaceDimension {
+		return invalid(fmt.Sprintf("space dimensions must be 1 to %d", maxSpaceDimension))
+	}
+
+	if b.Space.Capacity < 0 {
+		return invalid("capacity cannot be negative")
+	}
+
+	for _, e := range b.Elements {
+		if e.ImageURL == "" || e.Width <= 0 || e.Height <= 0 {
+			return invalid("element " + e.ID + " needs an image and a positive size")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,517 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	SpacePublic = "public"
+	// SpaceUnlisted is open to anyone who has the id but is left out of
+	// space listings.
+	SpaceUnlisted   = "unlisted"
+	SpaceInviteOnly = "invite-only"
+	SpacePassword   = "password"
+)
+
+const (
+	SpaceRoleOwner     = "owner"
+	SpaceRoleModerator = "moderator"
+	SpaceRoleMember    = "member"
+)
+
+type SpaceMember struct {
+	SpaceID   string
+	UserID    string
+	Role      string
+	CreatedAt time.Time
+}
+
+// SpaceInvitation is a shareable link that makes whoever opens it a member.
+// A zero ExpiresAt never expires and a zero MaxUses is unlimited.
+type SpaceInvitation struct {
+	ID        string
+	SpaceID   string
+	Code      string
+	CreatedBy string
+	ExpiresAt time.Time
+	MaxUses   int
+	Uses      int
+	CreatedAt time.Time
+}
+
+var (
+	ErrInvalidSpaceVisibility = errors.New("invalid space visibility")
+	ErrSpaceInviteOnly        = errors.New("space is invite-only")
+	ErrSpacePasswordRequired  = errors.New("space requires a password")
+	ErrSpaceWrongPassword     = errors.New("wrong space password")
+	ErrInvalidSpaceRole       = errors.New("invalid space role")
+	ErrSpaceOwnerRole         = errors.New("the owner's membership cannot be changed")
+	ErrSpaceMemberNotFound    = errors.New("space member not found")
+	ErrInvalidSpaceInvitation = errors.New("invalid invitation expiry or max uses")
+	ErrSpaceInvitationInvalid = errors.New("invitation is invalid, expired or used up")
+	ErrSpaceBanned            = errors.New("you are banned from this space")
+	ErrInvalidSpaceCapacity   = errors.New("invalid space capacity")
+	ErrInvalidSpaceRateLimits = errors.New("invalid space rate limits")
+)
+
+func (s *spaceService) GetSpace(ctx context.Context, userID, spaceID, password string) (*Space, []SpaceElement, error) {
+	space, err := s.EnterSpace(ctx, userID, spaceID, password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	elements, err := s.repository.ListElements(ctx, spaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return space, elements, nil
+}
+
+// EnterSpace returns the space if userID may see and join it. Members always
+// may; everyone else depends on the space's visibility.
+func (s *spaceService) EnterSpace(ctx context.Context, userID, spaceID, password string) (*Space, error) {
+	return s.enterSpace(ctx, userID, spaceID, password, false)
+}
+
+// ReenterSpace returns the space if userID, who was in it before, may join
+// it again, as when their session moves to another node. The password they
+// gave then still counts; bans and invite-only membership are checked
+// again.
+func (s *spaceService) ReenterSpace(ctx context.Context, userID, spaceID string) (*Space, error) {
+	return s.enterSpace(ctx, userID, spaceID, "", true)
+}
+
+func (s *spaceService) enterSpace(ctx context.Context, userID, spaceID, password string, reentering bool) (*Space, error) {
+	if uuid.Validate(spaceID) != nil {
+		return nil, ErrInvalidSpaceID
+	}
+
+	space, err := s.repository.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if space == nil {
+		return nil, ErrSpaceNotFound
+	}
+
+	if err := s.checkNotBanned(ctx, spaceID, userID); err != nil {
+		return nil, err
+	}
+
+	switch space.Visibility {
+	case SpacePublic, SpaceUnlisted:
+		return space, nil
+	}
+
+	role, err := spaceRole(ctx, s.repository, space, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != "" {
+		return space, nil
+	}
+
+	if space.Visibility != SpacePassword {
+		return nil, ErrSpaceInviteOnly
+	}
+
+	if reentering {
+		return space, nil
+	}
+
+	if password == "" {
+		return nil, ErrSpacePasswordRequired
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(space.PasswordHash), []byte(password)) != nil {
+		return nil, ErrSpaceWrongPassword
+	}
+
+	return space, nil
+}
+
+func (s *spaceService) SetSpaceVisibility(ctx context.Context, userID, spaceID, visibility, password string) error {
+	if uuid.Validate(spaceID) != nil {
+		return ErrInvalidSpaceID
+	}
+
+	var passwordHash string
+	switch visibility {
+	case SpacePublic, SpaceUnlisted, SpaceInviteOnly:
+	case SpacePassword:
+		if password == "" {
+			return ErrSpacePasswordRequired
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		passwordHash = string(hash)
+	default:
+		return ErrInvalidSpaceVisibility
+	}
+
+	if _, err := s.ownedSpace(ctx, userID, spaceID); err != nil {
+		return err
+	}
+
+	updated, err := s.repository.SetVisibility(ctx, spaceID, visibility, passwordHash)
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return ErrSpaceNotFound
+	}
+
+	s.listener.VisibilityChanged(spaceID)
+	return nil
+}
+
+// SetSpaceCapacity sets the occupancy limit; 0 goes back to the default for
+// the map size.
+func (s *spaceService) SetSpaceCapacity(ctx context.Context, userID, spaceID string, capacity int) error {
+	if uuid.Validate(spaceID) != nil {
+		return ErrInvalidSpaceID
+	}
+
+	if capacity < 0 {
+		return ErrInvalidSpaceCapacity
+	}
+
+	space, err := s.ownedSpace(ctx, userID, spaceID)
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.repository.SetCapacity(ctx, spaceID, capacity)
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return ErrSpaceNotFound
+	}
+
+	space.Capacity = capacity
+	s.listener.CapacityChanged(spaceID, space.MaxOccupancy())
+	return nil
+}
+
+// MemberRole returns userID's role in the space, or "" if they are not a
+// member.
+func (s *spaceService) MemberRole(ctx context.Context, spaceID, userID string) (string, error) {
+	space, err := s.repository.GetByID(ctx, spaceID)
+	if err != nil {
+		return "", err
+	}
+
+	if space == nil {
+		return "", ErrSpaceNotFound
+	}
+
+	return spaceRole(ctx, s.repository, space, userID)
+}
+
+func (s *spaceService) ListSpaceMembers(ctx context.Context, userID, spaceID string) ([]SpaceMember, error) {
+	if _, err := moderatedSpace(ctx, s.repository, userID, spaceID); err != nil {
+		return nil, err
+	}
+
+	return s.repository.ListMembers(ctx, spaceID)
+}
+
+// SetSpaceMemberRole adds memberID to the space or changes their role. Only
+// the owner manages roles.
+func (s *spaceService) SetSpaceMemberRole(
+	ctx context.Context,
+	userID string,
+	spaceID string,
+	memberID string,
+	role string,
+) (*SpaceMember, error) {
+
+	if uuid.Validate(spaceID) != nil {
+		return nil, ErrInvalidSpaceID
+	}
+
+	if uuid.Validate(memberID) != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	if role != SpaceRoleModerator && role != SpaceRoleMember {
+		return nil, ErrInvalidSpaceRole
+	}
+
+	space, err := s.ownedSpace(ctx, userID, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if memberID == space.CreatorID {
+		return nil, ErrSpaceOwnerRole
+	}
+
+	member, err := s.repository.UpsertMember(ctx, &SpaceMember{
+		SpaceID:   spaceID,
+		UserID:    memberID,
+		Role:      role,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.listener.MemberRoleChanged(spaceID, memberID, member.Role)
+	return member, nil
+}
+
+// RemoveSpaceMember lets the owner remove anyone, moderators remove plain
+// members, and every member leave on their own.
+func (s *spaceService) RemoveSpaceMember(ctx context.Context, userID, spaceID, memberID string) error {
+	if uuid.Validate(spaceID) != nil {
+		return ErrInvalidSpaceID
+	}
+
+	if uuid.Validate(memberID) != nil {
+		return ErrSpaceMemberNotFound
+	}
+
+	space, err := s.repository.GetByID(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+
+	if space == nil {
+		return ErrSpaceNotFound
+	}
+
+	if memberID == space.CreatorID {
+		return ErrSpaceOwnerRole
+	}
+
+	if memberID != userID {
+		role, err := spaceRole(ctx, s.repository, space, userID)
+		if err != nil {
+			return err
+		}
+
+		member, err := s.repository.GetMember(ctx, spaceID, memberID)
+		if err != nil {
+			return err
+		}
+
+		if member == nil {
+			return ErrSpaceMemberNotFound
+		}
+
+		allowed := role == SpaceRoleOwner ||
+			role == SpaceRoleModerator && member.Role == SpaceRoleMember
+		if !allowed {
+			return ErrSpaceForbidden
+		}
+	}
+
+	removed, err := s.repository.RemoveMember(ctx, spaceID, memberID)
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		return ErrSpaceMemberNotFound
+	}
+
+	s.listener.MemberRoleChanged(spaceID, memberID, "")
+	s.listener.MemberRemoved(spaceID, memberID)
+	return nil
+}
+
+func (s *spaceService) CreateSpaceInvitation(
+	ctx context.Context,
+	userID string,
+	spaceID string,
+	ttl time.Duration,
+	maxUses int,
+) (*SpaceInvitation, error) {
+
+	if ttl < 0 || maxUses < 0 {
+		return nil, ErrInvalidSpaceInvitation
+	}
+
+	if _, err := moderatedSpace(ctx, s.repository, userID, spaceID); err != nil {
+		return nil, err
+	}
+
+	code, err := newSpaceInvitationCode()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	inv := &SpaceInvitation{
+		ID:        uuid.NewString(),
+		SpaceID:   spaceID,
+		Code:      code,
+		CreatedBy: userID,
+		MaxUses:   maxUses,
+		CreatedAt: now,
+	}
+	if ttl > 0 {
+		inv.ExpiresAt = now.Add(ttl)
+	}
+
+	if err := s.repository.CreateInvitation(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+func (s *spaceService) ListSpaceInvitations(ctx context.Context, userID, spaceID string) ([]SpaceInvitation, error) {
+	if _, err := moderatedSpace(ctx, s.repository, userID, spaceID); err != nil {
+		return nil, err
+	}
+
+	return s.repository.ListInvitations(ctx, spaceID)
+}
+
+func (s *spaceService) RevokeSpaceInvitation(ctx context.Context, userID, spaceID, invitationID string) error {
+	if _, err := moderatedSpace(ctx, s.repository, userID, spaceID); err != nil {
+		return err
+	}
+
+	if uuid.Validate(invitationID) != nil {
+		return ErrSpaceInvitationInvalid
+	}
+
+	deleted, err := s.repository.DeleteInvitation(ctx, spaceID, invitationID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrSpaceInvitationInvalid
+	}
+
+	return nil
+}
+
+// AcceptSpaceInvitation makes userID a member of the invitation's space. An
+// existing member keeps their role, though the use is still counted. A
+// banned user is turned away without using up the invitation.
+func (s *spaceService) AcceptSpaceInvitation(ctx context.Context, userID, code string) (*Space, error) {
+	inv, err := s.repository.GetInvitationByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if inv == nil {
+		return nil, ErrSpaceInvitationInvalid
+	}
+
+	if err := s.checkNotBanned(ctx, inv.SpaceID, userID); err != nil {
+		return nil, err
+	}
+
+	space, err := s.repository.GetByID(ctx, inv.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if space == nil {
+		return nil, ErrSpaceNotFound
+	}
+
+	inv, err = s.repository.UseInvitation(ctx, code, &SpaceMember{
+		UserID:    userID,
+		Role:      SpaceRoleMember,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if inv == nil {
+		return nil, ErrSpaceInvitationInvalid
+	}
+
+	return space, nil
+}
+
+// checkNotBanned returns ErrSpaceBanned if userID has an active ban in the
+// space.
+func (s *spaceService) checkNotBanned(ctx context.Context, spaceID, userID string) error {
+	sanctions, err := s.repository.ListActiveUserSanctions(ctx, spaceID, userID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	for _, sanction := range sanctions {
+		if sanction.Kind == SanctionBan {
+			return ErrSpaceBanned
+		}
+	}
+
+	return nil
+}
+
+// spaceRole returns userID's role in space, or "" if they are not a member.
+func spaceRole(ctx context.Context, r SpaceRepository, space *Space, userID string) (string, error) {
+	if space.CreatorID == userID {
+		return SpaceRoleOwner, nil
+	}
+
+	member, err := r.GetMember(ctx, space.ID, userID)
+	if err != nil || member == nil {
+		return "", err
+	}
+
+	return member.Role, nil
+}
+
+// moderatedSpace loads a live space and checks that userID owns or
+// moderates it.
+func moderatedSpace(ctx context.Context, r SpaceRepository, userID, spaceID string) (*Space, error) {
+	if uuid.Validate(spaceID) != nil {
+		return nil, ErrInvalidSpaceID
+	}
+
+	space, err := r.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if space == nil {
+		return nil, ErrSpaceNotFound
+	}
+
+	role, err := spaceRole(ctx, r, space, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != SpaceRoleOwner && role != SpaceRoleModerator {
+		return nil, ErrSpaceForbidden
+	}
+
+	return space, nil
+}
+
+func newSpaceInvitationCode() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// SpaceRateLimit overrides how fast a real-time message may be sent in a
+// space: Burst at once, refilling at Rate a second. It applies to users with
+// Role in the space or, when Role is empty, to everyone without a limit of
+// their own role.
+type SpaceRateLimit struct {
+	Message string  `json:"message"`
+	Role    string  `json:"role,omitempty"`
+	Rate    float64 `json:"rate"`
+	Burst   int     `json:"burst"`
+}
+
+// rateLimitedMessages are the real-time messages a space may limit. Joining
+// and resuming happen before a space is known, so only the server limits
+// them.
+var rateLimitedMessages = []string{
+	"move", "move-to", "chat", "emote", "direct-message", "kick", "mute", "ban",
+}
+
+const (
+	maxRateLimitRate  = 1000
+	maxRateLimitBurst = 10000
+)
+
+// SetSpaceRateLimits replaces the space's rate limit overrides; an empty
+// list goes back to the server's defaults.
+func (s *spaceService) SetSpaceRateLimits(ctx context.Context, userID, spaceID string, limits []SpaceRateLimit) error {
+	if uuid.Validate(spaceID) != nil {
+		return ErrInvalidSpaceID
+	}
+
+	if !validRateLimits(limits) {
+		return ErrInvalidSpaceRateLimits
+	}
+
+	if _, err := s.ownedSpace(ctx, userID, spaceID); err != nil {
+		return err
+	}
+
+	updated, err := s.repository.SetRateLimits(ctx, spaceID, limits)
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return ErrSpaceNotFound
+	}
+
+	s.listener.RateLimitsChanged(spaceID, limits)
+	return nil
+}
+
+// validRateLimits reports whether every limit is for a known message and
+// role, within bounds, and the only one for its message and role.
+func validRateLimits(limits []SpaceRateLimit) bool {
+	type key struct{ message, role string }
+	seen := make(map[key]bool, len(limits))
+
+	for _, l := range limits {
+		if !slices.Contains(rateLimitedMessages, l.Message) {
+			return false
+		}
+		switch l.Role {
+		case "", SpaceRoleOwner, SpaceRoleModerator, SpaceRoleMember:
+		default:
+			return false
+		}
+		if l.Rate <= 0 || l.Rate > maxRateLimitRate || l.Burst < 1 || l.Burst > maxRateLimitBurst {
+			return false
+		}
+
+		k := key{l.Message, l.Role}
+		if seen[k] {
+			return false
+		}
+		seen[k] = true
+	}
+	return true
+}
@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SpaceService interface {
+	DeleteSpace(ctx context.Context, userID, spaceID string) error
+	ListDeletedSpaces(ctx context.Context, userID string) ([]Space, error)
+	RestoreSpace(ctx context.Context, userID, spaceID string) error
+
+	AddSpaceElement(ctx context.Context, userID, spaceID, elementID string, x, y int) (*SpaceElement, error)
+	DeleteSpaceElement(ctx context.Context, userID, id string) error
+	ListDeletedSpaceElements(ctx context.Context, userID, spaceID string) ([]SpaceElement, error)
+	RestoreSpaceElement(ctx context.Context, userID, id string) error
+
+	ListSpaceVersions(ctx context.Context, userID, spaceID string) ([]SpaceVersionChange, error)
+	DiffSpaceVersions(ctx context.Context, userID, spaceID string, from, to int) (*LayoutDiff, error)
+	RollbackSpace(ctx context.Context, userID, spaceID string, version int) (*SpaceVersion, error)
+
+	GetSpace(ctx context.Context, userID, spaceID, password string) (*Space, []SpaceElement, error)
+	EnterSpace(ctx context.Context, userID, spaceID, password string) (*Space, error)
+	ReenterSpace(ctx context.Context, userID, spaceID string) (*Space, error)
+	SetSpaceVisibility(ctx context.Context, userID, spaceID, visibility, password string) error
+	SetSpaceCapacity(ctx context.Context, userID, spaceID string, capacity int) error
+	SetSpaceRateLimits(ctx context.Context, userID, spaceID string, limits []SpaceRateLimit) error
+	MemberRole(ctx context.Context, spaceID, userID string) (string, error)
+
+	ListSpaceMembers(ctx context.Context, userID, spaceID string) ([]SpaceMember, error)
+	SetSpaceMemberRole(ctx context.Context, userID, spaceID, memberID, role string) (*SpaceMember, error)
+	RemoveSpaceMember(ctx context.Context, userID, spaceID, memberID string) error
+
+	CreateSpaceInvitation(ctx context.Context, userID, spaceID string, ttl time.Duration, maxUses int) (*SpaceInvitation, error)
+	ListSpaceInvitations(ctx context.Context, userID, spaceID string) ([]SpaceInvitation, error)
+	RevokeSpaceInvitation(ctx context.Context, userID, spaceID, invitationID string) error
+	AcceptSpaceInvitation(ctx context.Context, userID, code string) (*Space, error)
+}
+
+type SpaceRepository interface {
+	GetByID(ctx context.Context, id string) (*Space, error)
+	Create(ctx context.Context, space *Space) error
+	GetDeleted(ctx context.Context, id string) (*Space, error)
+	SoftDelete(ctx context.Context, id string, at time.Time) (bool, error)
+	Restore(ctx context.Context, id string) (bool, error)
+	ListDeleted(ctx context.Context, creatorID string) ([]Space, error)
+
+	GetElement(ctx context.Context, id string) (*SpaceElement, error)
+	GetDeletedElement(ctx context.Context, id string) (*SpaceElement, error)
+	SoftDeleteElement(ctx context.Context, id string, at time.Time) (bool, error)
+	RestoreElement(ctx context.Context, id string) (bool, error)
+	ListDeletedElements(ctx context.Context, spaceID string) ([]SpaceElement, error)
+	ListElements(ctx context.Context, spaceID string) ([]SpaceElement, error)
+	CreateElement(ctx context.Context, el *SpaceElement) error
+	UpsertElement(ctx context.Context, el *SpaceElement) (bool, error)
+	GetObstacle(ctx context.Context, id string) (*Obstacle, error)
+	ListObstacles(ctx context.Context, spaceID string) ([]Obstacle, error)
+
+	SetVisibility(ctx context.Context, id, visibility, passwordHash string) (bool, error)
+	SetCapacity(ctx context.Context, id string, capacity int) (bool, error)
+	SetRateLimits(ctx context.Context, id string, limits []SpaceRateLimit) (bool, error)
+	GetMember(ctx context.Context, spaceID, userID string) (*SpaceMember, error)
+	ListMembers(ctx context.Context, spaceID string) ([]SpaceMember, error)
+	AddMember(ctx context.Context, member *SpaceMember) error
+	UpsertMember(ctx context.Context, member *SpaceMember) (*SpaceMember, error)
+	RemoveMember(ctx context.Context, spaceID, userID string) (bool, error)
+
+	CreateInvitation(ctx context.Context, inv *SpaceInvitation) error
+	ListInvitations(ctx context.Context, spaceID string) ([]SpaceInvitation, error)
+	DeleteInvitation(ctx context.Context, spaceID, id string) (bool, error)
+	GetInvitationByCode(ctx context.Context, code string) (*SpaceInvitation, error)
+	UseInvitation(ctx context.Context, code string, member *SpaceMember) (*SpaceInvitation, error)
+
+	UpsertSanction(ctx context.Context, sanction *SpaceSanction) error
+	DeleteSanction(ctx context.Context, spaceID, userID, kind string) (bool, error)
+	ListActiveSanctions(ctx context.Context, spaceID string, at time.Time) ([]SpaceSanction, error)
+	ListActiveUserSanctions(ctx context.Context, spaceID, userID string, at time.Time) ([]SpaceSanction, error)
+
+	EditLayout(ctx context.Context, spaceID string, fn func(repo SpaceRepository) error) error
+	CreateVersion(ctx context.Context, v *SpaceVersion) error
+	GetVersion(ctx context.Context, spaceID string, version int) (*SpaceVersion, error)
+	GetLatestVersion(ctx context.Context, spaceID string) (*SpaceVersion, error)
+	ListVersions(ctx context.Context, spaceID string, limit int) ([]SpaceVersion, error)
+}
+
+type Space struct {
+	ID        string
+	Name      string
+	Width     int
+	Height    int
+	Thumbnail string
+	CreatorID string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt time.Time
+
+	Visibility   string
+	PasswordHash string
+
+	// Capacity is the most users allowed in the space at once; 0 derives it
+	// from the map size.
+	Capacity int
+
+	// RateLimits override how fast real-time messages may be sent in the
+	// space.
+	RateLimits []SpaceRateLimit
+}
+
+// tilesPerOccupant is how much of the map each user gets when a space has no
+// explicit capacity.
+const tilesPerOccupant = 16
+
+// MaxOccupancy is how many users may be in the space at once.
+func (s *Space) MaxOccupancy() int {
+	if s.Capacity > 0 {
+		return s.Capacity
+	}
+	return max(1, s.Width*s.Height/tilesPerOccupant)
+}
+
+// SpaceElement is a catalog element placed at a position in a space.
+type SpaceElement struct {
+	ID        string
+	SpaceID   string
+	ElementID string
+	X         int
+	Y         int
+	CreatedAt time.Time
+	DeletedAt time.Time
+}
+
+// Obstacle is the footprint of a static element placed in a space, which
+// users cannot walk through.
+type Obstacle struct {
+	ID      string
+	SpaceID string
+	X       int
+	Y       int
+	Width   int
+	Height  int
+}
+
+// LayoutListener is told when the elements of a space change so live rooms
+// can update their collision grid and clients. ObstacleRemoved only relies
+// on the obstacle's ID and SpaceID.
+type LayoutListener interface {
+	ObstacleAdded(o Obstacle)
+	ObstacleRemoved(o Obstacle)
+	ElementsChanged(spaceID string, version int, diff *LayoutDiff)
+}
+
+// SpaceListener is also told when someone may have lost access to a space,
+// so that they can be taken out of its live room, and when the rules its
+// connected users are held to change.
+type SpaceListener interface {
+	LayoutListener
+	MemberRoleChanged(spaceID, userID, role string)
+	MemberRemoved(spaceID, userID string)
+	VisibilityChanged(spaceID string)
+	RateLimitsChanged(spaceID string, limits []SpaceRateLimit)
+	CapacityChanged(spaceID string, capacity int)
+}
+
+type spaceService struct {
+	repository SpaceRepository
+	listener   SpaceListener
+}
+
+func NewSpaceService(r SpaceRepository, l SpaceListener) SpaceService {
+	return &spaceService{
+		repository: r,
+		listener:   l,
+	}
+}
+
+var (
+	ErrInvalidSpaceID       = errors.New("invalid space id")
+	ErrSpaceNotFound        = errors.New("space not found")
+	ErrSpaceForbidden       = errors.New("space belongs to another user")
+	ErrSpaceElementNotFound = errors.New("space element not found")
+	ErrInvalidElementPlace  = errors.New("element must be placed inside the space")
+	ErrSpaceDeleted         = errors.New("space is deleted")
+)
+
+func (s *spaceService) DeleteSpace(ctx context.Context, userID, spaceID string) error {
+	if uuid.Validate(spaceID) != nil {
+		return ErrInvalidSpaceID
+	}
+
+	space, err := s.repository.GetByID(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+
+	if space == nil {
+		return ErrSpaceNotFound
+	}
+
+	if space.CreatorID != userID {
+		return ErrSpaceForbidden
+	}
+
+	deleted, err := s.repository.SoftDelete(ctx, spaceID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrSpaceNotFound
+	}
+
+	// Nobody may be in a deleted space, so everyone connected is sent away.
+	s.listener.VisibilityChanged(spaceID)
+	return nil
+}
+
+func (s *spaceService) ListDeletedSpaces(ctx context.Context, userID string) ([]Space, error) {
+	return s.repository.ListDeleted(ctx, userID)
+}
+
+func (s *spaceService) RestoreSpace(ctx context.Context, userID, spaceID string) error {
+	if uuid.Validate(spaceID) != nil {
+		return ErrInvalidSpaceID
+	}
+
+	space, err := s.repository.GetDeleted(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+
+	if space == nil {
+		return ErrSpaceNotFound
+	}
+
+	if space.CreatorID != userID {
+		return ErrSpaceForbidden
+	}
+
+	restored, err := s.repository.Restore(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+
+	if !restored {
+		return ErrSpaceNotFound
+	}
+
+	return nil
+}
+
+func (s *spaceService) AddSpaceElement(
+	ctx context.Context,
+	userID, spaceID, elementID string,
+	x, y int,
+) (*SpaceElement, error) {
+	if uuid.Validate(spaceID) != nil {
+		return nil, ErrInvalidSpaceID
+	}
+
+	if uuid.Validate(elementID) != nil {
+		return nil, ErrElementNotFound
+	}
+
+	space, err := s.ownedSpace(ctx, userID, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if x < 0 || y < 0 || x >= space.Width || y >= space.Height {
+		return nil, ErrInvalidElementPlace
+	}
+
+	el := &SpaceElement{
+		ID:        uuid.NewString(),
+		SpaceID:   spaceID,
+		ElementID: elementID,
+		X:         x,
+		Y:         y,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	_, err = s.editLayout(ctx, spaceID, userID, LayoutAddElement, 0, func(repo SpaceRepository) error {
+		return repo.CreateElement(ctx, el)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return el, nil
+}
+
+func (s *spaceService) DeleteSpaceElement(ctx context.Context, userID, id string) error {
+	if uuid.Validate(id) != nil {
+		return ErrSpaceElementNotFound
+	}
+
+	el, err := s.repository.GetElement(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if el == nil {
+		return ErrSpaceElementNotFound
+	}
+
+	if _, err := s.ownedSpace(ctx, userID, el.SpaceID); err != nil {
+		return err
+	}
+
+	_, err = s.editLayout(ctx, el.SpaceID, userID, LayoutDeleteElement, 0, func(repo SpaceRepository) error {
+		deleted, err := repo.SoftDeleteElement(ctx, id, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		if !deleted {
+			return ErrSpaceElementNotFound
+		}
+
+		return nil
+	})
+	return err
+}
+
+func (s *spaceService) ListDeletedSpaceElements(ctx context.Context, userID, spaceID string) ([]SpaceElement, error) {
+	if uuid.Validate(spaceID) != nil {
+		return nil, ErrInvalidSpaceID
+	}
+
+	if _, err := s.ownedSpace(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+
+	return s.repository.ListDeletedElements(ctx, spaceID)
+}
+
+func (s *spaceService) RestoreSpaceElement(ctx context.Context, userID, id string) error {
+	if uuid.Validate(id) != nil {
+		return ErrSpaceElementNotFound
+	}
+
+	el, err := s.repository.GetDeletedElement(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if el == nil {
+		return ErrSpaceElementNotFound
+	}
+
+	// Elements of a space in the trash come back with the space.
+	if _, err := s.ownedSpace(ctx, userID, el.SpaceID); err != nil {
+		if err == ErrSpaceNotFound {
+			return ErrSpaceDeleted
+		}
+		return err
+	}
+
+	_, err = s.editLayout(ctx, el.SpaceID, userID, LayoutRestoreElement, 0, func(repo SpaceRepository) error {
+		restored, err := repo.RestoreElement(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if !restored {
+			return ErrSpaceElementNotFound
+		}
+
+		return nil
+	})
+	return err
+}
+
+// ownedSpace loads a live space and checks that userID created it.
+func (s *spaceService) ownedSpace(ctx context.Context, userID, spaceID string) (*Space, error) {
+	space, err := s.repository.GetByID(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if space == nil {
+		return nil, ErrSpaceNotFound
+	}
+
+	if space.CreatorID != userID {
+		return nil, ErrSpaceForbidden
+	}
+
+	return space, nil
+}
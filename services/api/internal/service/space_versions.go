@@ -0,0 +1,381 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded with each version of a space's layout.
+const (
+	LayoutInitial        = "initial"
+	LayoutAddElement     = "add-element"
+	LayoutDeleteElement  = "delete-element"
+	LayoutRestoreElement = "restore-element"
+	LayoutRollback       = "rollback"
+)
+
+// maxListedVersions is how much history ListSpaceVersions returns.
+const maxListedVersions = 50
+
+// LayoutElement is a placed element as recorded in a version.
+type LayoutElement struct {
+	ID        string `json:"id"`
+	ElementID string `json:"elementId"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+}
+
+// SpaceVersion is a snapshot of a space's element layout taken after one
+// batch of changes. RestoredVersion is set for rollbacks.
+type SpaceVersion struct {
+	SpaceID         string
+	Version         int
+	AuthorID        string
+	Action          string
+	RestoredVersion int
+	Elements        []LayoutElement
+	CreatedAt       time.Time
+}
+
+// SpaceVersionChange is a version together with what it changed from the
+// one before it.
+type SpaceVersionChange struct {
+	SpaceVersion
+	Diff *LayoutDiff
+}
+
+// LayoutDiff is what changed between two layouts. Moved holds the new
+// positions.
+type LayoutDiff struct {
+	Added   []LayoutElement
+	Removed []LayoutElement
+	Moved   []LayoutElement
+}
+
+func (d *LayoutDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0
+}
+
+var (
+	ErrSpaceVersionNotFound = errors.New("space version not found")
+	ErrSpaceVersionConflict = errors.New("space version already exists")
+)
+
+// ListSpaceVersions returns the latest versions of a space, newest first,
+// each with the changes it made.
+func (s *spaceService) ListSpaceVersions(ctx context.Context, userID, spaceID string) ([]SpaceVersionChange, error) {
+	if uuid.Validate(spaceID) != nil {
+		return nil, ErrInvalidSpaceID
+	}
+
+	if _, err := s.ownedSpace(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+
+	// One extra version is loaded to diff the oldest listed one against.
+	versions, err := s.repository.ListVersions(ctx, spaceID, maxListedVersions+1)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]SpaceVersionChange, 0, min(len(versions), maxListedVersions))
+	for i := 0; i < len(versions) && i < maxListedVersions; i++ {
+		var previous []LayoutElement
+		if i+1 < len(versions) {
+			previous = versions[i+1].Elements
+		}
+
+		changes = append(changes, SpaceVersionChange{
+			SpaceVersion: versions[i],
+			Diff:         diffLayouts(previous, versions[i].Elements),
+		})
+	}
+
+	return changes, nil
+}
+
+// DiffSpaceVersions returns the changes that turn version from into version
+// to.
+func (s *spaceService) DiffSpaceVersions(ctx context.Context, userID, spaceID string, from, to int) (*LayoutDiff, error) {
+	if uuid.Validate(spaceID) != nil {
+		return nil, ErrInvalidSpaceID
+	}
+
+	if _, err := s.ownedSpace(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+
+	a, err := s.version(ctx, spaceID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.version(ctx, spaceID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffLayouts(a.Elements, b.Elements), nil
+}
+
+// RollbackSpace puts the elements of a space back where they were at an
+// earlier version and records the result as a new version. Elements whose
+// catalog element has been purged since cannot come back, so the new
+// version lacks them.
+func (s *spaceService) RollbackSpace(ctx context.Context, userID, spaceID string, version int) (*SpaceVersion, error) {
+	if uuid.Validate(spaceID) != nil {
+		return nil, ErrInvalidSpaceID
+	}
+
+	if _, err := s.ownedSpace(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+
+	target, err := s.version(ctx, spaceID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.editLayout(ctx, spaceID, userID, LayoutRollback, version, func(repo SpaceRepository) error {
+		current, err := layout(ctx, repo, spaceID)
+		if err != nil {
+			return err
+		}
+
+		return applyRollback(ctx, repo, spaceID, diffLayouts(current, target.Elements))
+	})
+}
+
+func applyRollback(ctx context.Context, repo SpaceRepository, spaceID string, diff *LayoutDiff) error {
+	now := time.Now().UTC()
+
+	for _, el := range diff.Removed {
+		if _, err := repo.SoftDeleteElement(ctx, el.ID, now); err != nil {
+			return err
+		}
+	}
+
+	// Added elements may be in the trash, purged or live elsewhere in the
+	// history; upserting by id covers all three. Those whose catalog
+	// element has since been purged are left out.
+	for _, list := range [][]LayoutElement{diff.Added, diff.Moved} {
+		for _, el := range list {
+			_, err := repo.UpsertElement(ctx, &SpaceElement{
+				ID:        el.ID,
+				SpaceID:   spaceID,
+				ElementID: el.ElementID,
+				X:         el.X,
+				Y:         el.Y,
+				CreatedAt: now,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *spaceService) version(ctx context.Context, spaceID string, version int) (*SpaceVersion, error) {
+	v, err := s.repository.GetVersion(ctx, spaceID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return nil, ErrSpaceVersionNotFound
+	}
+
+	return v, nil
+}
+
+// layout returns the live elements of a space as they are recorded in
+// versions.
+func layout(ctx context.Context, repo SpaceRepository, spaceID string) ([]LayoutElement, error) {
+	elements, err := repo.ListElements(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := make([]LayoutElement, 0, len(elements))
+	for _, el := range elements {
+		layout = append(layout, LayoutElement{
+			ID:        el.ID,
+			ElementID: el.ElementID,
+			X:         el.X,
+			Y:         el.Y,
+		})
+	}
+
+	return layout, nil
+}
+
+// editLayout applies edit to the layout of a space and records the result
+// as a version by userID, in one transaction that concurrent edits of the
+// space wait for, then tells the listener what changed. Nothing is recorded
+// when the layout is unchanged, and the latest version is returned.
+func (s *spaceService) editLayout(
+	ctx context.Context,
+	spaceID, userID, action string,
+	restored int,
+	edit func(repo SpaceRepository) error,
+) (*SpaceVersion, error) {
+	var (
+		recorded *SpaceVersion
+		diff     *LayoutDiff
+	)
+
+	err := s.repository.EditLayout(ctx, spaceID, func(repo SpaceRepository) error {
+		if err := ensureBaseline(ctx, repo, spaceID); err != nil {
+			return err
+		}
+
+		if err := edit(repo); err != nil {
+			return err
+		}
+
+		var err error
+		recorded, diff, err = recordVersion(ctx, repo, spaceID, userID, action, restored)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !diff.Empty() {
+		s.layoutChanged(ctx, spaceID, recorded.Version, diff)
+	}
+
+	return recorded, nil
+}
+
+// ensureBaseline records the layout of a space that has no history yet, so
+// the first change made to it can be undone. It must run before the change.
+func ensureBaseline(ctx context.Context, repo SpaceRepository, spaceID string) error {
+	latest, err := repo.GetLatestVersion(ctx, spaceID)
+	if err != nil || latest != nil {
+		return err
+	}
+
+	current, err := layout(ctx, repo, spaceID)
+	if err != nil {
+		return err
+	}
+
+	return repo.CreateVersion(ctx, &SpaceVersion{
+		SpaceID:   spaceID,
+		Version:   1,
+		Action:    LayoutInitial,
+		Elements:  current,
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+// recordVersion snapshots the layout of a space after a batch of changes by
+// userID, returning the new version and what it changed from the one
+// before. When the layout is unchanged it returns the latest version and an
+// empty diff instead.
+func recordVersion(
+	ctx context.Context,
+	repo SpaceRepository,
+	spaceID, userID, action string,
+	restored int,
+) (*SpaceVersion, *LayoutDiff, error) {
+	latest, err := repo.GetLatestVersion(ctx, spaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var previous []LayoutElement
+	next := 1
+	if latest != nil {
+		previous = latest.Elements
+		next = latest.Version + 1
+	}
+
+	current, err := layout(ctx, repo, spaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	diff := diffLayouts(previous, current)
+	if diff.Empty() {
+		return latest, diff, nil
+	}
+
+	v := &SpaceVersion{
+		SpaceID:         spaceID,
+		Version:         next,
+		AuthorID:        userID,
+		Action:          action,
+		RestoredVersion: restored,
+		Elements:        current,
+		CreatedAt:       time.Now().UTC(),
+	}
+
+	if err := repo.CreateVersion(ctx, v); err != nil {
+		return nil, nil, err
+	}
+
+	return v, diff, nil
+}
+
+// layoutChanged passes a recorded change on to the listener, with the
+// obstacles it added or removed.
+func (s *spaceService) layoutChanged(ctx context.Context, spaceID string, version int, diff *LayoutDiff) {
+	for _, el := range slices.Concat(diff.Removed, diff.Moved) {
+		s.listener.ObstacleRemoved(Obstacle{ID: el.ID, SpaceID: spaceID})
+	}
+
+	for _, el := range slices.Concat(diff.Added, diff.Moved) {
+		o, err := s.repository.GetObstacle(ctx, el.ID)
+		if err != nil {
+			log.Println("load obstacle:", err)
+			continue
+		}
+		if o != nil {
+			s.listener.ObstacleAdded(*o)
+		}
+	}
+
+	s.listener.ElementsChanged(spaceID, version, diff)
+}
+
+// diffLayouts returns the changes that turn layout a into layout b.
+func diffLayouts(a, b []LayoutElement) *LayoutDiff {
+	before := make(map[string]LayoutElement, len(a))
+	for _, el := range a {
+		before[el.ID] = el
+	}
+
+	diff := &LayoutDiff{
+		Added:   []LayoutElement{},
+		Removed: []LayoutElement{},
+		Moved:   []LayoutElement{},
+	}
+
+	for _, el := range b {
+		old, ok := before[el.ID]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, el)
+		case old.X != el.X || old.Y != el.Y:
+			diff.Moved = append(diff.Moved, el)
+		}
+		delete(before, el.ID)
+	}
+
+	for _, el := range a {
+		if _, ok := before[el.ID]; ok {
+			diff.Removed = append(diff.Removed, el)
+		}
+	}
+
+	return diff
+}
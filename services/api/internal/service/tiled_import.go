@@ -0,0 +1,362 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"time"
+
+	"github.com/vaxxnsh/metaverse/api/internal/tiled"
+)
+
+// DefaultCollisionLayer is the layer of a Tiled map whose tiles block
+// movement unless the import names another.
+const DefaultCollisionLayer = "collision"
+
+const maxTiledPlacements = 20000
+
+// BundleConflictCollision reports collision tiles that could not be
+// imported, or a missing collision layer.
+const BundleConflictCollision = "collision"
+
+// TiledImportOptions tune how a Tiled map becomes a space. Name and
+// Thumbnail default to the map's name and thumbnail properties.
+// AssetBaseURL resolves the image paths of tilesets, which Tiled stores
+// relative to the map file.
+type TiledImportOptions struct {
+	Name           string
+	Thumbnail      string
+	AssetBaseURL   string
+	CollisionLayer string
+}
+
+// ImportTiledMap creates a space from a map saved by the Tiled editor, as
+// JSON or TMX. Tiles used by object layers and the collision layer become
+// catalog elements: tile objects are placed as elements, rectangles and
+// polygons become zones, and every tile of the collision layer is placed
+// as a static element. Other tile layers are artwork and are left to the
+// thumbnail.
+func (s *spaceBundleService) ImportTiledMap(ctx context.Context, userID string, file io.Reader, opts TiledImportOptions) (*SpaceImport, error) {
+	data, err := readBundleFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := tiled.Parse(data)
+	var mapErr *tiled.MapError
+	if errors.As(err, &mapErr) {
+		return nil, &SpaceBundleError{Message: mapErr.Message}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, conflicts, err := tiledBundle(m, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateSpaceBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	return s.importBundle(ctx, userID, bundle, conflicts)
+}
+
+// tiledConverter builds a bundle from a map, recording what it has to
+// leave out.
+type tiledConverter struct {
+	m         *tiled.Map
+	base      *url.URL
+	bundle    *SpaceBundle
+	elements  map[string]bool
+	conflicts []BundleConflict
+}
+
+func tiledBundle(m *tiled.Map, opts TiledImportOptions) (*SpaceBundle, []BundleConflict, error) {
+	c := &tiledConverter{
+		m:         m,
+		elements:  map[string]bool{},
+		conflicts: []BundleConflict{},
+	}
+
+	if opts.AssetBaseURL != "" {
+		base, err := url.Parse(opts.AssetBaseURL)
+		if err != nil || (base.Scheme != "http" && base.Scheme != "https") {
+			return nil, nil, &SpaceBundleError{Message: "asset base URL must be an http or https URL"}
+		}
+		c.base = base
+	}
+
+	for _, ts := range m.Tilesets {
+		if ts.Source != "" {
+			return nil, nil, &SpaceBundleError{Message: fmt.Sprintf("tileset %q is external; embed it in the map", ts.Source)}
+		}
+	}
+
+	name := firstNonEmpty(opts.Name, m.Properties.String("name"), "Tiled map")
+	collisionLayer := firstNonEmpty(opts.CollisionLayer, DefaultCollisionLayer)
+
+	c.bundle = &SpaceBundle{
+		Format:     SpaceBundleFormat,
+		Version:    SpaceBundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Space: BundleSpace{
+			ID:        "map",
+			Name:      name,
+			Width:     m.Width,
+			Height:    m.Height,
+			Thumbnail: firstNonEmpty(opts.Thumbnail, m.Properties.String("thumbnail")),
+		},
+		Elements:   []BundleElement{},
+		Placements: []LayoutElement{},
+		Zones:      []BundleZone{},
+		Portals:    []BundlePortal{},
+	}
+
+	foundCollision := false
+	m.Walk(func(l *tiled.Layer) {
+		collision := l.Name == collisionLayer
+		switch {
+		case l.Type == tiled.LayerObjects:
+			foundCollision = foundCollision || collision
+			c.objectLayer(l, collision)
+		case l.Type == tiled.LayerTiles && collision:
+			foundCollision = true
+			c.collisionLayer(l)
+		}
+	})
+
+	if !foundCollision {
+		c.conflict(BundleConflictCollision, collisionLayer, "map has no layer named %q, so nothing blocks movement", collisionLayer)
+	}
+
+	if len(c.bundle.Placements) > maxTiledPlacements {
+		return nil, nil, &SpaceBundleError{Message: fmt.Sprintf("map places more than %d elements", maxTiledPlacements)}
+	}
+
+	return c.bundle, c.conflicts, nil
+}
+
+func (c *tiledConverter) conflict(kind, id, format string, args ...any) {
+	c.conflicts = append(c.conflicts, BundleConflict{
+		Kind:    kind,
+		ID:      id,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// objectLayer places tile objects and turns shapes into zones. Objects of
+// the collision layer are placed as static elements.
+func (c *tiledConverter) objectLayer(l *tiled.Layer, collision bool) {
+	for _, o := range l.Objects {
+		id := fmt.Sprintf("object:%d", o.ID)
+
+		if o.GID != 0 {
+			ref, ok := c.m.Lookup(o.GID)
+			if !ok {
+				c.conflict(BundleConflictPlacement, id, "gid %d is not in any tileset", o.GID)
+				continue
+			}
+
+			elementID, ok := c.element(ref, collision || tiledStatic(ref))
+			if !ok {
+				c.conflict(BundleConflictPlacement, id, "tile %d of tileset %q has no image", ref.ID, ref.Tileset.Name)
+				continue
+			}
+
+			// Tile objects are anchored at their bottom-left corner.
+			c.bundle.Placements = append(c.bundle.Placements, LayoutElement{
+				ID:        id,
+				ElementID: elementID,
+				X:         c.tileX(o.X),
+				Y:         c.tileY(o.Y - o.Height),
+			})
+			continue
+		}
+
+		if collision {
+			c.conflict(BundleConflictCollision, id, "only tile objects can block movement")
+			continue
+		}
+
+		zone, ok := c.zone(l, o)
+		if !ok {
+			c.conflict(BundleConflictZone, id, "points, polylines and ellipses cannot become zones")
+			continue
+		}
+		zone.ID = id
+		c.bundle.Zones = append(c.bundle.Zones, zone)
+	}
+}
+
+// collisionLayer places every tile of the layer as a static element.
+func (c *tiledConverter) collisionLayer(l *tiled.Layer) {
+	for i, gid := range l.Data {
+		if gid == 0 {
+			continue
+		}
+
+		x, y := i%c.m.Width, i/c.m.Width
+		id := fmt.Sprintf("collision:%d,%d", x, y)
+
+		ref, ok := c.m.Lookup(gid)
+		if !ok {
+			c.conflict(BundleConflictCollision, id, "gid %d is not in any tileset", gid)
+			continue
+		}
+
+		elementID, ok := c.element(ref, true)
+		if !ok {
+			c.conflict(BundleConflictCollision, id, "tile %d of tileset %q has no image", ref.ID, ref.Tileset.Name)
+			continue
+		}
+
+		c.bundle.Placements = append(c.bundle.Placements, LayoutElement{
+			ID:        id,
+			ElementID: elementID,
+			X:         x,
+			Y:         y,
+		})
+	}
+}
+
+// element adds the catalog element for a tile to the bundle and returns its
+// bundle id. Atlas tiles point into the tileset image with a media
+// fragment. It reports false for tiles without an image.
+func (c *tiledConverter) element(ref tiled.TileRef, static bool) (string, bool) {
+	ts := ref.Tileset
+	tile := ref.Tile()
+
+	var image string
+	var width, height int
+	switch {
+	case tile.Image != "":
+		image = tile.Image
+		width, height = tile.ImageWidth, tile.ImageHeight
+	case ts.Image != "" && ts.Columns > 0:
+		col, row := ref.ID%ts.Columns, ref.ID/ts.Columns
+		x := ts.Margin + col*(ts.TileWidth+ts.Spacing)
+		y := ts.Margin + row*(ts.TileHeight+ts.Spacing)
+		image = fmt.Sprintf("%s#xywh=%d,%d,%d,%d", ts.Image, x, y, ts.TileWidth, ts.TileHeight)
+	default:
+		return "", false
+	}
+	if width == 0 || height == 0 {
+		width, height = ts.TileWidth, ts.TileHeight
+	}
+
+	id := fmt.Sprintf("tile:%d:%d", ts.FirstGID, ref.ID)
+	if static {
+		id += ":static"
+	}
+	if c.elements[id] {
+		return id, true
+	}
+
+	image = c.resolve(image)
+	e := BundleElement{
+		ID:       id,
+		ImageURL: image,
+		Width:    max(1, ceilDiv(width, c.m.TileWidth)),
+		Height:   max(1, ceilDiv(height, c.m.TileHeight)),
+		Static:   static,
+	}
+	e.Hash = ElementHash(e.ImageURL, e.Width, e.Height, e.Static)
+
+	c.elements[id] = true
+	c.bundle.Elements = append(c.bundle.Elements, e)
+	return id, true
+}
+
+// zone turns a rectangle or polygon into a zone, in tiles. Its chatScope,
+// audio and minRole properties become the zone's rules and the rest its
+// properties.
+func (c *tiledConverter) zone(l *tiled.Layer, o tiled.Object) (BundleZone, bool) {
+	var zone BundleZone
+
+	switch {
+	case o.Point || o.Ellipse || o.Polyline:
+		return zone, false
+	case len(o.Polygon) > 0:
+		zone.Shape = ZonePolygon
+		for _, p := range o.Polygon {
+			zone.Points = append(zone.Points, Point{X: c.tileX(o.X + p.X), Y: c.tileY(o.Y + p.Y)})
+		}
+	case o.Width > 0 && o.Height > 0:
+		zone.Shape = ZoneRect
+		zone.Points = []Point{
+			{X: c.tileX(o.X), Y: c.tileY(o.Y)},
+			{X: c.tileX(o.X + o.Width), Y: c.tileY(o.Y + o.Height)},
+		}
+	default:
+		return zone, false
+	}
+
+	zone.Name = firstNonEmpty(o.Name, l.Name)
+
+	props := map[string]any{}
+	for name, v := range o.Properties {
+		switch name {
+		case "chatScope":
+			zone.ChatScope = o.Properties.String(name)
+		case "audio":
+			zone.Audio = o.Properties.String(name)
+		case "minRole":
+			zone.MinRole = o.Properties.String(name)
+		default:
+			props[name] = v
+		}
+	}
+	zone.Properties, _ = json.Marshal(props)
+
+	return zone, true
+}
+
+func (c *tiledConverter) resolve(image string) string {
+	if c.base == nil {
+		return image
+	}
+
+	ref, err := url.Parse(image)
+	if err != nil || ref.IsAbs() {
+		return image
+	}
+
+	return c.base.ResolveReference(ref).String()
+}
+
+func (c *tiledConverter) tileX(px float64) int {
+	return int(math.Round(px / float64(c.m.TileWidth)))
+}
+
+func (c *tiledConverter) tileY(px float64) int {
+	return int(math.Round(px / float64(c.m.TileHeight)))
+}
+
+// tiledStatic reports whether a tile blocks movement when placed as an
+// object, from its own or its tileset's static property.
+func tiledStatic(ref tiled.TileRef) bool {
+	if static, ok := ref.Tile().Properties.Bool("static"); ok {
+		return static
+	}
+	static, _ := ref.Tileset.Properties.Bool("static")
+	return static
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -12,12 +12,18 @@ type Service interface {
 	CreateUser(ctx context.Context, email, name string) (*User, error)
 	GetUserByID(ctx context.Context, id string) (*User, error)
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	DeleteUser(ctx context.Context, id string) error
+	ListDeletedUsers(ctx context.Context) ([]User, error)
+	RestoreUser(ctx context.Context, id string) error
 }
 
 type UserRepository interface {
 	Create(ctx context.Context, user *User) error
 	GetByID(ctx context.Context, id string) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	SoftDelete(ctx context.Context, id string, at time.Time) (bool, error)
+	Restore(ctx context.Context, id string) (bool, error)
+	ListDeleted(ctx context.Context) ([]User, error)
 }
 
 type User struct {
@@ -26,6 +32,7 @@ type User struct {
 	Name      string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt time.Time
 }
 
 type userService struct {
@@ -111,3 +118,41 @@ func (s *userService) GetUserByEmail(ctx context.Context, email string) (*User,
 
 	return user, nil
 }
+
+func (s *userService) DeleteUser(ctx context.Context, id string) error {
+	if uuid.Validate(id) != nil {
+		return ErrUserNotFound
+	}
+
+	deleted, err := s.repository.SoftDelete(ctx, id, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (s *userService) ListDeletedUsers(ctx context.Context) ([]User, error) {
+	return s.repository.ListDeleted(ctx)
+}
+
+func (s *userService) RestoreUser(ctx context.Context, id string) error {
+	if uuid.Validate(id) != nil {
+		return ErrUserNotFound
+	}
+
+	restored, err := s.repository.Restore(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !restored {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ZoneService interface {
+	ListZones(ctx context.Context, userID, spaceID, password string) ([]Zone, error)
+	CreateZone(ctx context.Context, userID, spaceID string, zone *Zone) (*Zone, error)
+	UpdateZone(ctx context.Context, userID, spaceID, zoneID string, zone *Zone) (*Zone, error)
+	DeleteZone(ctx context.Context, userID, spaceID, zoneID string) error
+}
+
+type ZoneRepository interface {
+	Create(ctx context.Context, zone *Zone) error
+	Update(ctx context.Context, zone *Zone) (*Zone, error)
+	Delete(ctx context.Context, spaceID, id string) (bool, error)
+	ListBySpace(ctx context.Context, spaceID string) ([]Zone, error)
+}
+
+// ZoneListener is told when the zones of a space change so live rooms can
+// pick them up.
+type ZoneListener interface {
+	ZonesChanged(spaceID string)
+}
+
+const (
+	ZoneRect    = "rect"
+	ZonePolygon = "polygon"
+)
+
+const (
+	// ZoneChatSpace lets chat inside the zone reach the whole space.
+	ZoneChatSpace = "space"
+	// ZoneChatZone keeps chat between users inside the zone.
+	ZoneChatZone = "zone"
+)
+
+// Audio rules are enforced by clients, which receive them with zone events.
+const (
+	ZoneAudioOpen  = "open"
+	ZoneAudioZone  = "zone"
+	ZoneAudioMuted = "muted"
+)
+
+const (
+	maxZoneNameLength = 64
+	maxZonePoints     = 64
+)
+
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Zone is a named area of a space. Points are in tile coordinates: the
+// vertices of a polygon, or the top-left and exclusive bottom-right corners
+// of a rectangle.
+type Zone struct {
+	ID         string
+	SpaceID    string
+	Name       string
+	Shape      string
+	Points     []Point
+	Properties json.RawMessage
+	ChatScope  string
+	Audio      string
+
+	// MinRole is the space role needed to step into the zone; "" lets
+	// everyone in.
+	MinRole string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Contains reports whether the tile at x, y lies in the zone. Polygons test
+// the tile's center.
+func (z *Zone) Contains(x, y int) bool {
+	if z.Shape == ZoneRect {
+		return x >= z.Points[0].X && x < z.Points[1].X && y >= z.Points[0].Y && y < z.Points[1].Y
+	}
+
+	px, py := float64(x)+0.5, float64(y)+0.5
+	inside := false
+	for i, j := 0, len(z.Points)-1; i < len(z.Points); j, i = i, i+1 {
+		a, b := z.Points[i], z.Points[j]
+		ax, ay, bx, by := float64(a.X), float64(a.Y), float64(b.X), float64(b.Y)
+		if (ay > py) != (by > py) && px < (bx-ax)*(py-ay)/(by-ay)+ax {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Admits reports whether a user with the given space role may enter.
+func (z *Zone) Admits(role string) bool {
+	return spaceRoleRank(role) >= spaceRoleRank(z.MinRole)
+}
+
+type zoneService struct {
+	repository ZoneRepository
+	spaces     SpaceService
+	listener   ZoneListener
+}
+
+func NewZoneService(r ZoneRepository, spaces SpaceService, l ZoneListener) ZoneService {
+	return &zoneService{
+		repository: r,
+		spaces:     spaces,
+		listener:   l,
+	}
+}
+
+var (
+	ErrInvalidZone  = errors.New("invalid zone")
+	ErrZoneNotFound = errors.New("zone not found")
+)
+
+// ZoneValidationError explains why a zone was rejected.
+type ZoneValidationError struct {
+	Message string
+}
+
+func (e *ZoneValidationError) Error() string {
+	return ErrInvalidZone.Error() + ": " + e.Message
+}
+
+func (e *ZoneValidationError) Unwrap() error {
+	return ErrInvalidZone
+}
+
+// ListZones returns the zones of a space to anyone allowed into it.
+func (s *zoneService) ListZones(ctx context.Context, userID, spaceID, password string) ([]Zone, error) {
+	if _, err := s.spaces.EnterSpace(ctx, userID, spaceID, password); err != nil {
+		return nil, err
+	}
+
+	return s.repository.ListBySpace(ctx, spaceID)
+}
+
+func (s *zoneService) CreateZone(ctx context.Context, userID, spaceID string, zone *Zone) (*Zone, error) {
+	space, err := s.managedSpace(ctx, userID, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateZone(zone, space); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	zone.ID = uuid.NewString()
+	zone.SpaceID = spaceID
+	zone.CreatedAt = now
+	zone.UpdatedAt = now
+
+	if err := s.repository.Create(ctx, zone); err != nil {
+		return nil, err
+	}
+
+	s.listener.ZonesChanged(spaceID)
+
+	return zone, nil
+}
+
+func (s *zoneService) UpdateZone(ctx context.Context, userID, spaceID, zoneID string, zone *Zone) (*Zone, error) {
+	if uuid.Validate(zoneID) != nil {
+		return nil, ErrZoneNotFound
+	}
+
+	space, err := s.managedSpace(ctx, userID, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateZone(zone, space); err != nil {
+		return nil, err
+	}
+
+	zone.ID = zoneID
+	zone.SpaceID = spaceID
+	zone.UpdatedAt = time.Now().UTC()
+
+	updated, err := s.repository.Update(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	if updated == nil {
+		return nil, ErrZoneNotFound
+	}
+
+	s.listener.ZonesChanged(spaceID)
+
+	return updated, nil
+}
+
+func (s *zoneService) DeleteZone(ctx context.Context, userID, spaceID, zoneID string) error {
+	if uuid.Validate(zoneID) != nil {
+		return ErrZoneNotFound
+	}
+
+	if _, err := s.managedSpace(ctx, userID, spaceID); err != nil {
+		return err
+	}
+
+	deleted, err := s.repository.Delete(ctx, spaceID, zoneID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrZoneNotFound
+	}
+
+	s.listener.ZonesChanged(spaceID)
+
+	return nil
+}
+
+// managedSpace loads a space whose zones userID may edit, which owners and
+// moderators can.
+func (s *zoneService) managedSpace(ctx context.Context, userID, spaceID string) (*Space, error) {
+	space, err := s.spaces.EnterSpace(ctx, userID, spaceID, "")
+	if err != nil {
+		return nil, managedSpaceError(err)
+	}
+
+	role, err := s.spaces.MemberRole(ctx, spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != SpaceRoleOwner && role != SpaceRoleModerator {
+		return nil, ErrSpaceForbidden
+	}
+
+	return space, nil
+}
+
+func validateZone(zone *Zone, space *Space) error {
+	invalid := func(msg string) error {
+		return &ZoneValidationError{Message: msg}
+	}
+
+	if zone.Name == "" || len(zone.Name) > maxZoneNameLength {
+		return invalid("name must be 1 to 64 characters")
+	}
+
+	switch zone.Shape {
+	case ZoneRect:
+		if len(zone.Points) != 2 || zone.Points[0].X >= zone.Points[1].X || zone.Points[0].Y >= zone.Points[1].Y {
+			return invalid("rectangle needs a positive width and height")
+		}
+	case ZonePolygon:
+		if len(zone.Points) < 3 || len(zone.Points) > maxZonePoints {
+			return invalid("polygon needs 3 to 64 points")
+		}
+	default:
+		return invalid("shape must be rect or polygon")
+	}
+
+	for _, p := range zone.Points {
+		if p.X < 0 || p.Y < 0 || p.X > space.Width || p.Y > space.Height {
+			return invalid("zone must lie inside the space")
+		}
+	}
+
+	if len(zone.Properties) == 0 {
+		zone.Properties = json.RawMessage("{}")
+	}
+	var props map[string]any
+	if err := json.Unmarshal(zone.Properties, &props); err != nil || props == nil {
+		return invalid("properties must be an object")
+	}
+
+	if zone.ChatScope == "" {
+		zone.ChatScope = ZoneChatSpace
+	}
+	if zone.ChatScope != ZoneChatSpace && zone.ChatScope != ZoneChatZone {
+		return invalid("chatScope must be space or zone")
+	}
+
+	if zone.Audio == "" {
+		zone.Audio = ZoneAudioOpen
+	}
+	switch zone.Audio {
+	case ZoneAudioOpen, ZoneAudioZone, ZoneAudioMuted:
+	default:
+		return invalid("audio must be open, zone or muted")
+	}
+
+	switch zone.MinRole {
+	case "", SpaceRoleMember, SpaceRoleModerator, SpaceRoleOwner:
+	default:
+		return invalid("minRole must be member, moderator or owner")
+	}
+
+	return nil
+}
+
+func spaceRoleRank(role string) int {
+	switch role {
+	case SpaceRoleOwner:
+		return 3
+	case SpaceRoleModerator:
+		return 2
+	case SpaceRoleMember:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,93 @@
+// Package sharding shares spaces out between the real-time nodes with
+// consistent hashing, so that a node joining or leaving only moves the
+// spaces it gains or loses.
+package sharding
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// replicas is how many points each node has on the ring. More points even
+// out the share of spaces each node gets.
+const replicas = 128
+
+// Node is a real-time node as recorded in the node registry. URL is where
+// clients reach its WebSocket server.
+type Node struct {
+	ID          string
+	URL         string
+	Draining    bool
+	HeartbeatAt time.Time
+}
+
+// Ring assigns keys to nodes. The zero Ring has no nodes.
+type Ring struct {
+	points []point
+	nodes  map[string]Node
+}
+
+type point struct {
+	hash uint64
+	node string
+}
+
+// NewRing places every node that is not draining on a ring.
+func NewRing(nodes []Node) *Ring {
+	r := &Ring{nodes: make(map[string]Node)}
+
+	for _, n := range nodes {
+		if n.Draining {
+			continue
+		}
+
+		r.nodes[n.ID] = n
+		for i := range replicas {
+			r.points = append(r.points, point{hash: hash(n.ID + "#" + strconv.Itoa(i)), node: n.ID})
+		}
+	}
+
+	slices.SortFunc(r.points, func(a, b point) int {
+		if a.hash != b.hash {
+			return cmp.Compare(a.hash, b.hash)
+		}
+		return cmp.Compare(a.node, b.node)
+	})
+	return r
+}
+
+// Owner returns the node key belongs to, which is false if the ring is
+// empty.
+func (r *Ring) Owner(key string) (Node, bool) {
+	if r == nil || len(r.points) == 0 {
+		return Node{}, false
+	}
+
+	h := hash(key)
+	i, _ := slices.BinarySearchFunc(r.points, h, func(p point, h uint64) int {
+		return cmp.Compare(p.hash, h)
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.nodes[r.points[i].node], true
+}
+
+// Has reports whether id is on the ring.
+func (r *Ring) Has(id string) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r.nodes[id]
+	return ok
+}
+
+// hash must be the same on every node, so it cannot be seeded.
+func hash(s string) uint64 {
+	sum := sha256.Sum256([]byte(s))
+	return binary.BigEndian.Uint64(sum[:8])
+}
@@ -0,0 +1,239 @@
+package tiled
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type jsonProperty struct {
+	Name  string          `json:"name"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type jsonMap struct {
+	Orientation string         `json:"orientation"`
+	Infinite    bool           `json:"infinite"`
+	Width       int            `json:"width"`
+	Height      int            `json:"height"`
+	TileWidth   int            `json:"tilewidth"`
+	TileHeight  int            `json:"tileheight"`
+	Properties  []jsonProperty `json:"properties"`
+	Tilesets    []jsonTileset  `json:"tilesets"`
+	Layers      []jsonLayer    `json:"layers"`
+}
+
+type jsonTileset struct {
+	FirstGID    uint32         `json:"firstgid"`
+	Source      string         `json:"source"`
+	Name        string         `json:"name"`
+	TileWidth   int            `json:"tilewidth"`
+	TileHeight  int            `json:"tileheight"`
+	TileCount   int            `json:"tilecount"`
+	Columns     int            `json:"columns"`
+	Spacing     int            `json:"spacing"`
+	Margin      int            `json:"margin"`
+	Image       string         `json:"image"`
+	ImageWidth  int            `json:"imagewidth"`
+	ImageHeight int            `json:"imageheight"`
+	Properties  []jsonProperty `json:"properties"`
+	Tiles       []jsonTile     `json:"tiles"`
+}
+
+type jsonTile struct {
+	ID          int            `json:"id"`
+	Image       string         `json:"image"`
+	ImageWidth  int            `json:"imagewidth"`
+	ImageHeight int            `json:"imageheight"`
+	Properties  []jsonProperty `json:"properties"`
+}
+
+type jsonLayer struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Properties  []jsonProperty  `json:"properties"`
+	Data        json.RawMessage `json:"data"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Objects     []jsonObject    `json:"objects"`
+	Layers      []jsonLayer     `json:"layers"`
+}
+
+type jsonObject struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Class      string         `json:"class"`
+	GID        uint32         `json:"gid"`
+	X          float64        `json:"x"`
+	Y          float64        `json:"y"`
+	Width      float64        `json:"width"`
+	Height     float64        `json:"height"`
+	Polygon    []Vec          `json:"polygon"`
+	Polyline   []Vec          `json:"polyline"`
+	Ellipse    bool           `json:"ellipse"`
+	Point      bool           `json:"point"`
+	Properties []jsonProperty `json:"properties"`
+}
+
+func (v *Vec) UnmarshalJSON(data []byte) error {
+	var p struct {
+		X float64 `json:"x"`
+		Y float64 `json:"y"`
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	v.X, v.Y = p.X, p.Y
+	return nil
+}
+
+func parseJSON(data []byte) (*Map, error) {
+	var jm jsonMap
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return nil, invalid("malformed JSON")
+	}
+
+	m := &Map{
+		Orientation: jm.Orientation,
+		Infinite:    jm.Infinite,
+		Width:       jm.Width,
+		Height:      jm.Height,
+		TileWidth:   jm.TileWidth,
+		TileHeight:  jm.TileHeight,
+		Properties:  jsonProperties(jm.Properties),
+	}
+
+	for _, jt := range jm.Tilesets {
+		ts := Tileset{
+			FirstGID:    jt.FirstGID,
+			Source:      jt.Source,
+			Name:        jt.Name,
+			TileWidth:   jt.TileWidth,
+			TileHeight:  jt.TileHeight,
+			TileCount:   jt.TileCount,
+			Columns:     jt.Columns,
+			Spacing:     jt.Spacing,
+			Margin:      jt.Margin,
+			Image:       jt.Image,
+			ImageWidth:  jt.ImageWidth,
+			ImageHeight: jt.ImageHeight,
+			Properties:  jsonProperties(jt.Properties),
+		}
+		for _, t := range jt.Tiles {
+			ts.Tiles = append(ts.Tiles, Tile{
+				ID:          t.ID,
+				Image:       t.Image,
+				ImageWidth:  t.ImageWidth,
+				ImageHeight: t.ImageHeight,
+				Properties:  jsonProperties(t.Properties),
+			})
+		}
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+
+	layers, err := jsonLayers(jm.Layers)
+	if err != nil {
+		return nil, err
+	}
+	m.Layers = layers
+
+	return m, nil
+}
+
+func jsonLayers(jls []jsonLayer) ([]Layer, error) {
+	layers := make([]Layer, 0, len(jls))
+
+	for _, jl := range jls {
+		l := Layer{
+			Type:       jl.Type,
+			Name:       jl.Name,
+			Properties: jsonProperties(jl.Properties),
+		}
+
+		switch jl.Type {
+		case LayerTiles:
+			data, err := jsonLayerData(jl)
+			if err != nil {
+				return nil, err
+			}
+			l.Data = data
+
+		case LayerObjects:
+			for _, jo := range jl.Objects {
+				o := Object{
+					ID:         jo.ID,
+					Name:       jo.Name,
+					Type:       jo.Type,
+					GID:        jo.GID,
+					X:          jo.X,
+					Y:          jo.Y,
+					Width:      jo.Width,
+					Height:     jo.Height,
+					Polygon:    jo.Polygon,
+					Polyline:   jo.Polyline != nil,
+					Ellipse:    jo.Ellipse,
+					Point:      jo.Point,
+					Properties: jsonProperties(jo.Properties),
+				}
+				if o.Type == "" {
+					o.Type = jo.Class
+				}
+				l.Objects = append(l.Objects, o)
+			}
+
+		case LayerGroup:
+			children, err := jsonLayers(jl.Layers)
+			if err != nil {
+				return nil, err
+			}
+			l.Layers = children
+		}
+
+		layers = append(layers, l)
+	}
+
+	return layers, nil
+}
+
+// jsonLayerData reads tile data given as an array of gids or as an encoded
+// string.
+func jsonLayerData(jl jsonLayer) ([]uint32, error) {
+	if jl.Encoding == "base64" {
+		var payload string
+		if err := json.Unmarshal(jl.Data, &payload); err != nil {
+			return nil, invalid("layer %q data must be a string", jl.Name)
+		}
+		return decodeData("base64", jl.Compression, payload)
+	}
+
+	var gids []uint32
+	if err := json.Unmarshal(jl.Data, &gids); err != nil {
+		return nil, invalid("layer %q data must be an array of gids", jl.Name)
+	}
+	return gids, nil
+}
+
+func jsonProperties(jps []jsonProperty) Properties {
+	props := Properties{}
+
+	for _, jp := range jps {
+		var v any
+		if err := json.Unmarshal(jp.Value, &v); err != nil {
+			continue
+		}
+
+		switch jp.Type {
+		case "bool", "int", "float":
+			props[jp.Name] = v
+		default:
+			if s, ok := v.(string); ok {
+				props[jp.Name] = s
+			} else {
+				props[jp.Name] = fmt.Sprint(v)
+			}
+		}
+	}
+
+	return props
+}
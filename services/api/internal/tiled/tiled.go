@@ -0,0 +1,329 @@
+// Package tiled reads maps saved by the Tiled editor, in its JSON or TMX
+// format, into one model.
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Flip flags stored in the high bits of a gid.
+const (
+	flippedHorizontally = 0x80000000
+	flippedVertically   = 0x40000000
+	flippedDiagonally   = 0x20000000
+	rotatedHexagonal120 = 0x10000000
+
+	gidMask = ^uint32(flippedHorizontally | flippedVertically | flippedDiagonally | rotatedHexagonal120)
+)
+
+const (
+	LayerTiles   = "tilelayer"
+	LayerObjects = "objectgroup"
+	LayerGroup   = "group"
+)
+
+var ErrInvalidMap = errors.New("invalid tiled map")
+
+// MapError explains why a map could not be read.
+type MapError struct {
+	Message string
+}
+
+func (e *MapError) Error() string {
+	return ErrInvalidMap.Error() + ": " + e.Message
+}
+
+func (e *MapError) Unwrap() error {
+	return ErrInvalidMap
+}
+
+func invalid(format string, args ...any) error {
+	return &MapError{Message: fmt.Sprintf(format, args...)}
+}
+
+// Properties are custom properties, typed by their Tiled type: bools,
+// float64 for int and float, and strings for everything else.
+type Properties map[string]any
+
+// String returns the property name if it is a non-empty string.
+func (p Properties) String(name string) string {
+	s, _ := p[name].(string)
+	return s
+}
+
+// Bool returns the property name and whether it is set to a bool.
+func (p Properties) Bool(name string) (bool, bool) {
+	b, ok := p[name].(bool)
+	return b, ok
+}
+
+type Map struct {
+	Orientation string
+	Infinite    bool
+	Width       int
+	Height      int
+	TileWidth   int
+	TileHeight  int
+	Properties  Properties
+	Tilesets    []Tileset
+	Layers      []Layer
+}
+
+// Tileset is either an atlas, cut into tiles from Image, or a collection
+// with one image per tile. Source is set for external tilesets, which are
+// not embedded in the map.
+type Tileset struct {
+	FirstGID    uint32
+	Source      string
+	Name        string
+	TileWidth   int
+	TileHeight  int
+	TileCount   int
+	Columns     int
+	Spacing     int
+	Margin      int
+	Image       string
+	ImageWidth  int
+	ImageHeight int
+	Properties  Properties
+	Tiles       []Tile
+}
+
+// Tile holds what a tileset says about one of its tiles. Image is only set
+// in collections.
+type Tile struct {
+	ID          int
+	Image       string
+	ImageWidth  int
+	ImageHeight int
+	Properties  Properties
+}
+
+// Layer is a tile layer with Data holding a gid per tile row by row, an
+// object group, or a group of further layers.
+type Layer struct {
+	Type       string
+	Name       string
+	Properties Properties
+	Data       []uint32
+	Objects    []Object
+	Layers     []Layer
+}
+
+// Object is an object of an object group, in pixels. Tile objects have a
+// GID and are anchored at their bottom-left corner; polygons have points
+// relative to X, Y.
+type Object struct {
+	ID         int
+	Name       string
+	Type       string
+	GID        uint32
+	X          float64
+	Y          float64
+	Width      float64
+	Height     float64
+	Polygon    []Vec
+	Polyline   bool
+	Ellipse    bool
+	Point      bool
+	Properties Properties
+}
+
+type Vec struct {
+	X float64
+	Y float64
+}
+
+// Parse reads a map in either format, telling them apart by their first
+// character.
+func Parse(data []byte) (*Map, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, invalid("empty file")
+	}
+
+	var (
+		m   *Map
+		err error
+	)
+	if trimmed[0] == '<' {
+		m, err = parseTMX(trimmed)
+	} else {
+		m, err = parseJSON(trimmed)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *Map) validate() error {
+	if m.Orientation != "orthogonal" {
+		return invalid("only orthogonal maps are supported")
+	}
+
+	if m.Infinite {
+		return invalid("infinite maps are not supported")
+	}
+
+	if m.Width <= 0 || m.Height <= 0 || m.TileWidth <= 0 || m.TileHeight <= 0 {
+		return invalid("map and tile sizes must be positive")
+	}
+
+	var check func(layers []Layer) error
+	check = func(layers []Layer) error {
+		for _, l := range layers {
+			if l.Type == LayerTiles && len(l.Data) != m.Width*m.Height {
+				return invalid("layer %q has %d tiles, expected %d", l.Name, len(l.Data), m.Width*m.Height)
+			}
+			if err := check(l.Layers); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return check(m.Layers)
+}
+
+// Walk calls fn for every layer, descending into groups.
+func (m *Map) Walk(fn func(l *Layer)) {
+	var walk func(layers []Layer)
+	walk = func(layers []Layer) {
+		for i := range layers {
+			fn(&layers[i])
+			walk(layers[i].Layers)
+		}
+	}
+	walk(m.Layers)
+}
+
+// TileRef is a tile of a tileset, as found by a gid.
+type TileRef struct {
+	Tileset *Tileset
+	ID      int
+}
+
+// Tile returns what the tileset says about the tile, or a zero Tile.
+func (r TileRef) Tile() Tile {
+	for _, t := range r.Tileset.Tiles {
+		if t.ID == r.ID {
+			return t
+		}
+	}
+	return Tile{ID: r.ID}
+}
+
+// Lookup finds the tile a gid refers to, ignoring flip flags. It reports
+// false for empty tiles and gids outside every tileset.
+func (m *Map) Lookup(gid uint32) (TileRef, bool) {
+	gid &= gidMask
+	if gid == 0 {
+		return TileRef{}, false
+	}
+
+	var found *Tileset
+	for i := range m.Tilesets {
+		ts := &m.Tilesets[i]
+		if ts.FirstGID <= gid && (found == nil || ts.FirstGID > found.FirstGID) {
+			found = ts
+		}
+	}
+	if found == nil {
+		return TileRef{}, false
+	}
+
+	id := int(gid - found.FirstGID)
+	if found.TileCount > 0 && id >= found.TileCount {
+		return TileRef{}, false
+	}
+
+	return TileRef{Tileset: found, ID: id}, true
+}
+
+// decodeData decodes the base64 or CSV payload of a tile layer.
+func decodeData(encoding, compression, payload string) ([]uint32, error) {
+	switch encoding {
+	case "csv":
+		var gids []uint32
+		for _, field := range strings.FieldsFunc(payload, func(r rune) bool {
+			return r == ',' || r == '\n' || r == '\r' || r == ' ' || r == '\t'
+		}) {
+			gid, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, invalid("malformed csv tile data")
+			}
+			gids = append(gids, uint32(gid))
+		}
+		return gids, nil
+
+	case "base64":
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(payload))
+		if err != nil {
+			return nil, invalid("malformed base64 tile data")
+		}
+
+		var r io.Reader = bytes.NewReader(raw)
+		switch compression {
+		case "":
+		case "gzip":
+			if r, err = gzip.NewReader(r); err != nil {
+				return nil, invalid("malformed gzip tile data")
+			}
+		case "zlib":
+			if r, err = zlib.NewReader(r); err != nil {
+				return nil, invalid("malformed zlib tile data")
+			}
+		default:
+			return nil, invalid("unsupported compression %q", compression)
+		}
+
+		raw, err = io.ReadAll(r)
+		if err != nil || len(raw)%4 != 0 {
+			return nil, invalid("malformed compressed tile data")
+		}
+
+		gids := make([]uint32, len(raw)/4)
+		for i := range gids {
+			b := raw[i*4:]
+			gids[i] = uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+		}
+		return gids, nil
+
+	default:
+		return nil, invalid("unsupported encoding %q", encoding)
+	}
+}
+
+// typedProperty converts a property value given as text.
+func typedProperty(typ, value string) any {
+	switch typ {
+	case "bool":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return value
+		}
+		return b
+	case "int", "float":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return value
+		}
+		return f
+	default:
+		return value
+	}
+}
@@ -0,0 +1,274 @@
+package tiled
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"`
+}
+
+type tmxProperties struct {
+	Properties []tmxProperty `xml:"property"`
+}
+
+type tmxImage struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+type tmxMap struct {
+	Orientation string        `xml:"orientation,attr"`
+	Infinite    int           `xml:"infinite,attr"`
+	Width       int           `xml:"width,attr"`
+	Height      int           `xml:"height,attr"`
+	TileWidth   int           `xml:"tilewidth,attr"`
+	TileHeight  int           `xml:"tileheight,attr"`
+	Properties  tmxProperties `xml:"properties"`
+	Tilesets    []tmxTileset  `xml:"tileset"`
+	tmxLayers
+}
+
+// tmxLayers are the layers of a map or group, by kind.
+type tmxLayers struct {
+	Layers       []tmxLayer       `xml:"layer"`
+	ObjectGroups []tmxObjectGroup `xml:"objectgroup"`
+	Groups       []tmxGroup       `xml:"group"`
+}
+
+type tmxTileset struct {
+	FirstGID   uint32        `xml:"firstgid,attr"`
+	Source     string        `xml:"source,attr"`
+	Name       string        `xml:"name,attr"`
+	TileWidth  int           `xml:"tilewidth,attr"`
+	TileHeight int           `xml:"tileheight,attr"`
+	TileCount  int           `xml:"tilecount,attr"`
+	Columns    int           `xml:"columns,attr"`
+	Spacing    int           `xml:"spacing,attr"`
+	Margin     int           `xml:"margin,attr"`
+	Image      tmxImage      `xml:"image"`
+	Properties tmxProperties `xml:"properties"`
+	Tiles      []tmxTile     `xml:"tile"`
+}
+
+type tmxTile struct {
+	ID         int           `xml:"id,attr"`
+	Image      tmxImage      `xml:"image"`
+	Properties tmxProperties `xml:"properties"`
+}
+
+type tmxLayer struct {
+	Name       string        `xml:"name,attr"`
+	Properties tmxProperties `xml:"properties"`
+	Data       tmxData       `xml:"data"`
+}
+
+type tmxData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Payload     string `xml:",chardata"`
+	Tiles       []struct {
+		GID uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+}
+
+type tmxObjectGroup struct {
+	Name       string        `xml:"name,attr"`
+	Properties tmxProperties `xml:"properties"`
+	Objects    []tmxObject   `xml:"object"`
+}
+
+type tmxGroup struct {
+	Name       string        `xml:"name,attr"`
+	Properties tmxProperties `xml:"properties"`
+	tmxLayers
+}
+
+type tmxObject struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	GID        uint32        `xml:"gid,attr"`
+	X          float64       `xml:"x,attr"`
+	Y          float64       `xml:"y,attr"`
+	Width      float64       `xml:"width,attr"`
+	Height     float64       `xml:"height,attr"`
+	Properties tmxProperties `xml:"properties"`
+	Polygon    *struct {
+		Points string `xml:"points,attr"`
+	} `xml:"polygon"`
+	Polyline *struct{} `xml:"polyline"`
+	Ellipse  *struct{} `xml:"ellipse"`
+	Point    *struct{} `xml:"point"`
+}
+
+func parseTMX(data []byte) (*Map, error) {
+	var tm tmxMap
+	if err := xml.Unmarshal(data, &tm); err != nil {
+		return nil, invalid("malformed TMX")
+	}
+
+	m := &Map{
+		Orientation: tm.Orientation,
+		Infinite:    tm.Infinite != 0,
+		Width:       tm.Width,
+		Height:      tm.Height,
+		TileWidth:   tm.TileWidth,
+		TileHeight:  tm.TileHeight,
+		Properties:  tm.Properties.toProperties(),
+	}
+
+	for _, tt := range tm.Tilesets {
+		ts := Tileset{
+			FirstGID:    tt.FirstGID,
+			Source:      tt.Source,
+			Name:        tt.Name,
+			TileWidth:   tt.TileWidth,
+			TileHeight:  tt.TileHeight,
+			TileCount:   tt.TileCount,
+			Columns:     tt.Columns,
+			Spacing:     tt.Spacing,
+			Margin:      tt.Margin,
+			Image:       tt.Image.Source,
+			ImageWidth:  tt.Image.Width,
+			ImageHeight: tt.Image.Height,
+			Properties:  tt.Properties.toProperties(),
+		}
+		for _, t := range tt.Tiles {
+			ts.Tiles = append(ts.Tiles, Tile{
+				ID:          t.ID,
+				Image:       t.Image.Source,
+				ImageWidth:  t.Image.Width,
+				ImageHeight: t.Image.Height,
+				Properties:  t.Properties.toProperties(),
+			})
+		}
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+
+	layers, err := tm.tmxLayers.toLayers()
+	if err != nil {
+		return nil, err
+	}
+	m.Layers = layers
+
+	return m, nil
+}
+
+// toLayers converts the layers of a map or group. TMX interleaves layer
+// kinds, but nothing read from a map depends on their order.
+func (tl *tmxLayers) toLayers() ([]Layer, error) {
+	var layers []Layer
+
+	for _, l := range tl.Layers {
+		data, err := l.Data.gids()
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, Layer{
+			Type:       LayerTiles,
+			Name:       l.Name,
+			Properties: l.Properties.toProperties(),
+			Data:       data,
+		})
+	}
+
+	for _, g := range tl.ObjectGroups {
+		l := Layer{
+			Type:       LayerObjects,
+			Name:       g.Name,
+			Properties: g.Properties.toProperties(),
+		}
+		for _, to := range g.Objects {
+			o, err := to.toObject()
+			if err != nil {
+				return nil, err
+			}
+			l.Objects = append(l.Objects, o)
+		}
+		layers = append(layers, l)
+	}
+
+	for _, g := range tl.Groups {
+		children, err := g.tmxLayers.toLayers()
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, Layer{
+			Type:       LayerGroup,
+			Name:       g.Name,
+			Properties: g.Properties.toProperties(),
+			Layers:     children,
+		})
+	}
+
+	return layers, nil
+}
+
+func (d *tmxData) gids() ([]uint32, error) {
+	if d.Encoding == "" {
+		gids := make([]uint32, len(d.Tiles))
+		for i, t := range d.Tiles {
+			gids[i] = t.GID
+		}
+		return gids, nil
+	}
+
+	return decodeData(d.Encoding, d.Compression, d.Payload)
+}
+
+func (to *tmxObject) toObject() (Object, error) {
+	o := Object{
+		ID:         to.ID,
+		Name:       to.Name,
+		Type:       to.Type,
+		GID:        to.GID,
+		X:          to.X,
+		Y:          to.Y,
+		Width:      to.Width,
+		Height:     to.Height,
+		Polyline:   to.Polyline != nil,
+		Ellipse:    to.Ellipse != nil,
+		Point:      to.Point != nil,
+		Properties: to.Properties.toProperties(),
+	}
+	if o.Type == "" {
+		o.Type = to.Class
+	}
+
+	if to.Polygon != nil {
+		for _, pair := range strings.Fields(to.Polygon.Points) {
+			xs, ys, ok := strings.Cut(pair, ",")
+			x, errX := strconv.ParseFloat(xs, 64)
+			y, errY := strconv.ParseFloat(ys, 64)
+			if !ok || errX != nil || errY != nil {
+				return Object{}, invalid("object %d has malformed polygon points", to.ID)
+			}
+			o.Polygon = append(o.Polygon, Vec{X: x, Y: y})
+		}
+	}
+
+	return o, nil
+}
+
+func (tp tmxProperties) toProperties() Properties {
+	props := Properties{}
+
+	for _, p := range tp.Properties {
+		value := p.Value
+		if value == "" {
+			// Multi-line strings are stored as the element's text.
+			value = p.Text
+		}
+		props[p.Name] = typedProperty(p.Type, value)
+	}
+
+	return props
+}
@@ -0,0 +1,26 @@
+-- name: UpsertUserBlock :exec
+INSERT INTO user_blocks(user_id, blocked_id, kind, created_at)
+VALUES($1,$2,$3,$4)
+ON CONFLICT (user_id, blocked_id)
+DO UPDATE SET kind = EXCLUDED.kind, created_at = EXCLUDED.created_at;
+
+-- name: DeleteUserBlock :execrows
+DELETE FROM user_blocks
+WHERE user_id = $1 AND blocked_id = $2;
+
+-- name: ListUserBlocks :many
+SELECT * FROM user_blocks
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListUserBlocksInvolving :many
+SELECT * FROM user_blocks
+WHERE user_id = @user_id OR blocked_id = @user_id;
+
+-- name: IsBlocked :one
+SELECT EXISTS (
+    SELECT 1 FROM user_blocks
+    WHERE kind = 'block'
+      AND ((user_id = @user_id AND blocked_id = @other_id)
+        OR (user_id = @other_id AND blocked_id = @user_id))
+);
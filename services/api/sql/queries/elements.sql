@@ -0,0 +1,37 @@
+-- name: GetElement :one
+SELECT * FROM elements
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: SoftDeleteElement :execrows
+UPDATE elements
+SET deleted_at = $2
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreElement :execrows
+UPDATE elements
+SET deleted_at = NULL, updated_at = $2
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: ListDeletedElements :many
+SELECT * FROM elements
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeDeletedElements :execrows
+DELETE FROM elements
+WHERE deleted_at < $1;
+
+-- name: CreateElement :exec
+INSERT INTO elements(id, image_url, width, height, static, created_at, updated_at)
+VALUES($1,$2,$3,$4,$5,$6,$7);
+
+-- name: GetElementByHash :one
+SELECT * FROM elements
+WHERE content_hash = $1 AND deleted_at IS NULL
+ORDER BY created_at
+LIMIT 1;
+
+-- name: ListSpaceCatalogElements :many
+SELECT DISTINCT e.* FROM elements e
+JOIN space_elements se ON se.element_id = e.id
+WHERE se.space_id = $1 AND se.deleted_at IS NULL;
@@ -0,0 +1,35 @@
+-- name: CreateFriendRequest :one
+INSERT INTO friend_requests(id, sender_id, receiver_id, status, created_at, updated_at)
+SELECT $1,$2,$3,$4,$5,$6
+WHERE EXISTS (SELECT 1 FROM users WHERE id = $3 AND deleted_at IS NULL)
+RETURNING *;
+
+-- name: GetFriendRequest :one
+SELECT * FROM friend_requests
+WHERE id = $1;
+
+-- name: UpdateFriendRequestStatus :one
+UPDATE friend_requests
+SET status = $2, updated_at = $3
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: ListPendingFriendRequests :many
+SELECT * FROM friend_requests
+WHERE status = 'pending' AND (sender_id = @user_id OR receiver_id = @user_id)
+ORDER BY created_at DESC;
+
+-- name: ListFriends :many
+SELECT u.id, u.name, u.avatar_id, u.presence_visibility, fr.updated_at AS friends_since, p.space_id
+FROM friend_requests fr
+JOIN users u ON u.id = CASE WHEN fr.sender_id = @user_id THEN fr.receiver_id ELSE fr.sender_id END
+LEFT JOIN user_presence p ON p.user_id = u.id AND p.seen_at > @present_since
+WHERE fr.status = 'accepted' AND (fr.sender_id = @user_id OR fr.receiver_id = @user_id)
+  AND u.deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM user_blocks b
+    WHERE b.kind = 'block'
+      AND ((b.user_id = @user_id AND b.blocked_id = u.id)
+        OR (b.user_id = u.id AND b.blocked_id = @user_id))
+  )
+ORDER BY u.name;
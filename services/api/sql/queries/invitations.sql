@@ -0,0 +1,16 @@
+-- name: CreateUserInvitation :exec
+INSERT INTO user_invitations(id, user_id, token_hash, expires_at, created_at)
+VALUES($1,$2,$3,$4,$5);
+
+-- name: DeletePendingUserInvitations :exec
+DELETE FROM user_invitations
+WHERE user_id = $1 AND accepted_at IS NULL;
+
+-- name: GetPendingUserInvitation :one
+SELECT * FROM user_invitations
+WHERE token_hash = $1 AND accepted_at IS NULL;
+
+-- name: AcceptUserInvitation :execrows
+UPDATE user_invitations
+SET accepted_at = $2
+WHERE id = $1 AND accepted_at IS NULL;
@@ -0,0 +1,15 @@
+-- name: GetUserPreferences :one
+SELECT * FROM user_preferences
+WHERE user_id = $1;
+
+-- name: InsertUserPreferences :one
+INSERT INTO user_preferences(user_id, version, document, updated_at)
+VALUES($1, 1, $2, $3)
+ON CONFLICT (user_id) DO NOTHING
+RETURNING *;
+
+-- name: UpdateUserPreferences :one
+UPDATE user_preferences
+SET document = $2, version = version + 1, updated_at = $3
+WHERE user_id = $1 AND version = @expected_version
+RETURNING *;
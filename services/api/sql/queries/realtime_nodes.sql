@@ -0,0 +1,21 @@
+-- name: UpsertRealtimeNode :exec
+INSERT INTO realtime_nodes (id, url, draining, heartbeat_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (id) DO UPDATE
+SET url = EXCLUDED.url,
+    draining = EXCLUDED.draining,
+    heartbeat_at = EXCLUDED.heartbeat_at;
+
+-- name: ListLiveRealtimeNodes :many
+SELECT id, url, draining, heartbeat_at
+FROM realtime_nodes
+WHERE heartbeat_at > $1
+ORDER BY id;
+
+-- name: DeleteRealtimeNode :exec
+DELETE FROM realtime_nodes
+WHERE id = $1;
+
+-- name: DeleteStaleRealtimeNodes :execrows
+DELETE FROM realtime_nodes
+WHERE heartbeat_at <= $1;
@@ -0,0 +1,57 @@
+-- name: GetSpaceElement :one
+SELECT * FROM space_elements
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetDeletedSpaceElement :one
+SELECT * FROM space_elements
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: SoftDeleteSpaceElement :execrows
+UPDATE space_elements
+SET deleted_at = $2
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreSpaceElement :execrows
+UPDATE space_elements
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: ListDeletedSpaceElements :many
+SELECT * FROM space_elements
+WHERE space_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeDeletedSpaceElements :execrows
+DELETE FROM space_elements
+WHERE deleted_at < $1;
+
+-- name: ListSpaceElements :many
+SELECT * FROM space_elements
+WHERE space_id = $1 AND deleted_at IS NULL
+ORDER BY created_at;
+
+-- name: CreateSpaceElement :execrows
+INSERT INTO space_elements (id, space_id, element_id, x, y, created_at)
+SELECT @id::uuid, @space_id::uuid, e.id, @x::integer, @y::integer, @created_at::timestamp
+FROM elements e
+WHERE e.id = @element_id AND e.deleted_at IS NULL;
+
+-- name: GetSpaceObstacle :one
+SELECT se.id, se.space_id, se.x, se.y, e.width, e.height
+FROM space_elements se
+JOIN elements e ON e.id = se.element_id
+WHERE se.id = $1 AND e.static AND e.deleted_at IS NULL;
+
+-- name: ListSpaceObstacles :many
+SELECT se.id, se.space_id, se.x, se.y, e.width, e.height
+FROM space_elements se
+JOIN elements e ON e.id = se.element_id
+WHERE se.space_id = $1 AND se.deleted_at IS NULL AND e.static AND e.deleted_at IS NULL
+ORDER BY se.created_at;
+
+-- name: UpsertSpaceElement :execrows
+INSERT INTO space_elements (id, space_id, element_id, x, y, created_at)
+SELECT $1, $2, $3, $4, $5, $6
+WHERE EXISTS (SELECT 1 FROM elements WHERE id = $3)
+ON CONFLICT (id) DO UPDATE
+SET x = EXCLUDED.x, y = EXCLUDED.y, deleted_at = NULL;
@@ -0,0 +1,25 @@
+-- name: CreateSpaceInvitation :one
+INSERT INTO space_invitations(id, space_id, code, created_by, expires_at, max_uses, created_at)
+VALUES($1,$2,$3,$4,$5,$6,$7)
+RETURNING *;
+
+-- name: ListSpaceInvitations :many
+SELECT * FROM space_invitations
+WHERE space_id = $1
+ORDER BY created_at DESC;
+
+-- name: DeleteSpaceInvitation :execrows
+DELETE FROM space_invitations
+WHERE id = $1 AND space_id = $2;
+
+-- name: UseSpaceInvitation :one
+UPDATE space_invitations
+SET uses = uses + 1
+WHERE code = $1
+  AND (expires_at IS NULL OR expires_at > @now)
+  AND (max_uses IS NULL OR uses < max_uses)
+RETURNING *;
+
+-- name: GetSpaceInvitationByCode :one
+SELECT * FROM space_invitations
+WHERE code = $1;
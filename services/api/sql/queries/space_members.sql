@@ -0,0 +1,23 @@
+-- name: GetSpaceMember :one
+SELECT * FROM space_members
+WHERE space_id = $1 AND user_id = $2;
+
+-- name: ListSpaceMembers :many
+SELECT * FROM space_members
+WHERE space_id = $1
+ORDER BY created_at;
+
+-- name: AddSpaceMember :exec
+INSERT INTO space_members(space_id, user_id, role, created_at)
+VALUES($1,$2,$3,$4)
+ON CONFLICT (space_id, user_id) DO NOTHING;
+
+-- name: UpsertSpaceMember :one
+INSERT INTO space_members(space_id, user_id, role, created_at)
+VALUES($1,$2,$3,$4)
+ON CONFLICT (space_id, user_id) DO UPDATE SET role = EXCLUDED.role
+RETURNING *;
+
+-- name: DeleteSpaceMember :execrows
+DELETE FROM space_members
+WHERE space_id = $1 AND user_id = $2;
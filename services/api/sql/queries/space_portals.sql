@@ -0,0 +1,20 @@
+-- name: UpsertSpacePortal :exec
+INSERT INTO space_portals (element_id, space_id, destination_space_id, destination_x, destination_y, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (element_id) DO UPDATE
+SET destination_space_id = EXCLUDED.destination_space_id,
+    destination_x = EXCLUDED.destination_x,
+    destination_y = EXCLUDED.destination_y,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: DeleteSpacePortal :execrows
+DELETE FROM space_portals
+WHERE element_id = $1 AND space_id = $2;
+
+-- name: ListSpacePortals :many
+SELECT p.element_id, p.space_id, p.destination_space_id, p.destination_x, p.destination_y,
+    p.created_at, p.updated_at, e.x, e.y
+FROM space_portals p
+JOIN space_elements e ON e.id = p.element_id
+WHERE p.space_id = $1 AND e.deleted_at IS NULL
+ORDER BY p.created_at;
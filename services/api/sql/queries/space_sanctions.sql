@@ -0,0 +1,22 @@
+-- name: UpsertSpaceSanction :one
+INSERT INTO space_sanctions(space_id, user_id, kind, reason, moderator_id, expires_at, created_at)
+VALUES($1,$2,$3,$4,$5,$6,$7)
+ON CONFLICT (space_id, user_id, kind) DO UPDATE
+SET reason = EXCLUDED.reason,
+    moderator_id = EXCLUDED.moderator_id,
+    expires_at = EXCLUDED.expires_at,
+    created_at = EXCLUDED.created_at
+RETURNING *;
+
+-- name: DeleteSpaceSanction :execrows
+DELETE FROM space_sanctions
+WHERE space_id = $1 AND user_id = $2 AND kind = $3;
+
+-- name: ListActiveSpaceSanctions :many
+SELECT * FROM space_sanctions
+WHERE space_id = $1 AND (expires_at IS NULL OR expires_at > @now)
+ORDER BY created_at DESC;
+
+-- name: ListActiveUserSpaceSanctions :many
+SELECT * FROM space_sanctions
+WHERE space_id = $1 AND user_id = $2 AND (expires_at IS NULL OR expires_at > @now);
@@ -0,0 +1,19 @@
+-- name: CreateSpaceVersion :exec
+INSERT INTO space_versions (space_id, version, author_id, action, restored_version, elements, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetSpaceVersion :one
+SELECT * FROM space_versions
+WHERE space_id = $1 AND version = $2;
+
+-- name: GetLatestSpaceVersion :one
+SELECT * FROM space_versions
+WHERE space_id = $1
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: ListSpaceVersions :many
+SELECT * FROM space_versions
+WHERE space_id = $1
+ORDER BY version DESC
+LIMIT $2;
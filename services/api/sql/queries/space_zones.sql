@@ -0,0 +1,19 @@
+-- name: CreateSpaceZone :one
+INSERT INTO space_zones(id, space_id, name, shape, points, properties, chat_scope, audio, min_role, created_at, updated_at)
+VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+RETURNING *;
+
+-- name: UpdateSpaceZone :one
+UPDATE space_zones
+SET name = $3, shape = $4, points = $5, properties = $6, chat_scope = $7, audio = $8, min_role = $9, updated_at = $10
+WHERE id = $1 AND space_id = $2
+RETURNING *;
+
+-- name: DeleteSpaceZone :execrows
+DELETE FROM space_zones
+WHERE id = $1 AND space_id = $2;
+
+-- name: ListSpaceZones :many
+SELECT * FROM space_zones
+WHERE space_id = $1
+ORDER BY created_at;
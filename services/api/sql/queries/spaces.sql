@@ -0,0 +1,50 @@
+-- name: GetSpace :one
+SELECT * FROM spaces
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: LockSpace :one
+SELECT true AS locked FROM spaces
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: GetDeletedSpace :one
+SELECT * FROM spaces
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: SoftDeleteSpace :execrows
+UPDATE spaces
+SET deleted_at = $2
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreSpace :execrows
+UPDATE spaces
+SET deleted_at = NULL, updated_at = $2
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: ListDeletedSpaces :many
+SELECT * FROM spaces
+WHERE creator_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeDeletedSpaces :execrows
+DELETE FROM spaces
+WHERE deleted_at < $1;
+
+-- name: UpdateSpaceVisibility :execrows
+UPDATE spaces
+SET visibility = $2, password_hash = $3, updated_at = $4
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: UpdateSpaceCapacity :execrows
+UPDATE spaces
+SET capacity = $2, updated_at = $3
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: UpdateSpaceRateLimits :execrows
+UPDATE spaces
+SET rate_limits = $2, updated_at = $3
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: CreateSpace :exec
+INSERT INTO spaces(id, name, width, height, thumbnail, creator_id, visibility, capacity, created_at, updated_at)
+VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10);
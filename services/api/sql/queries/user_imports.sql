@@ -0,0 +1,42 @@
+-- name: CreateUserImport :one
+INSERT INTO user_imports(id, created_by, status, dry_run, created_at, updated_at)
+VALUES($1,$2,$3,$4,$5,$6)
+RETURNING *;
+
+-- name: GetUserImport :one
+SELECT * FROM user_imports
+WHERE id = $1;
+
+-- name: ListUnfinishedUserImports :many
+SELECT * FROM user_imports
+WHERE status IN ('pending', 'running')
+ORDER BY created_at;
+
+-- name: ClaimUserImport :execrows
+UPDATE user_imports
+SET status = 'running', updated_at = @updated_at
+WHERE id = @id AND (status = 'pending' OR (status = 'running' AND updated_at < @stale_before));
+
+-- name: UpdateUserImportStatus :exec
+UPDATE user_imports
+SET status = $2, updated_at = $3, finished_at = $4
+WHERE id = $1;
+
+-- name: CreateUserImportRow :exec
+INSERT INTO user_import_rows(import_id, row_number, name, email, role, avatar_id, status, message)
+VALUES($1,$2,$3,$4,$5,$6,$7,$8);
+
+-- name: ListUserImportRows :many
+SELECT * FROM user_import_rows
+WHERE import_id = $1
+ORDER BY row_number;
+
+-- name: ListPendingUserImportRows :many
+SELECT * FROM user_import_rows
+WHERE import_id = $1 AND status = 'pending'
+ORDER BY row_number;
+
+-- name: UpdateUserImportRow :exec
+UPDATE user_import_rows
+SET status = $3, message = $4
+WHERE import_id = $1 AND row_number = $2;
@@ -0,0 +1,23 @@
+-- name: SetUserPresence :exec
+INSERT INTO user_presence (user_id, space_id, node_id, seen_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET space_id = EXCLUDED.space_id,
+    node_id = EXCLUDED.node_id,
+    seen_at = EXCLUDED.seen_at;
+
+-- name: DeleteUserPresence :exec
+DELETE FROM user_presence
+WHERE user_id = $1 AND node_id = $2;
+
+-- name: RefreshUserPresence :exec
+INSERT INTO user_presence (user_id, space_id, node_id, seen_at)
+SELECT unnest(@user_ids::uuid[]), unnest(@space_ids::uuid[]), @node_id::text, @seen_at::timestamp
+ON CONFLICT (user_id) DO UPDATE
+SET space_id = EXCLUDED.space_id,
+    node_id = EXCLUDED.node_id,
+    seen_at = EXCLUDED.seen_at;
+
+-- name: DeleteStaleUserPresence :exec
+DELETE FROM user_presence
+WHERE seen_at <= $1;
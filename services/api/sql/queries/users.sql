@@ -4,6 +4,67 @@ INSERT INTO users(id, name, email, password, created_at, updated_at)
 VALUES($1,$2,$3,$4,$5,$6)
 RETURNING *;
 
+-- name: GetUser :one
+SELECT * FROM users
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: UserActive :one
+SELECT EXISTS (
+    SELECT 1 FROM users
+    WHERE id = $1 AND deleted_at IS NULL
+);
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE lower(email) = lower(@email) AND deleted_at IS NULL;
+
 -- name: ListUsers :many
 SELECT * FROM users
-ORDER BY created_at DESC;
\ No newline at end of file
+WHERE deleted_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: UpdateUserPresenceVisibility :exec
+UPDATE users
+SET presence_visibility = $2, updated_at = $3
+WHERE id = $1;
+
+-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = $2
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreUser :execrows
+UPDATE users
+SET deleted_at = NULL, updated_at = $2
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: ListDeletedUsers :many
+SELECT * FROM users
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at < $1;
+
+-- name: UserEmailExists :one
+SELECT EXISTS (
+    SELECT 1 FROM users
+    WHERE lower(email) = lower(@email)
+);
+
+-- name: CreateInvitedUser :one
+INSERT INTO users(id, name, email, password, avatar_id, role, created_at, updated_at)
+VALUES($1,$2,$3,'',$4,$5,$6,$7)
+ON CONFLICT ((lower(email))) DO NOTHING
+RETURNING *;
+
+-- name: GetInvitedUserForUpdate :one
+SELECT * FROM users
+WHERE lower(email) = lower($1) AND password = '' AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: SetUserPassword :exec
+UPDATE users
+SET password = $2, updated_at = $3
+WHERE id = $1;
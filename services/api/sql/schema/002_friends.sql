@@ -0,0 +1,23 @@
+-- +goose Up
+
+CREATE TABLE friend_requests (
+    id UUID PRIMARY KEY,
+    sender_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    receiver_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX friend_requests_open_pair
+    ON friend_requests (LEAST(sender_id, receiver_id), GREATEST(sender_id, receiver_id))
+    WHERE status IN ('pending', 'accepted');
+
+ALTER TABLE users ADD COLUMN presence_visibility TEXT NOT NULL DEFAULT 'friends';
+
+
+-- +goose Down
+
+ALTER TABLE users DROP COLUMN presence_visibility;
+
+DROP TABLE friend_requests;
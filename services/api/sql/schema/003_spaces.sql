@@ -0,0 +1,17 @@
+-- +goose Up
+
+CREATE TABLE spaces (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    thumbnail TEXT,
+    creator_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+
+-- +goose Down
+
+DROP TABLE spaces;
@@ -0,0 +1,16 @@
+-- +goose Up
+
+CREATE TABLE user_blocks (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, blocked_id)
+);
+
+CREATE INDEX user_blocks_blocked_id ON user_blocks (blocked_id);
+
+
+-- +goose Down
+
+DROP TABLE user_blocks;
@@ -0,0 +1,13 @@
+-- +goose Up
+
+CREATE TABLE user_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    document JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+
+-- +goose Down
+
+DROP TABLE user_preferences;
@@ -0,0 +1,29 @@
+-- +goose Up
+
+CREATE TABLE elements (
+    id UUID PRIMARY KEY,
+    image_url TEXT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    static BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE space_elements (
+    id UUID PRIMARY KEY,
+    space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    element_id UUID NOT NULL REFERENCES elements(id) ON DELETE CASCADE,
+    x INTEGER NOT NULL,
+    y INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX space_elements_space_id ON space_elements (space_id);
+
+
+-- +goose Down
+
+DROP TABLE space_elements;
+
+DROP TABLE elements;
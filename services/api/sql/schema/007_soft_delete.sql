@@ -0,0 +1,19 @@
+-- +goose Up
+
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE spaces ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE elements ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE space_elements ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX spaces_deleted_at ON spaces (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX elements_deleted_at ON elements (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX space_elements_deleted_at ON space_elements (deleted_at) WHERE deleted_at IS NOT NULL;
+
+
+-- +goose Down
+
+ALTER TABLE space_elements DROP COLUMN deleted_at;
+ALTER TABLE elements DROP COLUMN deleted_at;
+ALTER TABLE spaces DROP COLUMN deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
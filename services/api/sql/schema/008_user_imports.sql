@@ -0,0 +1,45 @@
+-- +goose Up
+
+CREATE UNIQUE INDEX users_email_lower ON users (lower(email));
+
+CREATE TABLE user_imports (
+    id UUID PRIMARY KEY,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL,
+    dry_run BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP
+);
+
+CREATE TABLE user_import_rows (
+    import_id UUID NOT NULL REFERENCES user_imports(id) ON DELETE CASCADE,
+    row_number INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    role TEXT NOT NULL,
+    avatar_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (import_id, row_number)
+);
+
+CREATE TABLE user_invitations (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL
+);
+
+
+-- +goose Down
+
+DROP TABLE user_invitations;
+
+DROP TABLE user_import_rows;
+
+DROP TABLE user_imports;
+
+DROP INDEX users_email_lower;
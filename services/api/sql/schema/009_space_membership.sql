@@ -0,0 +1,40 @@
+-- +goose Up
+
+ALTER TABLE spaces ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public';
+ALTER TABLE spaces ADD COLUMN password_hash TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE space_members (
+    space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (space_id, user_id)
+);
+
+CREATE INDEX space_members_user ON space_members (user_id);
+
+INSERT INTO space_members (space_id, user_id, role, created_at)
+SELECT id, creator_id, 'owner', created_at FROM spaces;
+
+CREATE TABLE space_invitations (
+    id UUID PRIMARY KEY,
+    space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    code TEXT NOT NULL UNIQUE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP,
+    max_uses INTEGER,
+    uses INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX space_invitations_space ON space_invitations (space_id);
+
+
+-- +goose Down
+
+DROP TABLE space_invitations;
+
+DROP TABLE space_members;
+
+ALTER TABLE spaces DROP COLUMN password_hash;
+ALTER TABLE spaces DROP COLUMN visibility;
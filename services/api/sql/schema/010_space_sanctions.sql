@@ -0,0 +1,17 @@
+-- +goose Up
+
+CREATE TABLE space_sanctions (
+    space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    moderator_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (space_id, user_id, kind)
+);
+
+
+-- +goose Down
+
+DROP TABLE space_sanctions;
@@ -0,0 +1,8 @@
+-- +goose Up
+
+ALTER TABLE spaces ADD COLUMN capacity INTEGER;
+
+
+-- +goose Down
+
+ALTER TABLE spaces DROP COLUMN capacity;
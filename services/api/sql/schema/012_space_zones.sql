@@ -0,0 +1,22 @@
+-- +goose Up
+
+CREATE TABLE space_zones (
+    id UUID PRIMARY KEY,
+    space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    shape TEXT NOT NULL,
+    points JSONB NOT NULL,
+    properties JSONB NOT NULL DEFAULT '{}',
+    chat_scope TEXT NOT NULL DEFAULT 'space',
+    audio TEXT NOT NULL DEFAULT 'open',
+    min_role TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX space_zones_space ON space_zones (space_id);
+
+
+-- +goose Down
+
+DROP TABLE space_zones;
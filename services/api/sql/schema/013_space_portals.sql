@@ -0,0 +1,18 @@
+-- +goose Up
+
+CREATE TABLE space_portals (
+    element_id UUID PRIMARY KEY REFERENCES space_elements(id) ON DELETE CASCADE,
+    space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    destination_space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    destination_x INTEGER NOT NULL,
+    destination_y INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX space_portals_space_id ON space_portals (space_id);
+
+
+-- +goose Down
+
+DROP TABLE space_portals;
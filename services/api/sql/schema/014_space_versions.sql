@@ -0,0 +1,17 @@
+-- +goose Up
+
+CREATE TABLE space_versions (
+    space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    restored_version INTEGER,
+    elements JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (space_id, version)
+);
+
+
+-- +goose Down
+
+DROP TABLE space_versions;
@@ -0,0 +1,29 @@
+-- +goose Up
+
+ALTER TABLE elements ADD COLUMN content_hash TEXT;
+
+-- The hash must match service.ElementHash.
+-- +goose StatementBegin
+CREATE FUNCTION elements_content_hash() RETURNS trigger AS $$
+BEGIN
+    NEW.content_hash := encode(sha256(convert_to(
+        NEW.image_url || '|' || NEW.width || 'x' || NEW.height || '|' || NEW.static, 'UTF8')), 'hex');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER elements_content_hash
+BEFORE INSERT OR UPDATE OF image_url, width, height, static ON elements
+FOR EACH ROW EXECUTE FUNCTION elements_content_hash();
+
+UPDATE elements SET image_url = image_url;
+
+CREATE INDEX elements_content_hash ON elements (content_hash) WHERE deleted_at IS NULL;
+
+
+-- +goose Down
+
+DROP TRIGGER elements_content_hash ON elements;
+DROP FUNCTION elements_content_hash();
+ALTER TABLE elements DROP COLUMN content_hash;
@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- Every real-time node keeps its row's heartbeat fresh; spaces are shared
+-- out between the nodes with a recent heartbeat that are not draining.
+CREATE TABLE realtime_nodes (
+    id TEXT PRIMARY KEY,
+    url TEXT NOT NULL,
+    draining BOOLEAN NOT NULL DEFAULT FALSE,
+    heartbeat_at TIMESTAMP NOT NULL
+);
+
+
+-- +goose Down
+
+DROP TABLE realtime_nodes;
@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- Overrides of how fast real-time messages may be sent in the space, as a
+-- list of {message, role, rate, burst}.
+ALTER TABLE spaces ADD COLUMN rate_limits JSONB NOT NULL DEFAULT '[]';
+
+
+-- +goose Down
+
+ALTER TABLE spaces DROP COLUMN rate_limits;
@@ -0,0 +1,19 @@
+-- +goose Up
+
+-- Where each connected user is, kept by the real-time node they are
+-- connected to so that friends on any node can find them. A node refreshes
+-- the rows of its users while it runs; rows it stopped refreshing are
+-- stale.
+CREATE TABLE user_presence (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    node_id TEXT NOT NULL,
+    seen_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX user_presence_node_id ON user_presence (node_id);
+
+
+-- +goose Down
+
+DROP TABLE user_presence;
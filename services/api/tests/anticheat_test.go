@@ -0,0 +1,147 @@
+package tests
+
+import "testing"
+
+func TestMovementAntiCheat(t *testing.T) {
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Guarded",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	owner := joinSpaceAt(t, spaceId, ownerToken, 10, 10)
+
+	move := func(x, y int) {
+		owner.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": x, "y": y},
+		})
+	}
+
+	t.Run("Jumps are rejected with the authoritative position", func(t *testing.T) {
+		move(10, 12)
+
+		msg := waitForMessage(t, owner)
+		if msg["type"] != "movement-rejected" {
+			t.Fatalf("expected movement-rejected got %v", msg["type"])
+		}
+
+		payload := msg["payload"].(map[string]any)
+		if payload["reason"] != "not-adjacent" || payload["x"] != 10.0 || payload["y"] != 10.0 {
+			t.Fatalf("expected to be put back at 10,10 got %v", payload)
+		}
+	})
+
+	t.Run("Diagonal moves are rejected", func(t *testing.T) {
+		move(11, 11)
+
+		msg := waitForMessage(t, owner)
+		if msg["type"] != "movement-rejected" || msg["payload"].(map[string]any)["reason"] != "not-adjacent" {
+			t.Fatalf("expected not-adjacent got %v", msg)
+		}
+	})
+
+	t.Run("Moving faster than the limit is rejected", func(t *testing.T) {
+		// More steps than the burst allows, back and forth, sent at once.
+		for i := 0; i < 10; i++ {
+			move(11-i%2, 10)
+		}
+
+		msg := waitForMessage(t, owner)
+		payload := msg["payload"].(map[string]any)
+		if msg["type"] != "movement-rejected" || payload["reason"] != "too-fast" {
+			t.Fatalf("expected too-fast got %v", msg)
+		}
+	})
+
+	t.Run("Repeated violations get the client kicked", func(t *testing.T) {
+		for i := 0; i < 30; i++ {
+			move(50, 50)
+		}
+
+		for {
+			msg := waitForMessage(t, owner)
+			if msg["type"] == "kicked" {
+				break
+			}
+			if msg["type"] != "movement-rejected" {
+				t.Fatalf("expected movement-rejected or kicked got %v", msg["type"])
+			}
+		}
+	})
+}
+
+func TestSpawnIsChosenByTheServer(t *testing.T) {
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	_, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Guarded",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	rejoin := func(t *testing.T, payload map[string]any) map[string]any {
+		payload["spaceId"] = spaceId
+		payload["token"] = ownerToken
+
+		ws := dial(t)
+		defer leave(ws)
+
+		ws.WriteJSON(map[string]any{"type": "join", "payload": payload})
+
+		msg := waitForMessage(t, ws)
+		if msg["type"] != "space-joined" {
+			t.Fatalf("expected space-joined got %v", msg["type"])
+		}
+		return msg["payload"].(map[string]any)["spawn"].(map[string]any)
+	}
+
+	leave(joinSpaceAt(t, spaceId, ownerToken, 10, 10))
+
+	t.Run("A rejoin ignores a spawn point the client picked", func(t *testing.T) {
+		spawn := rejoin(t, map[string]any{"spawn": map[string]any{"x": 50, "y": 50}})
+		if spawn["x"] == 50.0 && spawn["y"] == 50.0 {
+			t.Fatalf("expected a spawn point of the server's choosing got %v", spawn)
+		}
+	})
+
+	t.Run("A rejoin ignores a spawn ticket issued to someone else", func(t *testing.T) {
+		spawn := rejoin(t, map[string]any{"spawnTicket": spawnTicket(t, spaceId, guestToken, 50, 50)})
+		if spawn["x"] == 50.0 && spawn["y"] == 50.0 {
+			t.Fatalf("expected a spawn point of the server's choosing got %v", spawn)
+		}
+	})
+
+	t.Run("A spawn ticket from the server is honored", func(t *testing.T) {
+		spawn := rejoin(t, map[string]any{"spawnTicket": spawnTicket(t, spaceId, ownerToken, 50, 50)})
+		if spawn["x"] != 50.0 || spawn["y"] != 50.0 {
+			t.Fatalf("expected to spawn at 50,50 got %v", spawn)
+		}
+	})
+}
+
+func TestAdminsMoveToAnyFreeTile(t *testing.T) {
+	adminId, adminToken := signupAndSignin(t, randomUsername(), "admin")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Guarded",
+		"dimensions": "100x200",
+	}, adminToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	admin := joinSpaceForDeltas(t, spaceId, adminToken, 10, 10)
+	defer leave(admin)
+
+	admin.WriteJSON(map[string]any{
+		"type":    "move",
+		"payload": map[string]any{"x": 50, "y": 50},
+	})
+
+	at := deltaUsers(waitForDelta(t, admin), "moved")[adminId]
+	if at == nil || at["x"] != 50.0 || at["y"] != 50.0 {
+		t.Fatalf("expected to be at 50,50 got %v", at)
+	}
+}
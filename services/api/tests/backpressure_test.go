@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestSlowConsumer(t *testing.T) {
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	_, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Busy",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	// Lift the chat limit so the flood reaches the guest.
+	resp, _ := doRequest(t, "PUT", BACKEND_URL+"/api/v1/space/"+spaceId+"/rate-limits", map[string]any{
+		"rateLimits": []map[string]any{{"message": "chat", "rate": 1000, "burst": 10000}},
+	}, ownerToken)
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200 got %d", resp.StatusCode)
+	}
+
+	owner := joinSpaceAt(t, spaceId, ownerToken, 0, 0)
+	defer leave(owner)
+	guest := joinSpaceAt(t, spaceId, guestToken, 1, 1)
+
+	t.Run("A client that stops reading is disconnected", func(t *testing.T) {
+		// The guest reads nothing while the owner sends far more than
+		// the socket buffers and the send queue hold.
+		message := strings.Repeat("x", 16*1024)
+		for range 2000 {
+			owner.WriteJSON(map[string]any{
+				"type":    "chat",
+				"payload": map[string]any{"message": message},
+			})
+		}
+
+		guest.SetReadDeadline(time.Now().Add(30 * time.Second))
+		for {
+			_, _, err := guest.ReadMessage()
+			if err == nil {
+				continue
+			}
+
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) || closeErr.Code != websocket.ClosePolicyViolation {
+				t.Fatalf("expected a policy violation close got %v", err)
+			}
+			if closeErr.Text != "too slow to keep up" {
+				t.Fatalf("expected a close reason got %q", closeErr.Text)
+			}
+			return
+		}
+	})
+}
+
+func TestSlowDefaultClient(t *testing.T) {
+	ownerId, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	_, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Pacing",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	owner := joinSpaceAt(t, spaceId, ownerToken, 0, 0)
+	defer leave(owner)
+	guest := joinSpaceAt(t, spaceId, guestToken, 0, 5)
+	defer leave(guest)
+	waitForMessage(t, owner) // guest's user-joined
+
+	t.Run("Only the latest movement of a user waits to be sent", func(t *testing.T) {
+		// The guest reads nothing while the owner paces up and down the
+		// space, one step a tick, more times than the send queue holds.
+		x, dx := 0, 1
+		for range 300 {
+			if x+dx < 0 || x+dx > 99 {
+				dx = -dx
+			}
+			x += dx
+			owner.WriteJSON(map[string]any{
+				"type":    "move",
+				"payload": map[string]any{"x": x, "y": 0},
+			})
+			time.Sleep(60 * time.Millisecond)
+		}
+
+		guest.SetReadDeadline(time.Now().Add(10 * time.Second))
+		for {
+			var msg map[string]any
+			if err := guest.ReadJSON(&msg); err != nil {
+				t.Fatal("expected the guest to stay connected:", err)
+			}
+
+			payload, _ := msg["payload"].(map[string]any)
+			if msg["type"] == "movement" && payload["userId"] == ownerId && payload["x"] == float64(x) {
+				return
+			}
+		}
+	})
+}
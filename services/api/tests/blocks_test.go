@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func joinSpace(t *testing.T, spaceId, token string) *websocket.Conn {
+	u := url.URL{Scheme: "ws", Host: "localhost:3001", Path: "/"}
+
+	ws, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		t.Fatal("ws connection failed:", err)
+	}
+
+	ws.WriteJSON(map[string]any{
+		"type": "join",
+		"payload": map[string]any{
+			"spaceId": spaceId,
+			"token":   token,
+		},
+	})
+
+	if msg := waitForMessage(t, ws); msg["type"] != "space-joined" {
+		t.Fatalf("expected space-joined got %v", msg["type"])
+	}
+
+	return ws
+}
+
+// expectNoMessage fails if conn receives a message of msgType within d.
+func expectNoMessage(t *testing.T, conn *websocket.Conn, msgType string, d time.Duration) {
+	deadline := time.Now().Add(d)
+	for {
+		conn.SetReadDeadline(deadline)
+
+		var data map[string]any
+		if err := conn.ReadJSON(&data); err != nil {
+			return
+		}
+
+		if data["type"] == msgType {
+			t.Fatalf("unexpected %s message", msgType)
+		}
+	}
+}
+
+func TestUserBlocks(t *testing.T) {
+	username := randomUsername()
+
+	aliceId, aliceToken := signupAndSignin(t, username, "user")
+	bobId, bobToken := signupAndSignin(t, username+"-blocked", "user")
+
+	resp, _ := doRequest(t, "POST", BACKEND_URL+"/api/v1/user/blocks", map[string]any{
+		"userId": bobId,
+	}, aliceToken)
+
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200 got %d", resp.StatusCode)
+	}
+
+	t.Run("Blocked user appears in block list", func(t *testing.T) {
+		_, data := doRequest(t, "GET", BACKEND_URL+"/api/v1/user/blocks", nil, aliceToken)
+
+		blocks := data["blocks"].([]any)
+		if len(blocks) != 1 || blocks[0].(map[string]any)["kind"] != "block" {
+			t.Fatal("expected one block")
+		}
+	})
+
+	t.Run("Blocked user cannot send friend request", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/requests", map[string]any{
+			"userId": bobId,
+		}, aliceToken)
+
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Blocked user's chat is hidden and DMs are refused", func(t *testing.T) {
+		_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+			"name":       "Test",
+			"dimensions": "100x200",
+		}, aliceToken)
+
+		spaceId := spaceData["spaceId"].(string)
+
+		aliceWs := joinSpace(t, spaceId, aliceToken)
+		defer aliceWs.Close()
+
+		bobWs := joinSpace(t, spaceId, bobToken)
+		defer bobWs.Close()
+
+		bobWs.WriteJSON(map[string]any{
+			"type":    "chat",
+			"payload": map[string]any{"message": "hello"},
+		})
+
+		expectNoMessage(t, aliceWs, "chat", time.Second)
+
+		bobWs.WriteJSON(map[string]any{
+			"type": "direct-message",
+			"payload": map[string]any{
+				"userId":  aliceId,
+				"message": "hello",
+			},
+		})
+
+		if msg := waitForMessage(t, bobWs); msg["type"] != "error" {
+			t.Fatalf("expected error got %v", msg["type"])
+		}
+	})
+
+	t.Run("Unblock removes the block", func(t *testing.T) {
+		resp, _ := doRequest(t, "DELETE", BACKEND_URL+"/api/v1/user/blocks/"+bobId, nil, aliceToken)
+
+		if resp.StatusCode != 204 {
+			t.Fatalf("expected 204 got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package tests
+
+import "testing"
+
+func TestSpaceCapacityQueue(t *testing.T) {
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	_, firstToken := signupAndSignin(t, randomUsername()+"-first", "user")
+	_, secondToken := signupAndSignin(t, randomUsername()+"-second", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Tiny",
+		"dimensions": "100x200",
+	}, ownerToken)
+
+	spaceId := spaceData["spaceId"].(string)
+	spaceURL := BACKEND_URL + "/api/v1/space/" + spaceId
+
+	resp, _ := doRequest(t, "PUT", spaceURL+"/capacity", map[string]any{
+		"capacity": 1,
+	}, ownerToken)
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200 got %d", resp.StatusCode)
+	}
+
+	_, data := doRequest(t, "GET", spaceURL, nil, ownerToken)
+	if data["capacity"] != 1.0 {
+		t.Fatalf("expected capacity 1 got %v", data["capacity"])
+	}
+
+	first := joinSpace(t, spaceId, firstToken)
+
+	second, msg := tryJoinSpace(t, spaceId, secondToken, "")
+	if msg["type"] != "queue-position" {
+		t.Fatalf("expected queue-position got %v", msg["type"])
+	}
+
+	payload := msg["payload"].(map[string]any)
+	if payload["position"] != 1.0 {
+		t.Fatalf("expected position 1 got %v", payload["position"])
+	}
+
+	owner, msg := tryJoinSpace(t, spaceId, ownerToken, "")
+
+	t.Run("Owner jumps the queue", func(t *testing.T) {
+		payload := msg["payload"].(map[string]any)
+		if msg["type"] != "queue-position" || payload["position"] != 1.0 {
+			t.Fatalf("expected owner first in queue got %v", msg)
+		}
+	})
+
+	t.Run("Leaving admits the head of the queue", func(t *testing.T) {
+		leave(first)
+
+		if msg := waitForMessage(t, owner); msg["type"] != "space-joined" {
+			t.Fatalf("expected space-joined got %v", msg["type"])
+		}
+	})
+
+	t.Run("Raising the capacity admits the queue", func(t *testing.T) {
+		resp, _ := doRequest(t, "PUT", spaceURL+"/capacity", map[string]any{
+			"capacity": 2,
+		}, ownerToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		msg := waitForMessage(t, second)
+		for msg["type"] == "queue-position" {
+			msg = waitForMessage(t, second)
+		}
+		if msg["type"] != "space-joined" {
+			t.Fatalf("expected space-joined got %v", msg["type"])
+		}
+
+		if msg := waitForMessage(t, owner); msg["type"] != "user-joined" {
+			t.Fatalf("expected user-joined got %v", msg["type"])
+		}
+	})
+
+	t.Run("Lowering the capacity queues the last admitted", func(t *testing.T) {
+		resp, _ := doRequest(t, "PUT", spaceURL+"/capacity", map[string]any{
+			"capacity": 1,
+		}, ownerToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		msg := waitForMessage(t, second)
+		if msg["type"] != "queue-position" || msg["payload"].(map[string]any)["position"] != 1.0 {
+			t.Fatalf("expected the second user back in the queue got %v", msg)
+		}
+
+		if msg := waitForMessage(t, owner); msg["type"] != "user-left" {
+			t.Fatalf("expected user-left got %v", msg["type"])
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// joinSpaceOn joins a space with a spawn ticket through the node listening
+// at wsURL and returns the space-joined payload.
+func joinSpaceOn(t *testing.T, wsURL, spaceId, token, ticket string) (*websocket.Conn, map[string]any) {
+	ws, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatal("ws connection failed:", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+
+	ws.WriteJSON(map[string]any{
+		"type": "join",
+		"payload": map[string]any{
+			"spaceId":     spaceId,
+			"token":       token,
+			"spawnTicket": ticket,
+		},
+	})
+
+	msg := waitForMessage(t, ws)
+	if msg["type"] != "space-joined" {
+		t.Fatalf("expected space-joined got %v", msg["type"])
+	}
+
+	return ws, msg["payload"].(map[string]any)
+}
+
+// TestClusterFanOut needs a second node sharing the database and the
+// postgres broker, e.g. SECOND_WS_URL=ws://localhost:3002/.
+func TestClusterFanOut(t *testing.T) {
+	secondNode := os.Getenv("SECOND_WS_URL")
+	if secondNode == "" {
+		t.Skip("SECOND_WS_URL is not set")
+	}
+
+	ownerId, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	guestId, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Clustered",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	owner := joinSpaceForDeltas(t, spaceId, ownerToken, 0, 0)
+	guest, joined := joinSpaceOn(t, secondNode, spaceId, guestToken, spawnTicket(t, spaceId, guestToken, 1, 1))
+
+	t.Run("Users on other nodes join", func(t *testing.T) {
+		msg := waitForMessage(t, owner)
+		if msg["type"] != "user-joined" || msg["payload"].(map[string]any)["userId"] != guestId {
+			t.Fatalf("expected the guest to join got %v", msg)
+		}
+
+		// The guest's node learns of the owner once it asks the others,
+		// which may be after the guest is let in.
+		for _, u := range joined["users"].([]any) {
+			if u.(map[string]any)["userId"] == ownerId {
+				return
+			}
+		}
+		msg = waitForMessage(t, guest)
+		if msg["type"] != "user-joined" || msg["payload"].(map[string]any)["userId"] != ownerId {
+			t.Fatalf("expected the owner to be present got %v", msg)
+		}
+	})
+
+	t.Run("Moves reach other nodes", func(t *testing.T) {
+		guest.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 2, "y": 1},
+		})
+
+		at := deltaUsers(waitForDelta(t, owner), "moved")[guestId]
+		if at == nil || at["x"] != 2.0 {
+			t.Fatalf("expected the guest at 2,1 got %v", at)
+		}
+	})
+
+	t.Run("Chat reaches other nodes", func(t *testing.T) {
+		owner.WriteJSON(map[string]any{
+			"type":    "chat",
+			"payload": map[string]any{"message": "across the cluster"},
+		})
+
+		msg := waitForMessage(t, guest)
+		if msg["type"] != "chat" || msg["payload"].(map[string]any)["message"] != "across the cluster" {
+			t.Fatalf("expected the chat got %v", msg)
+		}
+	})
+
+	t.Run("Leaving reaches other nodes", func(t *testing.T) {
+		leave(guest)
+
+		msg := waitForMessage(t, owner)
+		if msg["type"] != "user-left" || msg["payload"].(map[string]any)["userId"] != guestId {
+			t.Fatalf("expected the guest to leave got %v", msg)
+		}
+	})
+}
+
+// TestFriendsAcrossNodes needs a second node sharing the database, e.g.
+// SECOND_WS_URL=ws://localhost:3002/.
+func TestFriendsAcrossNodes(t *testing.T) {
+	secondNode := os.Getenv("SECOND_WS_URL")
+	if secondNode == "" {
+		t.Skip("SECOND_WS_URL is not set")
+	}
+
+	_, aliceToken := signupAndSignin(t, randomUsername(), "user")
+	bobId, bobToken := signupAndSignin(t, randomUsername()+"-friend", "user")
+
+	_, requestData := doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/requests", map[string]any{
+		"userId": bobId,
+	}, aliceToken)
+	doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/requests/"+requestData["id"].(string)+"/accept", nil, bobToken)
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Hangout",
+		"dimensions": "100x200",
+	}, bobToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	bob, _ := joinSpaceOn(t, secondNode, spaceId, bobToken, spawnTicket(t, spaceId, bobToken, 0, 0))
+	defer leave(bob)
+
+	t.Run("Friends on other nodes are listed in their space", func(t *testing.T) {
+		_, data := doRequest(t, "GET", BACKEND_URL+"/api/v1/friends", nil, aliceToken)
+
+		friends := data["friends"].([]any)
+		if len(friends) != 1 || friends[0].(map[string]any)["spaceId"] != spaceId {
+			t.Fatalf("expected bob in his space got %v", friends)
+		}
+	})
+
+	t.Run("Joining a friend on another node spawns beside them", func(t *testing.T) {
+		resp, data := doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/"+bobId+"/join", nil, aliceToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		alice, joined := joinSpaceOn(t, secondNode, spaceId, aliceToken, data["spawnTicket"].(string))
+		defer leave(alice)
+
+		// The tile to bob's left is off the map.
+		spawn := joined["spawn"].(map[string]any)
+		if x, y := spawn["x"].(float64), spawn["y"].(float64); x < 0 || y < 0 || x+y != 1 {
+			t.Fatalf("expected a free tile next to bob got %v", spawn)
+		}
+	})
+}
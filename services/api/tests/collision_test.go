@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestCollisionGrid(t *testing.T) {
+	_, adminToken := signupAndSignin(t, randomUsername()+"-admin", "admin")
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	_, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+	_, lateToken := signupAndSignin(t, randomUsername()+"-late", "user")
+
+	_, el := doRequest(t, "POST", BACKEND_URL+"/api/v1/admin/element", map[string]any{
+		"imageUrl": "https://test.com/table.png",
+		"width":    2,
+		"height":   2,
+		"static":   true,
+	}, adminToken)
+	elementId := el["id"].(string)
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Furnished",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	owner := joinSpaceAt(t, spaceId, ownerToken, 12, 11)
+	guest := joinSpaceForDeltas(t, spaceId, guestToken, 1, 1)
+	waitForMessage(t, owner) // guest's user-joined
+
+	move := func(x, y int) {
+		owner.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": x, "y": y},
+		})
+	}
+
+	resp, placed := doRequest(t, "POST", BACKEND_URL+"/api/v1/space/element", map[string]any{
+		"elementId": elementId,
+		"spaceId":   spaceId,
+		"x":         10,
+		"y":         10,
+	}, ownerToken)
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200 got %d", resp.StatusCode)
+	}
+	placedId := placed["id"].(string)
+	waitForMessage(t, owner) // elements-changed
+	waitForMessage(t, guest)
+
+	t.Run("Moving into a static element is rejected", func(t *testing.T) {
+		move(11, 11)
+
+		if msg := waitForMessage(t, owner); msg["type"] != "movement-rejected" {
+			t.Fatalf("expected movement-rejected got %v", msg["type"])
+		}
+	})
+
+	t.Run("Blocked moves do not use up the step budget", func(t *testing.T) {
+		// More blocked moves than the burst allows, then a legal one.
+		for i := 0; i < 6; i++ {
+			move(11, 11)
+		}
+		move(12, 10)
+
+		for i := 0; i < 6; i++ {
+			msg := waitForMessage(t, owner)
+			if msg["type"] != "movement-rejected" || msg["payload"].(map[string]any)["reason"] != "blocked" {
+				t.Fatalf("expected blocked got %v", msg)
+			}
+		}
+
+		waitForDelta(t, guest)
+	})
+
+	t.Run("Tiles next to the element are walkable", func(t *testing.T) {
+		move(12, 11)
+
+		waitForDelta(t, guest)
+	})
+
+	t.Run("Spawning on the element moves to the nearest free tile", func(t *testing.T) {
+		u := url.URL{Scheme: "ws", Host: "localhost:3001", Path: "/"}
+		late, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+		if err != nil {
+			t.Fatal("ws connection failed:", err)
+		}
+		// late stays until the end, so its leaving does not get in the way of
+		// the events the other subtests wait for.
+		t.Cleanup(func() { late.Close() })
+
+		late.WriteJSON(map[string]any{
+			"type": "join",
+			"payload": map[string]any{
+				"spaceId":     spaceId,
+				"token":       lateToken,
+				"spawnTicket": spawnTicket(t, spaceId, lateToken, 10, 10),
+			},
+		})
+
+		msg := waitForMessage(t, late)
+		if msg["type"] != "space-joined" {
+			t.Fatalf("expected space-joined got %v", msg["type"])
+		}
+		payload := msg["payload"].(map[string]any)
+		spawn := payload["spawn"].(map[string]any)
+		if payload["spawnMoved"] != true || spawn["x"] == 10.0 && spawn["y"] == 10.0 {
+			t.Fatalf("expected to be moved off the element got %v", payload)
+		}
+		if x, y := spawn["x"].(float64), spawn["y"].(float64); x < 9 || x > 12 || y < 9 || y > 12 {
+			t.Fatalf("expected a tile next to the element got %v", spawn)
+		}
+
+		waitForMessage(t, owner) // late's user-joined
+		waitForMessage(t, guest)
+	})
+
+	t.Run("Removing the element frees its tiles", func(t *testing.T) {
+		resp, _ := doRequest(t, "DELETE", BACKEND_URL+"/api/v1/space/element", map[string]any{
+			"id": placedId,
+		}, ownerToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+		waitForMessage(t, guest) // elements-changed
+
+		move(11, 11)
+
+		waitForDelta(t, guest)
+	})
+}
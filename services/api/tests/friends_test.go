@@ -0,0 +1,151 @@
+package tests
+
+import "testing"
+
+func signupAndSignin(t *testing.T, username, userType string) (string, string) {
+	password := "123456"
+
+	_, signupData := doRequest(t, "POST", BACKEND_URL+"/api/v1/signup", map[string]any{
+		"username": username,
+		"password": password,
+		"type":     userType,
+	}, "")
+
+	_, signinData := doRequest(t, "POST", BACKEND_URL+"/api/v1/signin", map[string]any{
+		"username": username,
+		"password": password,
+	}, "")
+
+	return signupData["userId"].(string), signinData["token"].(string)
+}
+
+func TestFriendRequests(t *testing.T) {
+	username := randomUsername()
+
+	aliceId, aliceToken := signupAndSignin(t, username, "user")
+	bobId, bobToken := signupAndSignin(t, username+"-friend", "user")
+
+	t.Run("Cannot befriend yourself", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/requests", map[string]any{
+			"userId": aliceId,
+		}, aliceToken)
+
+		if resp.StatusCode != 400 {
+			t.Fatalf("expected 400 got %d", resp.StatusCode)
+		}
+	})
+
+	resp, requestData := doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/requests", map[string]any{
+		"userId": bobId,
+	}, aliceToken)
+
+	if resp.StatusCode != 201 {
+		t.Fatalf("expected 201 got %d", resp.StatusCode)
+	}
+
+	requestId := requestData["id"].(string)
+
+	t.Run("Duplicate request conflicts", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/requests", map[string]any{
+			"userId": aliceId,
+		}, bobToken)
+
+		if resp.StatusCode != 409 {
+			t.Fatalf("expected 409 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Sender cannot accept their own request", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/requests/"+requestId+"/accept", nil, aliceToken)
+
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Receiver sees incoming request", func(t *testing.T) {
+		_, data := doRequest(t, "GET", BACKEND_URL+"/api/v1/friends/requests", nil, bobToken)
+
+		incoming := data["incoming"].([]any)
+		if len(incoming) != 1 {
+			t.Fatalf("expected 1 incoming request got %d", len(incoming))
+		}
+	})
+
+	t.Run("Receiver accepts request", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/requests/"+requestId+"/accept", nil, bobToken)
+
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		_, data := doRequest(t, "GET", BACKEND_URL+"/api/v1/friends", nil, aliceToken)
+
+		friends := data["friends"].([]any)
+		if len(friends) != 1 || friends[0].(map[string]any)["id"] != bobId {
+			t.Fatal("expected bob in friends list")
+		}
+	})
+
+	t.Run("Accepted requests cannot be resolved again", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/requests/"+requestId+"/decline", nil, bobToken)
+
+		if resp.StatusCode != 409 {
+			t.Fatalf("expected 409 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Deleted users cannot be befriended", func(t *testing.T) {
+		_, adminToken := signupAndSignin(t, username+"-admin", "admin")
+		goneId, _ := signupAndSignin(t, username+"-gone", "user")
+
+		resp, _ := doRequest(t, "DELETE", BACKEND_URL+"/api/v1/admin/user/"+goneId, nil, adminToken)
+		if resp.StatusCode != 204 {
+			t.Fatalf("expected 204 got %d", resp.StatusCode)
+		}
+
+		resp, _ = doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/requests", map[string]any{
+			"userId": goneId,
+		}, aliceToken)
+		if resp.StatusCode != 404 {
+			t.Fatalf("expected 404 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Cannot join an offline friend", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/"+bobId+"/join", nil, aliceToken)
+
+		if resp.StatusCode != 404 {
+			t.Fatalf("expected 404 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Joining an online friend spawns beside them", func(t *testing.T) {
+		_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+			"name":       "Hangout",
+			"dimensions": "100x200",
+		}, bobToken)
+		spaceId := spaceData["spaceId"].(string)
+
+		bob := joinSpaceAt(t, spaceId, bobToken, 0, 0)
+		defer leave(bob)
+
+		resp, data := doRequest(t, "POST", BACKEND_URL+"/api/v1/friends/"+bobId+"/join", nil, aliceToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+		if data["spaceId"] != spaceId {
+			t.Fatalf("expected bob's space got %v", data["spaceId"])
+		}
+
+		alice, joined := joinSpaceOn(t, "ws://localhost:3001/", spaceId, aliceToken, data["spawnTicket"].(string))
+		defer leave(alice)
+
+		// The tile to bob's left is off the map.
+		spawn := joined["spawn"].(map[string]any)
+		x, y := spawn["x"].(float64), spawn["y"].(float64)
+		if x < 0 || y < 0 || x+y != 1 {
+			t.Fatalf("expected a free tile next to bob got %v", spawn)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func postCSV(t *testing.T, url, csv, token string) (*http.Response, map[string]any) {
+	req, err := http.NewRequest("POST", url, strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	var result map[string]any
+	json.Unmarshal(respBody, &result)
+
+	return resp, result
+}
+
+func waitForImport(t *testing.T, importId, token string) map[string]any {
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		_, data := doRequest(t, "GET", BACKEND_URL+"/api/v1/admin/users/import/"+importId, nil, token)
+		if data["status"] == "completed" || data["status"] == "failed" {
+			return data
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	t.Fatal("import did not finish")
+	return nil
+}
+
+func TestUserImport(t *testing.T) {
+	_, adminToken := signupAndSignin(t, randomUsername(), "admin")
+	_, userToken := signupAndSignin(t, randomUsername()+"-user", "user")
+
+	prefix := randomUsername()
+	csv := "name,email,role\n" +
+		"Ada," + prefix + "-ada@example.com,admin\n" +
+		"Bob," + prefix + "-bob@example.com,\n" +
+		"Bob again," + strings.ToUpper(prefix) + "-BOB@example.com,user\n" +
+		",missing-name@example.com,user\n" +
+		"Eve,not-an-email,user\n" +
+		"Mallory," + prefix + "-mallory@example.com,owner\n"
+
+	t.Run("Users cannot import", func(t *testing.T) {
+		resp, _ := postCSV(t, BACKEND_URL+"/api/v1/admin/users/import", csv, userToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Missing email column is rejected", func(t *testing.T) {
+		resp, _ := postCSV(t, BACKEND_URL+"/api/v1/admin/users/import", "name,role\nAda,user\n", adminToken)
+		if resp.StatusCode != 400 {
+			t.Fatalf("expected 400 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Dry run validates without creating users", func(t *testing.T) {
+		resp, data := postCSV(t, BACKEND_URL+"/api/v1/admin/users/import?dryRun=true", csv, adminToken)
+		if resp.StatusCode != 202 {
+			t.Fatalf("expected 202 got %d", resp.StatusCode)
+		}
+
+		data = waitForImport(t, data["id"].(string), adminToken)
+
+		summary := data["summary"].(map[string]any)
+		if summary["valid"] != 2.0 || summary["invalid"] != 3.0 || summary["skipped"] != 1.0 {
+			t.Fatalf("unexpected summary %v", summary)
+		}
+	})
+
+	t.Run("Import creates users and is idempotent on email", func(t *testing.T) {
+		_, data := postCSV(t, BACKEND_URL+"/api/v1/admin/users/import", csv, adminToken)
+		data = waitForImport(t, data["id"].(string), adminToken)
+
+		summary := data["summary"].(map[string]any)
+		if summary["created"] != 2.0 {
+			t.Fatalf("expected 2 created got %v", summary)
+		}
+
+		_, data = postCSV(t, BACKEND_URL+"/api/v1/admin/users/import", csv, adminToken)
+		data = waitForImport(t, data["id"].(string), adminToken)
+
+		summary = data["summary"].(map[string]any)
+		if summary["created"] != nil || summary["skipped"] != 3.0 {
+			t.Fatalf("expected existing users to be skipped got %v", summary)
+		}
+	})
+
+	t.Run("Pending invitations are sent again", func(t *testing.T) {
+		_, data := postCSV(t, BACKEND_URL+"/api/v1/admin/users/import", "name,email\nAda,"+prefix+"-ada@example.com\n", adminToken)
+		data = waitForImport(t, data["id"].(string), adminToken)
+
+		row := data["rows"].([]any)[0].(map[string]any)
+		if row["status"] != "skipped" || !strings.Contains(row["message"].(string), "sent again") {
+			t.Fatalf("expected the invitation to be sent again got %v", row)
+		}
+	})
+
+	t.Run("Unknown invitation token is rejected", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", BACKEND_URL+"/api/v1/invitations/accept", map[string]any{
+			"token":    "not-a-token",
+			"password": "correct horse battery",
+		}, "")
+		if resp.StatusCode != 404 {
+			t.Fatalf("expected 404 got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInterestManagement(t *testing.T) {
+	ownerId, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	guestId, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Plaza",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	// The view reaches 16 tiles along either axis.
+	owner := joinSpaceForDeltas(t, spaceId, ownerToken, 0, 0)
+	guest := joinSpaceForDeltas(t, spaceId, guestToken, 17, 0)
+
+	move := func(x, y int) {
+		guest.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": x, "y": y},
+		})
+	}
+
+	t.Run("Users coming into range enter each other's view", func(t *testing.T) {
+		// The owner hears nothing of the guest's join, so its first event
+		// is the delta below.
+		move(16, 0)
+
+		entered := deltaUsers(waitForDelta(t, owner), "entered")[guestId]
+		if entered == nil || entered["x"] != 16.0 || entered["y"] != 0.0 {
+			t.Fatalf("expected guest to enter at 16,0 got %v", entered)
+		}
+
+		entered = deltaUsers(waitForDelta(t, guest), "entered")[ownerId]
+		if entered == nil || entered["x"] != 0.0 || entered["y"] != 0.0 {
+			t.Fatalf("expected owner to enter at 0,0 got %v", entered)
+		}
+	})
+
+	t.Run("Movement reaches users in view", func(t *testing.T) {
+		move(15, 0)
+
+		if deltaUsers(waitForDelta(t, owner), "moved")[guestId] == nil {
+			t.Fatal("expected the guest to move")
+		}
+	})
+
+	t.Run("The mover has its own moves confirmed", func(t *testing.T) {
+		at := deltaUsers(waitForDelta(t, guest), "moved")[guestId]
+		if at == nil || at["x"] != 15.0 || at["y"] != 0.0 {
+			t.Fatalf("expected the guest at 15,0 got %v", at)
+		}
+	})
+
+	t.Run("Users going out of range leave each other's view", func(t *testing.T) {
+		move(16, 0)
+		if deltaUsers(waitForDelta(t, owner), "moved")[guestId] == nil {
+			t.Fatal("expected the guest to move")
+		}
+		waitForDelta(t, guest)
+
+		move(17, 0)
+		if deltaUsers(waitForDelta(t, owner), "left")[guestId] == nil {
+			t.Fatal("expected the guest to leave view")
+		}
+		if deltaUsers(waitForDelta(t, guest), "left")[ownerId] == nil {
+			t.Fatal("expected the owner to leave view")
+		}
+	})
+
+	t.Run("Leaving out of range is not announced", func(t *testing.T) {
+		leave(guest)
+
+		expectNoMessage(t, owner, "user-left", time.Second)
+	})
+}
+
+func TestClientsWithoutDeltasSeeTheWholeRoom(t *testing.T) {
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	guestId, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Plaza",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	owner := joinSpaceAt(t, spaceId, ownerToken, 0, 0)
+	guest := joinSpaceForDeltas(t, spaceId, guestToken, 50, 50)
+
+	t.Run("Joins out of range are announced", func(t *testing.T) {
+		msg := waitForMessage(t, owner)
+		if msg["type"] != "user-joined" || msg["payload"].(map[string]any)["userId"] != guestId {
+			t.Fatalf("expected the guest's user-joined got %v", msg)
+		}
+	})
+
+	t.Run("Movement out of range is broadcast", func(t *testing.T) {
+		guest.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 51, "y": 50},
+		})
+
+		msg := waitForMessage(t, owner)
+		if msg["type"] != "movement" || msg["payload"].(map[string]any)["userId"] != guestId {
+			t.Fatalf("expected the guest's movement got %v", msg)
+		}
+	})
+
+	t.Run("Clients asking for deltas still only see their view", func(t *testing.T) {
+		delta := waitForDelta(t, guest)
+		if len(deltaUsers(delta, "entered")) != 0 || len(deltaUsers(delta, "moved")) != 1 {
+			t.Fatalf("expected only the guest's own move got %v", delta)
+		}
+	})
+
+	t.Run("Leaving out of range is announced", func(t *testing.T) {
+		leave(guest)
+
+		msg := waitForMessage(t, owner)
+		if msg["type"] != "user-left" || msg["payload"].(map[string]any)["userId"] != guestId {
+			t.Fatalf("expected the guest's user-left got %v", msg)
+		}
+	})
+}
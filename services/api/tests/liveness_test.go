@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConnectionLiveness(t *testing.T) {
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Heartbeat",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	owner := joinSpaceAt(t, spaceId, ownerToken, 0, 0)
+	defer leave(owner)
+
+	t.Run("Pongs report the round trip", func(t *testing.T) {
+		// Reading answers the server's pings; the next ping comes within
+		// the default interval.
+		owner.SetReadDeadline(time.Now().Add(15 * time.Second))
+		for {
+			var msg map[string]any
+			if err := owner.ReadJSON(&msg); err != nil {
+				t.Fatal("expected a latency report:", err)
+			}
+			if msg["type"] != "latency" {
+				continue
+			}
+
+			rtt, ok := msg["payload"].(map[string]any)["rtt"].(float64)
+			if !ok || rtt < 0 {
+				t.Fatalf("expected a round trip in milliseconds got %v", msg["payload"])
+			}
+			if _, ok := msg["seq"]; ok {
+				t.Fatalf("expected latency reports not to be replayed got seq %v", msg["seq"])
+			}
+			return
+		}
+	})
+
+	t.Run("Round trips are exposed as metrics", func(t *testing.T) {
+		_, adminToken := signupAndSignin(t, randomUsername()+"-admin", "admin")
+
+		resp, vars := doRequest(t, "GET", "http://localhost:3001/debug/vars", nil, adminToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		realtime, _ := vars["realtime"].(map[string]any)
+		if clients, _ := realtime["clients"].(float64); clients < 1 {
+			t.Fatalf("expected at least one client got %v", realtime)
+		}
+		if _, ok := realtime["rttMeanMs"]; !ok {
+			t.Fatalf("expected a mean round trip got %v", realtime)
+		}
+	})
+
+	t.Run("Only admins see the metrics", func(t *testing.T) {
+		resp, _ := doRequest(t, "GET", "http://localhost:3001/debug/vars", nil, "")
+		if resp.StatusCode != 401 {
+			t.Fatalf("expected 401 got %d", resp.StatusCode)
+		}
+
+		resp, _ = doRequest(t, "GET", "http://localhost:3001/debug/vars", nil, ownerToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package tests
+
+import "testing"
+
+func TestSpaceModeration(t *testing.T) {
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	guestId, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Moderated",
+		"dimensions": "100x200",
+	}, ownerToken)
+
+	spaceId := spaceData["spaceId"].(string)
+	spaceURL := BACKEND_URL + "/api/v1/space/" + spaceId
+
+	t.Run("Guests cannot moderate", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", spaceURL+"/bans", map[string]any{
+			"userId": guestId,
+		}, guestToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Kick disconnects with a reason", func(t *testing.T) {
+		guest := joinSpace(t, spaceId, guestToken)
+		defer guest.Close()
+
+		resp, _ := doRequest(t, "POST", spaceURL+"/kick", map[string]any{
+			"userId": guestId,
+			"reason": "spamming",
+		}, ownerToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		msg := waitForMessage(t, guest)
+		payload := msg["payload"].(map[string]any)
+		if msg["type"] != "kicked" || payload["reason"] != "spamming" {
+			t.Fatalf("expected kicked event got %v", msg)
+		}
+	})
+
+	t.Run("Muted users cannot chat", func(t *testing.T) {
+		owner := joinSpaceAt(t, spaceId, ownerToken, 0, 0)
+		defer owner.Close()
+
+		guest := joinSpaceAt(t, spaceId, guestToken, 1, 1)
+		defer guest.Close()
+		waitForMessage(t, owner)
+
+		owner.WriteJSON(map[string]any{
+			"type": "mute",
+			"payload": map[string]any{
+				"userId":   guestId,
+				"reason":   "too loud",
+				"duration": 60,
+			},
+		})
+
+		if msg := waitForMessage(t, guest); msg["type"] != "muted" {
+			t.Fatalf("expected muted event got %v", msg["type"])
+		}
+
+		guest.WriteJSON(map[string]any{
+			"type":    "chat",
+			"payload": map[string]any{"message": "hello"},
+		})
+
+		if msg := waitForMessage(t, guest); msg["type"] != "error" {
+			t.Fatalf("expected chat to be refused got %v", msg["type"])
+		}
+
+		resp, _ := doRequest(t, "DELETE", spaceURL+"/mutes/"+guestId, nil, ownerToken)
+		if resp.StatusCode != 204 {
+			t.Fatalf("expected 204 got %d", resp.StatusCode)
+		}
+
+		if msg := waitForMessage(t, guest); msg["type"] != "unmuted" {
+			t.Fatalf("expected unmuted event got %v", msg["type"])
+		}
+	})
+
+	t.Run("Banned users cannot rejoin", func(t *testing.T) {
+		guest := joinSpace(t, spaceId, guestToken)
+		defer guest.Close()
+
+		resp, _ := doRequest(t, "POST", spaceURL+"/bans", map[string]any{
+			"userId": guestId,
+			"reason": "harassment",
+		}, ownerToken)
+		if resp.StatusCode != 201 {
+			t.Fatalf("expected 201 got %d", resp.StatusCode)
+		}
+
+		if msg := waitForMessage(t, guest); msg["type"] != "banned" {
+			t.Fatalf("expected banned event got %v", msg["type"])
+		}
+
+		if _, msg := tryJoinSpace(t, spaceId, guestToken, ""); msg["type"] != "error" {
+			t.Fatalf("expected join to be refused got %v", msg["type"])
+		}
+
+		resp, _ = doRequest(t, "GET", spaceURL, nil, guestToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Banned users do not use up invitations", func(t *testing.T) {
+		_, inv := doRequest(t, "POST", spaceURL+"/invitations", map[string]any{
+			"expiresIn": 3600,
+			"maxUses":   1,
+		}, ownerToken)
+		acceptURL := BACKEND_URL + "/api/v1/space/invitations/" + inv["code"].(string) + "/accept"
+
+		resp, _ := doRequest(t, "POST", acceptURL, nil, guestToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+
+		_, otherToken := signupAndSignin(t, randomUsername()+"-other", "user")
+		resp, _ = doRequest(t, "POST", acceptURL, nil, otherToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected the invitation to still have its use got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPathfinding(t *testing.T) {
+	_, adminToken := signupAndSignin(t, randomUsername()+"-admin", "admin")
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	_, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, el := doRequest(t, "POST", BACKEND_URL+"/api/v1/admin/element", map[string]any{
+		"imageUrl": "https://test.com/wall.png",
+		"width":    1,
+		"height":   5,
+		"static":   true,
+	}, adminToken)
+	elementId := el["id"].(string)
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Maze",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	doRequest(t, "POST", BACKEND_URL+"/api/v1/space/element", map[string]any{
+		"elementId": elementId,
+		"spaceId":   spaceId,
+		"x":         5,
+		"y":         0,
+	}, ownerToken)
+
+	pathURL := func(fromX, fromY, toX, toY int) string {
+		return fmt.Sprintf("%s/api/v1/space/%s/path?fromX=%d&fromY=%d&toX=%d&toY=%d",
+			BACKEND_URL, spaceId, fromX, fromY, toX, toY)
+	}
+
+	t.Run("Paths go around obstacles", func(t *testing.T) {
+		resp, data := doRequest(t, "GET", pathURL(0, 0, 10, 0), nil, guestToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		path := data["path"].([]any)
+		if len(path) != 20 {
+			t.Fatalf("expected 20 steps around the wall got %d", len(path))
+		}
+
+		for _, step := range path {
+			p := step.(map[string]any)
+			if p["x"] == 5.0 && p["y"].(float64) < 5 {
+				t.Fatalf("path walks through the wall at %v", p)
+			}
+		}
+	})
+
+	t.Run("Blocked endpoints are rejected", func(t *testing.T) {
+		resp, _ := doRequest(t, "GET", pathURL(0, 0, 5, 2), nil, guestToken)
+		if resp.StatusCode != 400 {
+			t.Fatalf("expected 400 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Move-to walks the path step by step", func(t *testing.T) {
+		owner := joinSpaceAt(t, spaceId, ownerToken, 0, 0)
+		guest := joinSpaceForDeltas(t, spaceId, guestToken, 0, 10)
+		waitForMessage(t, owner) // guest's user-joined
+
+		owner.WriteJSON(map[string]any{
+			"type":    "move-to",
+			"payload": map[string]any{"x": 3, "y": 0},
+		})
+
+		msg := waitForMessage(t, owner)
+		if msg["type"] != "path" {
+			t.Fatalf("expected path got %v", msg["type"])
+		}
+		if steps := msg["payload"].(map[string]any)["steps"].([]any); len(steps) != 3 {
+			t.Fatalf("expected 3 steps got %d", len(steps))
+		}
+
+		for x := 1; x <= 3; x++ {
+			moved := waitForDelta(t, guest)["moved"].([]any)
+			if len(moved) != 1 || moved[0].(map[string]any)["x"] != float64(x) {
+				t.Fatalf("expected movement to x=%d got %v", x, moved)
+			}
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package tests
+
+import "testing"
+
+func TestSpacePortals(t *testing.T) {
+	_, adminToken := signupAndSignin(t, randomUsername()+"-admin", "admin")
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	_, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, el := doRequest(t, "POST", BACKEND_URL+"/api/v1/admin/element", map[string]any{
+		"imageUrl": "https://test.com/portal.png",
+		"width":    1,
+		"height":   1,
+		"static":   false,
+	}, adminToken)
+	elementId := el["id"].(string)
+
+	_, lobbyData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Lobby",
+		"dimensions": "100x200",
+	}, ownerToken)
+	lobbyId := lobbyData["spaceId"].(string)
+
+	_, officeData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Office",
+		"dimensions": "50x50",
+	}, ownerToken)
+	officeId := officeData["spaceId"].(string)
+
+	doRequest(t, "POST", BACKEND_URL+"/api/v1/space/element", map[string]any{
+		"elementId": elementId,
+		"spaceId":   lobbyId,
+		"x":         5,
+		"y":         5,
+	}, ownerToken)
+
+	_, lobby := doRequest(t, "GET", BACKEND_URL+"/api/v1/space/"+lobbyId, nil, ownerToken)
+	portalId := lobby["elements"].([]any)[0].(map[string]any)["id"].(string)
+	portalURL := BACKEND_URL + "/api/v1/space/" + lobbyId + "/portals/" + portalId
+
+	t.Run("Only managers can create portals", func(t *testing.T) {
+		resp, _ := doRequest(t, "PUT", portalURL, map[string]any{
+			"destinationSpaceId": officeId,
+			"spawn":              map[string]any{"x": 1, "y": 1},
+		}, guestToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Spawn must be inside the destination", func(t *testing.T) {
+		resp, _ := doRequest(t, "PUT", portalURL, map[string]any{
+			"destinationSpaceId": officeId,
+			"spawn":              map[string]any{"x": 80, "y": 1},
+		}, ownerToken)
+		if resp.StatusCode != 400 {
+			t.Fatalf("expected 400 got %d", resp.StatusCode)
+		}
+	})
+
+	resp, _ := doRequest(t, "PUT", portalURL, map[string]any{
+		"destinationSpaceId": officeId,
+		"spawn":              map[string]any{"x": 10, "y": 12},
+	}, ownerToken)
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200 got %d", resp.StatusCode)
+	}
+
+	t.Run("Portals are listed", func(t *testing.T) {
+		_, data := doRequest(t, "GET", BACKEND_URL+"/api/v1/space/"+lobbyId+"/portals", nil, guestToken)
+		portals := data["portals"].([]any)
+		if len(portals) != 1 || portals[0].(map[string]any)["destinationSpaceId"] != officeId {
+			t.Fatalf("expected the office portal got %v", portals)
+		}
+	})
+
+	t.Run("Stepping on a portal moves the user to the destination", func(t *testing.T) {
+		guest := joinSpaceAt(t, lobbyId, guestToken, 5, 4)
+		owner := joinSpaceForDeltas(t, lobbyId, ownerToken, 1, 1)
+		waitForMessage(t, guest) // owner's user-joined
+
+		guest.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 5, "y": 5},
+		})
+
+		msg := waitForMessage(t, guest)
+		if msg["type"] != "portal-transition" {
+			t.Fatalf("expected portal-transition got %v", msg["type"])
+		}
+
+		payload := msg["payload"].(map[string]any)
+		spawn := payload["spawn"].(map[string]any)
+		if payload["spaceId"] != officeId || spawn["x"] != 10.0 || spawn["y"] != 12.0 {
+			t.Fatalf("unexpected transition %v", payload)
+		}
+
+		if msg := waitForMessage(t, guest); msg["type"] != "space-joined" {
+			t.Fatalf("expected space-joined got %v", msg["type"])
+		}
+
+		waitForDelta(t, owner)
+		if msg := waitForMessage(t, owner); msg["type"] != "user-left" {
+			t.Fatalf("expected user-left got %v", msg["type"])
+		}
+	})
+
+	t.Run("Portals into spaces the user cannot enter are refused", func(t *testing.T) {
+		resp, _ := doRequest(t, "PUT", BACKEND_URL+"/api/v1/space/"+officeId+"/visibility", map[string]any{
+			"visibility": "invite-only",
+		}, ownerToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		guest := joinSpaceAt(t, lobbyId, guestToken, 5, 4)
+		guest.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 5, "y": 5},
+		})
+
+		if msg := waitForMessage(t, guest); msg["type"] != "error" {
+			t.Fatalf("expected error got %v", msg["type"])
+		}
+	})
+
+	t.Run("Deleting a portal", func(t *testing.T) {
+		resp, _ := doRequest(t, "DELETE", portalURL, nil, ownerToken)
+		if resp.StatusCode != 204 {
+			t.Fatalf("expected 204 got %d", resp.StatusCode)
+		}
+	})
+}
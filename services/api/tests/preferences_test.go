@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func patchPreferences(t *testing.T, token, ifMatch string, patch map[string]any) (*http.Response, map[string]any) {
+	body, _ := json.Marshal(patch)
+
+	req, err := http.NewRequest("PATCH", BACKEND_URL+"/api/v1/user/preferences", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	var result map[string]any
+	json.Unmarshal(respBody, &result)
+
+	return resp, result
+}
+
+func TestUserPreferences(t *testing.T) {
+	_, token := signupAndSignin(t, randomUsername(), "user")
+
+	resp, data := doRequest(t, "GET", BACKEND_URL+"/api/v1/user/preferences", nil, token)
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200 got %d", resp.StatusCode)
+	}
+
+	etag := resp.Header.Get("ETag")
+	if data["version"] != float64(0) {
+		t.Fatal("expected empty preferences at version 0")
+	}
+
+	t.Run("Patch merges into the document", func(t *testing.T) {
+		resp, data := patchPreferences(t, token, etag, map[string]any{
+			"ui":    map[string]any{"theme": "dark"},
+			"audio": map[string]any{"volume": 0.5},
+		})
+
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		resp, data = patchPreferences(t, token, resp.Header.Get("ETag"), map[string]any{
+			"audio": map[string]any{"volume": nil},
+		})
+
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		prefs := data["preferences"].(map[string]any)
+		if prefs["ui"].(map[string]any)["theme"] != "dark" {
+			t.Fatal("theme should survive an unrelated patch")
+		}
+		if _, ok := prefs["audio"].(map[string]any)["volume"]; ok {
+			t.Fatal("null should remove volume")
+		}
+	})
+
+	t.Run("Stale ETag is rejected", func(t *testing.T) {
+		resp, _ := patchPreferences(t, token, etag, map[string]any{
+			"ui": map[string]any{"theme": "light"},
+		})
+
+		if resp.StatusCode != 412 {
+			t.Fatalf("expected 412 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Schema violations are rejected", func(t *testing.T) {
+		resp, data := patchPreferences(t, token, "", map[string]any{
+			"ui": map[string]any{"theme": "neon"},
+		})
+
+		if resp.StatusCode != 422 {
+			t.Fatalf("expected 422 got %d", resp.StatusCode)
+		}
+		if len(data["errors"].([]any)) != 1 {
+			t.Fatal("expected one validation error")
+		}
+	})
+}
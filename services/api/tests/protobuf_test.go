@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vaxxnsh/metaverse/api/wire"
+)
+
+// protoConn is a client speaking the protobuf subprotocol.
+type protoConn struct {
+	conn  *websocket.Conn
+	codec wire.Codec
+}
+
+func dialSubprotocol(t *testing.T, subprotocols ...string) *websocket.Conn {
+	u := url.URL{Scheme: "ws", Host: "localhost:3001", Path: "/"}
+
+	dialer := websocket.Dialer{Subprotocols: subprotocols}
+	ws, _, err := dialer.Dial(u.String(), nil)
+	if err != nil {
+		t.Fatal("ws connection failed:", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+
+	return ws
+}
+
+func (c *protoConn) send(t *testing.T, msgType string, payload map[string]any) {
+	t.Helper()
+
+	raw, _ := json.Marshal(payload)
+	frame, err := c.codec.Encode(wire.Message{Type: msgType, Payload: raw})
+	if err != nil {
+		t.Fatalf("failed to encode %s: %v", msgType, err)
+	}
+	if err := c.conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+		t.Fatal("failed to write websocket message:", err)
+	}
+}
+
+func (c *protoConn) receive(t *testing.T) (string, map[string]any) {
+	t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	for {
+		kind, frame, err := c.conn.ReadMessage()
+		if err != nil {
+			t.Fatal("failed to read websocket message:", err)
+		}
+		if kind != websocket.BinaryMessage {
+			t.Fatalf("expected a binary frame got %d", kind)
+		}
+
+		msg, err := c.codec.Decode(frame)
+		if err != nil {
+			t.Fatal("failed to decode websocket message:", err)
+		}
+		if msg.Type == "latency" {
+			continue
+		}
+
+		var payload map[string]any
+		json.Unmarshal(msg.Payload, &payload)
+		return msg.Type, payload
+	}
+}
+
+func TestProtobufProtocol(t *testing.T) {
+	ownerId, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	guestId, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Binary",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	var guest *protoConn
+
+	t.Run("The server prefers protobuf", func(t *testing.T) {
+		ws := dialSubprotocol(t, wire.SubprotocolJSON, wire.SubprotocolProtobuf)
+		if ws.Subprotocol() != wire.SubprotocolProtobuf {
+			t.Fatalf("expected %s got %q", wire.SubprotocolProtobuf, ws.Subprotocol())
+		}
+	})
+
+	t.Run("JSON can still be negotiated", func(t *testing.T) {
+		ws := dialSubprotocol(t, wire.SubprotocolJSON)
+		if ws.Subprotocol() != wire.SubprotocolJSON {
+			t.Fatalf("expected %s got %q", wire.SubprotocolJSON, ws.Subprotocol())
+		}
+
+		ws.WriteJSON(map[string]any{"type": "chat", "payload": map[string]any{"message": "hi"}})
+		if msg := waitForMessage(t, ws); msg["type"] != "error" {
+			t.Fatalf("expected a JSON error got %v", msg)
+		}
+	})
+
+	owner := joinSpaceForDeltas(t, spaceId, ownerToken, 0, 0)
+
+	t.Run("Protobuf clients join", func(t *testing.T) {
+		ws := dialSubprotocol(t, wire.SubprotocolProtobuf)
+		guest = &protoConn{conn: ws, codec: wire.ClientCodec(ws.Subprotocol())}
+
+		guest.send(t, "join", map[string]any{
+			"spaceId":     spaceId,
+			"token":       guestToken,
+			"spawnTicket": spawnTicket(t, spaceId, guestToken, 1, 1),
+			"stateDeltas": true,
+		})
+
+		msgType, payload := guest.receive(t)
+		if msgType != "space-joined" {
+			t.Fatalf("expected space-joined got %s", msgType)
+		}
+		if payload["userId"] != guestId {
+			t.Fatalf("expected user id %s got %v", guestId, payload["userId"])
+		}
+		if users := payload["users"].([]any); len(users) != 1 {
+			t.Fatalf("expected the owner in the space got %v", users)
+		}
+
+		if msg := waitForMessage(t, owner); msg["type"] != "user-joined" {
+			t.Fatalf("expected user-joined got %v", msg["type"])
+		}
+	})
+
+	t.Run("Protobuf moves reach JSON clients", func(t *testing.T) {
+		guest.send(t, "move", map[string]any{"x": 2, "y": 1})
+
+		at := deltaUsers(waitForDelta(t, owner), "moved")[guestId]
+		if at == nil || at["x"] != 2.0 || at["y"] != 1.0 {
+			t.Fatalf("expected the guest at 2,1 got %v", at)
+		}
+
+		// The guest has its own move confirmed.
+		if msgType, payload := guest.receive(t); msgType != "state-delta" || deltaUsers(payload, "moved")[guestId] == nil {
+			t.Fatalf("expected the guest's own move got %s %v", msgType, payload)
+		}
+	})
+
+	t.Run("JSON moves reach protobuf clients", func(t *testing.T) {
+		owner.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 1, "y": 0},
+		})
+
+		msgType, payload := guest.receive(t)
+		if msgType != "state-delta" {
+			t.Fatalf("expected state-delta got %s", msgType)
+		}
+		if tick, _ := payload["tick"].(float64); tick <= 0 {
+			t.Fatalf("expected a tick got %v", payload["tick"])
+		}
+		if moved := deltaUsers(payload, "moved"); len(moved) != 1 || moved[ownerId] == nil {
+			t.Fatalf("expected the owner to move got %v", payload["moved"])
+		}
+	})
+}
+
+func TestProtobufNumbers(t *testing.T) {
+	server := wire.ServerCodec(wire.SubprotocolProtobuf)
+	client := wire.ClientCodec(wire.SubprotocolProtobuf)
+
+	events := []struct {
+		msgType string
+		field   string
+		payload string
+	}{
+		{"rate-limited", "retryAfter", `{"type":"chat","retryAfter":1500}`},
+		{"latency", "rtt", `{"rtt":42}`},
+	}
+
+	for _, e := range events {
+		t.Run("Round trip of "+e.msgType+" keeps numbers numbers", func(t *testing.T) {
+			frame, err := server.Encode(wire.Message{Type: e.msgType, Payload: json.RawMessage(e.payload)})
+			if err != nil {
+				t.Fatalf("failed to encode %s: %v", e.msgType, err)
+			}
+
+			msg, err := client.Decode(frame)
+			if err != nil {
+				t.Fatalf("failed to decode %s: %v", e.msgType, err)
+			}
+
+			var sent, received map[string]any
+			json.Unmarshal([]byte(e.payload), &sent)
+			json.Unmarshal(msg.Payload, &received)
+
+			if _, ok := received[e.field].(float64); !ok || received[e.field] != sent[e.field] {
+				t.Fatalf("expected %s to be the number %v got %#v", e.field, sent[e.field], received[e.field])
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestMessageRateLimits(t *testing.T) {
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	_, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Chatty",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+	limitsURL := BACKEND_URL + "/api/v1/space/" + spaceId + "/rate-limits"
+
+	t.Run("Only the owner can set rate limits", func(t *testing.T) {
+		resp, _ := doRequest(t, "PUT", limitsURL, map[string]any{
+			"rateLimits": []map[string]any{{"message": "chat", "rate": 10, "burst": 10}},
+		}, guestToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Invalid rate limits are rejected", func(t *testing.T) {
+		for _, limit := range []map[string]any{
+			{"message": "teleport", "rate": 1, "burst": 1},
+			{"message": "chat", "rate": 0, "burst": 1},
+			{"message": "chat", "rate": 1, "burst": 0},
+			{"message": "chat", "role": "admin", "rate": 1, "burst": 1},
+		} {
+			resp, _ := doRequest(t, "PUT", limitsURL, map[string]any{
+				"rateLimits": []map[string]any{limit},
+			}, ownerToken)
+			if resp.StatusCode != 400 {
+				t.Fatalf("expected 400 for %v got %d", limit, resp.StatusCode)
+			}
+		}
+	})
+
+	t.Run("The owner can set rate limits by role", func(t *testing.T) {
+		resp, _ := doRequest(t, "PUT", limitsURL, map[string]any{
+			"rateLimits": []map[string]any{
+				{"message": "chat", "rate": 1, "burst": 2},
+				{"message": "chat", "role": "owner", "rate": 100, "burst": 100},
+			},
+		}, ownerToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+	})
+
+	owner := joinSpaceAt(t, spaceId, ownerToken, 0, 0)
+	defer leave(owner)
+	guest := joinSpaceAt(t, spaceId, guestToken, 1, 1)
+	waitForMessage(t, owner) // guest joined
+
+	t.Run("A role's limit overrides the space's", func(t *testing.T) {
+		for range 5 {
+			owner.WriteJSON(map[string]any{
+				"type":    "chat",
+				"payload": map[string]any{"message": "hello"},
+			})
+		}
+
+		for range 5 {
+			if msg := waitForMessage(t, guest); msg["type"] != "chat" {
+				t.Fatalf("expected chat got %v", msg)
+			}
+		}
+	})
+
+	t.Run("New rate limits apply to connected users", func(t *testing.T) {
+		resp, _ := doRequest(t, "PUT", limitsURL, map[string]any{
+			"rateLimits": []map[string]any{
+				{"message": "chat", "rate": 1, "burst": 2},
+				{"message": "chat", "role": "owner", "rate": 0.1, "burst": 1},
+			},
+		}, ownerToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		for range 3 {
+			owner.WriteJSON(map[string]any{
+				"type":    "chat",
+				"payload": map[string]any{"message": "hello again"},
+			})
+		}
+
+		owner.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for {
+			var msg map[string]any
+			if err := owner.ReadJSON(&msg); err != nil {
+				t.Fatal("expected the owner to be rate limited:", err)
+			}
+			if msg["type"] == "rate-limited" {
+				break
+			}
+		}
+		owner.SetReadDeadline(time.Time{})
+
+		// Only one of the chats gets through.
+		if msg := waitForMessage(t, guest); msg["type"] != "chat" {
+			t.Fatalf("expected chat got %v", msg)
+		}
+	})
+
+	t.Run("Flooding is rate limited, muted and then disconnected", func(t *testing.T) {
+		for range 40 {
+			guest.WriteJSON(map[string]any{
+				"type":    "chat",
+				"payload": map[string]any{"message": "spam"},
+			})
+		}
+
+		var limited, muted, kicked map[string]any
+		guest.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for kicked == nil {
+			var msg map[string]any
+			if err := guest.ReadJSON(&msg); err != nil {
+				t.Fatal("expected to be kicked:", err)
+			}
+
+			switch msg["type"] {
+			case "rate-limited":
+				if limited != nil {
+					t.Fatal("expected a single rate-limited event")
+				}
+				limited = msg["payload"].(map[string]any)
+			case "muted":
+				muted = msg["payload"].(map[string]any)
+			case "kicked":
+				kicked = msg["payload"].(map[string]any)
+			}
+		}
+
+		if limited == nil || limited["type"] != "chat" {
+			t.Fatalf("expected chat to be rate limited got %v", limited)
+		}
+		if retry, _ := limited["retryAfter"].(float64); retry <= 0 {
+			t.Fatalf("expected a retry delay got %v", limited["retryAfter"])
+		}
+		if muted == nil || muted["reason"] != "flooding" || muted["until"] == nil {
+			t.Fatalf("expected a temporary mute for flooding got %v", muted)
+		}
+		if kicked["reason"] != "flooding" {
+			t.Fatalf("expected a kick for flooding got %v", kicked)
+		}
+
+		if _, _, err := guest.ReadMessage(); err == nil {
+			t.Fatal("expected the connection to be closed")
+		}
+	})
+
+	t.Run("Dropped messages are exposed as metrics", func(t *testing.T) {
+		_, adminToken := signupAndSignin(t, randomUsername()+"-admin", "admin")
+
+		resp, vars := doRequest(t, "GET", "http://localhost:3001/debug/vars", nil, adminToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		realtime, _ := vars["realtime"].(map[string]any)
+		dropped, _ := realtime["dropped"].(map[string]any)
+		if chats, _ := dropped["chat"].(float64); chats < 1 {
+			t.Fatalf("expected dropped chats got %v", dropped)
+		}
+		mutes, _ := realtime["floodMutes"].(float64)
+		kicks, _ := realtime["floodKicks"].(float64)
+		if mutes < 1 || kicks < 1 {
+			t.Fatalf("expected a flood mute and kick got %v", realtime)
+		}
+	})
+}
+
+func TestInvalidMessagesAreRateLimited(t *testing.T) {
+	ws, _, err := websocket.DefaultDialer.Dial("ws://localhost:3001/", nil)
+	if err != nil {
+		t.Fatal("ws connection failed:", err)
+	}
+	defer ws.Close()
+
+	for i := range 40 {
+		if i%2 == 0 {
+			ws.WriteMessage(websocket.TextMessage, []byte("not json"))
+		} else {
+			ws.WriteJSON(map[string]any{"type": "teleport"})
+		}
+	}
+
+	errs := 0
+	var limited map[string]any
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var msg map[string]any
+		if err := ws.ReadJSON(&msg); err != nil {
+			t.Fatal("expected to be kicked:", err)
+		}
+
+		switch msg["type"] {
+		case "error":
+			errs++
+		case "rate-limited":
+			limited = msg["payload"].(map[string]any)
+		}
+		if msg["type"] == "kicked" {
+			break
+		}
+	}
+
+	if errs > 5 {
+		t.Fatalf("expected the replies to be limited got %d errors", errs)
+	}
+	if limited == nil || limited["type"] != "invalid" {
+		t.Fatalf("expected invalid messages to be rate limited got %v", limited)
+	}
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// leave closes conn the way a client leaving on purpose does. Merely
+// dropping the connection keeps the user in the space for a resume.
+func leave(conn *websocket.Conn) {
+	conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second),
+	)
+	conn.Close()
+}
+
+func dial(t *testing.T) *websocket.Conn {
+	u := url.URL{Scheme: "ws", Host: "localhost:3001", Path: "/"}
+
+	ws, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		t.Fatal("ws connection failed:", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+
+	return ws
+}
+
+func resume(t *testing.T, token string, lastSeq float64) (*websocket.Conn, map[string]any) {
+	ws := dial(t)
+	ws.WriteJSON(map[string]any{
+		"type":    "resume",
+		"payload": map[string]any{"token": token, "lastSeq": lastSeq},
+	})
+	return ws, waitForMessage(t, ws)
+}
+
+func TestSessionResume(t *testing.T) {
+	ownerId, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	_, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Flaky",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	owner := joinSpaceForDeltas(t, spaceId, ownerToken, 0, 0)
+
+	guest := dial(t)
+	guest.WriteJSON(map[string]any{
+		"type": "join",
+		"payload": map[string]any{
+			"spaceId":     spaceId,
+			"token":       guestToken,
+			"spawnTicket": spawnTicket(t, spaceId, guestToken, 1, 1),
+			"stateDeltas": true,
+		},
+	})
+	joined := waitForMessage(t, guest)
+	if joined["type"] != "space-joined" {
+		t.Fatalf("expected space-joined got %v", joined["type"])
+	}
+	waitForMessage(t, owner) // guest's user-joined
+
+	token, _ := joined["payload"].(map[string]any)["resumeToken"].(string)
+	if token == "" {
+		t.Fatal("expected a resume token")
+	}
+	lastSeq := joined["seq"].(float64)
+
+	t.Run("A dropped user stays in the space", func(t *testing.T) {
+		guest.Close()
+
+		owner.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 1, "y": 0},
+		})
+		owner.WriteJSON(map[string]any{
+			"type":    "chat",
+			"payload": map[string]any{"message": "still there?"},
+		})
+
+		// Give the move a tick to go out.
+		waitForDelta(t, owner)
+		time.Sleep(200 * time.Millisecond)
+	})
+
+	t.Run("Resuming replays the missed events", func(t *testing.T) {
+		ws, msg := resume(t, token, lastSeq)
+		if msg["type"] != "resumed" {
+			t.Fatalf("expected resumed got %v", msg)
+		}
+
+		payload := msg["payload"].(map[string]any)
+		position := payload["position"].(map[string]any)
+		if position["x"] != 1.0 || position["y"] != 1.0 {
+			t.Fatalf("expected the guest at 1,1 got %v", position)
+		}
+		if payload["resumeToken"] == "" || payload["resumeToken"] == token {
+			t.Fatalf("expected a new resume token got %v", payload["resumeToken"])
+		}
+
+		// The chat went out at once, the move on the next tick.
+		missed := map[string]map[string]any{}
+		var last map[string]any
+		for range 2 {
+			last = waitForMessage(t, ws)
+			missed[last["type"].(string)] = last
+		}
+
+		if missed["chat"] == nil {
+			t.Fatalf("expected the chat to be replayed got %v", missed)
+		}
+		delta, _ := missed["state-delta"]["payload"].(map[string]any)
+		if deltaUsers(delta, "moved")[ownerId] == nil {
+			t.Fatalf("expected the owner's move to be replayed got %v", missed)
+		}
+		if last["seq"] != payload["lastSeq"] {
+			t.Fatalf("expected the last event to have seq %v got %v", payload["lastSeq"], last["seq"])
+		}
+
+		token = payload["resumeToken"].(string)
+		lastSeq = last["seq"].(float64)
+		guest = ws
+	})
+
+	t.Run("A used resume token is refused", func(t *testing.T) {
+		_, msg := resume(t, joined["payload"].(map[string]any)["resumeToken"].(string), 0)
+		if msg["type"] != "error" {
+			t.Fatalf("expected error got %v", msg)
+		}
+	})
+
+	t.Run("The resumed session carries on", func(t *testing.T) {
+		guest.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 2, "y": 1},
+		})
+
+		msg := waitForMessage(t, owner)
+		if msg["type"] == "user-left" || msg["type"] == "user-joined" {
+			t.Fatalf("expected the reconnect to go unannounced got %v", msg["type"])
+		}
+		if msg["type"] != "state-delta" {
+			t.Fatalf("expected state-delta got %v", msg["type"])
+		}
+	})
+
+	t.Run("Leaving on purpose ends the session", func(t *testing.T) {
+		leave(guest)
+
+		if msg := waitForMessage(t, owner); msg["type"] != "user-left" {
+			t.Fatalf("expected user-left got %v", msg["type"])
+		}
+
+		_, msg := resume(t, token, lastSeq)
+		if msg["type"] != "error" {
+			t.Fatalf("expected error got %v", msg)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// joinOrRedirect asks the node at wsURL to join a space, returning the first
+// reply, which is either space-joined or a redirect.
+func joinOrRedirect(t *testing.T, wsURL, spaceId, token string, x, y int) (*websocket.Conn, map[string]any) {
+	ws, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatal("ws connection failed:", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+
+	ws.WriteJSON(map[string]any{
+		"type": "join",
+		"payload": map[string]any{
+			"spaceId":     spaceId,
+			"token":       token,
+			"spawnTicket": spawnTicket(t, spaceId, token, x, y),
+		},
+	})
+
+	return ws, waitForMessage(t, ws)
+}
+
+// TestSpaceSharding needs a second node sharing the database, both started
+// with WS_PUBLIC_URL set, e.g. SHARDED_WS_URL=ws://localhost:3002/.
+func TestSpaceSharding(t *testing.T) {
+	secondNode := os.Getenv("SHARDED_WS_URL")
+	if secondNode == "" {
+		t.Skip("SHARDED_WS_URL is not set")
+	}
+
+	firstNode := "ws://localhost:3001/"
+	_, token := signupAndSignin(t, randomUsername(), "user")
+
+	var spaceIds []string
+	for range 4 {
+		_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+			"name":       "Sharded",
+			"dimensions": "100x200",
+		}, token)
+		spaceIds = append(spaceIds, spaceData["spaceId"].(string))
+	}
+
+	for _, spaceId := range spaceIds {
+		t.Run("Joins end on the owning node", func(t *testing.T) {
+			first, msg := joinOrRedirect(t, firstNode, spaceId, token, 3, 4)
+			if msg["type"] == "space-joined" {
+				leave(first)
+				return
+			}
+			if msg["type"] != "redirect" {
+				t.Fatalf("expected space-joined or redirect got %v", msg)
+			}
+
+			redirect := msg["payload"].(map[string]any)
+			if redirect["spaceId"] != spaceId {
+				t.Fatalf("expected a redirect to %s got %v", spaceId, redirect["spaceId"])
+			}
+			ticket, _ := redirect["spawnTicket"].(string)
+			if ticket == "" {
+				t.Fatalf("expected the spawn ticket to be kept got %v", redirect)
+			}
+
+			owner, joined := joinSpaceOn(t, redirect["url"].(string), spaceId, token, ticket)
+			defer leave(owner)
+			if spawn := joined["spawn"].(map[string]any); spawn["x"] != float64(3) || spawn["y"] != float64(4) {
+				t.Fatalf("expected to spawn at 3,4 got %v", joined["spawn"])
+			}
+		})
+
+		t.Run("Every node agrees on the owner", func(t *testing.T) {
+			_, fromFirst := joinOrRedirect(t, firstNode, spaceId, token, 3, 4)
+			_, fromSecond := joinOrRedirect(t, secondNode, spaceId, token, 3, 4)
+
+			if fromFirst["type"] == fromSecond["type"] {
+				t.Fatalf("expected exactly one node to serve the space got %v and %v", fromFirst["type"], fromSecond["type"])
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// tryJoinSpace sends a join and returns the connection and its first reply.
+func tryJoinSpace(t *testing.T, spaceId, token, password string) (*websocket.Conn, map[string]any) {
+	u := url.URL{Scheme: "ws", Host: "localhost:3001", Path: "/"}
+
+	ws, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		t.Fatal("ws connection failed:", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+
+	ws.WriteJSON(map[string]any{
+		"type": "join",
+		"payload": map[string]any{
+			"spaceId":  spaceId,
+			"token":    token,
+			"password": password,
+		},
+	})
+
+	return ws, waitForMessage(t, ws)
+}
+
+func TestSpaceAccess(t *testing.T) {
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	guestId, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Private",
+		"dimensions": "100x200",
+	}, ownerToken)
+
+	spaceId := spaceData["spaceId"].(string)
+	spaceURL := BACKEND_URL + "/api/v1/space/" + spaceId
+
+	t.Run("Spaces are public by default", func(t *testing.T) {
+		resp, data := doRequest(t, "GET", spaceURL, nil, guestToken)
+		if resp.StatusCode != 200 || data["visibility"] != "public" {
+			t.Fatalf("expected public space got %d %v", resp.StatusCode, data["visibility"])
+		}
+	})
+
+	t.Run("Only the owner changes visibility", func(t *testing.T) {
+		resp, _ := doRequest(t, "PUT", spaceURL+"/visibility", map[string]any{
+			"visibility": "invite-only",
+		}, guestToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	doRequest(t, "PUT", spaceURL+"/visibility", map[string]any{
+		"visibility": "invite-only",
+	}, ownerToken)
+
+	t.Run("Invite-only space rejects non-members", func(t *testing.T) {
+		resp, _ := doRequest(t, "GET", spaceURL, nil, guestToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+
+		if _, msg := tryJoinSpace(t, spaceId, guestToken, ""); msg["type"] != "error" {
+			t.Fatalf("expected join to be refused got %v", msg["type"])
+		}
+	})
+
+	t.Run("Invitation with one use admits one user", func(t *testing.T) {
+		_, inv := doRequest(t, "POST", spaceURL+"/invitations", map[string]any{
+			"expiresIn": 3600,
+			"maxUses":   1,
+		}, ownerToken)
+
+		code := inv["code"].(string)
+
+		resp, _ := doRequest(t, "POST", BACKEND_URL+"/api/v1/space/invitations/"+code+"/accept", nil, guestToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		_, otherToken := signupAndSignin(t, randomUsername()+"-other", "user")
+		resp, _ = doRequest(t, "POST", BACKEND_URL+"/api/v1/space/invitations/"+code+"/accept", nil, otherToken)
+		if resp.StatusCode != 404 {
+			t.Fatalf("expected used-up invitation to be rejected got %d", resp.StatusCode)
+		}
+
+		resp, _ = doRequest(t, "GET", spaceURL, nil, guestToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected member to see space got %d", resp.StatusCode)
+		}
+
+		joinSpace(t, spaceId, guestToken).Close()
+	})
+
+	t.Run("Owner promotes and removes members", func(t *testing.T) {
+		guest := joinSpace(t, spaceId, guestToken)
+		defer guest.Close()
+
+		resp, member := doRequest(t, "PUT", spaceURL+"/members/"+guestId, map[string]any{
+			"role": "moderator",
+		}, ownerToken)
+		if resp.StatusCode != 200 || member["role"] != "moderator" {
+			t.Fatalf("expected moderator got %d %v", resp.StatusCode, member["role"])
+		}
+
+		resp, _ = doRequest(t, "DELETE", spaceURL+"/members/"+guestId, nil, ownerToken)
+		if resp.StatusCode != 204 {
+			t.Fatalf("expected 204 got %d", resp.StatusCode)
+		}
+
+		// The guest may no longer be in the invite-only space.
+		if msg := waitForMessage(t, guest); msg["type"] != "error" {
+			t.Fatalf("expected the removed member to be sent away got %v", msg["type"])
+		}
+	})
+
+	t.Run("Going invite-only sends non-members away", func(t *testing.T) {
+		doRequest(t, "PUT", spaceURL+"/visibility", map[string]any{
+			"visibility": "public",
+		}, ownerToken)
+		guest := joinSpace(t, spaceId, guestToken)
+		defer guest.Close()
+
+		doRequest(t, "PUT", spaceURL+"/visibility", map[string]any{
+			"visibility": "invite-only",
+		}, ownerToken)
+
+		if msg := waitForMessage(t, guest); msg["type"] != "error" {
+			t.Fatalf("expected the guest to be sent away got %v", msg["type"])
+		}
+	})
+
+	t.Run("Password space needs the password", func(t *testing.T) {
+		doRequest(t, "PUT", spaceURL+"/visibility", map[string]any{
+			"visibility": "password",
+			"password":   "open sesame",
+		}, ownerToken)
+
+		resp, _ := doRequest(t, "GET", spaceURL, nil, guestToken)
+		if resp.StatusCode != 401 {
+			t.Fatalf("expected 401 got %d", resp.StatusCode)
+		}
+
+		if _, msg := tryJoinSpace(t, spaceId, guestToken, "wrong"); msg["type"] != "error" {
+			t.Fatalf("expected wrong password to be refused got %v", msg["type"])
+		}
+
+		if _, msg := tryJoinSpace(t, spaceId, guestToken, "open sesame"); msg["type"] != "space-joined" {
+			t.Fatalf("expected space-joined got %v", msg["type"])
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestSpaceBundle(t *testing.T) {
+	_, adminToken := signupAndSignin(t, randomUsername()+"-admin", "admin")
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	_, otherToken := signupAndSignin(t, randomUsername()+"-other", "user")
+
+	_, el := doRequest(t, "POST", BACKEND_URL+"/api/v1/admin/element", map[string]any{
+		"imageUrl": "https://test.com/desk-" + randomUsername() + ".png",
+		"width":    2,
+		"height":   1,
+		"static":   true,
+	}, adminToken)
+	elementId := el["id"].(string)
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Office",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	for _, x := range []int{3, 7} {
+		doRequest(t, "POST", BACKEND_URL+"/api/v1/space/element", map[string]any{
+			"elementId": elementId,
+			"spaceId":   spaceId,
+			"x":         x,
+			"y":         4,
+		}, ownerToken)
+	}
+
+	doRequest(t, "POST", BACKEND_URL+"/api/v1/space/"+spaceId+"/zones", map[string]any{
+		"name": "Kitchen",
+		"rect": map[string]any{"x": 0, "y": 0, "width": 5, "height": 5},
+	}, ownerToken)
+
+	exportURL := BACKEND_URL + "/api/v1/space/" + spaceId + "/export"
+	importURL := BACKEND_URL + "/api/v1/space/import"
+
+	t.Run("Only the owner can export", func(t *testing.T) {
+		resp, _ := doRequest(t, "GET", exportURL, nil, otherToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	resp, bundle := doRequest(t, "GET", exportURL, nil, ownerToken)
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200 got %d", resp.StatusCode)
+	}
+
+	t.Run("Export holds the layout and its catalog", func(t *testing.T) {
+		if bundle["format"] != "metaverse-space" || bundle["version"] != 1.0 {
+			t.Fatalf("unexpected bundle header %v %v", bundle["format"], bundle["version"])
+		}
+		if n := len(bundle["elements"].([]any)); n != 1 {
+			t.Fatalf("expected 1 catalog element got %d", n)
+		}
+		if n := len(bundle["placements"].([]any)); n != 2 {
+			t.Fatalf("expected 2 placements got %d", n)
+		}
+		if n := len(bundle["zones"].([]any)); n != 1 {
+			t.Fatalf("expected 1 zone got %d", n)
+		}
+	})
+
+	t.Run("Import remaps ids and reuses identical elements", func(t *testing.T) {
+		resp, data := doRequest(t, "POST", importURL, bundle, otherToken)
+		if resp.StatusCode != 201 {
+			t.Fatalf("expected 201 got %d", resp.StatusCode)
+		}
+
+		if data["spaceId"] == spaceId {
+			t.Fatal("expected a new space id")
+		}
+
+		imported := data["elements"].([]any)[0].(map[string]any)
+		if imported["elementId"] != elementId || imported["deduplicated"] != true {
+			t.Fatalf("expected the catalog element to be reused got %v", imported)
+		}
+		if conflicts := data["conflicts"].([]any); len(conflicts) != 0 {
+			t.Fatalf("expected no conflicts got %v", conflicts)
+		}
+
+		_, copied := doRequest(t, "GET", BACKEND_URL+"/api/v1/space/"+data["spaceId"].(string)+"/export", nil, otherToken)
+		if n := len(copied["placements"].([]any)); n != 2 {
+			t.Fatalf("expected 2 placements got %d", n)
+		}
+	})
+
+	t.Run("Conflicting entries are skipped and reported", func(t *testing.T) {
+		placements := bundle["placements"].([]any)
+		bundle["placements"] = append(placements, map[string]any{
+			"id": "outside", "elementId": elementId, "x": 500, "y": 4,
+		})
+		bundle["elements"].([]any)[0].(map[string]any)["hash"] = "stale"
+
+		resp, data := doRequest(t, "POST", importURL, bundle, otherToken)
+		if resp.StatusCode != 201 {
+			t.Fatalf("expected 201 got %d", resp.StatusCode)
+		}
+
+		kinds := map[string]bool{}
+		for _, c := range data["conflicts"].([]any) {
+			kinds[c.(map[string]any)["kind"].(string)] = true
+		}
+		if !kinds["placement"] || !kinds["element-hash"] {
+			t.Fatalf("expected placement and element-hash conflicts got %v", data["conflicts"])
+		}
+	})
+
+	t.Run("Zip exports can be imported", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", exportURL+"?format=zip", nil)
+		req.Header.Set("Authorization", "Bearer "+ownerToken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		archive, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if ct := resp.Header.Get("Content-Type"); ct != "application/zip" {
+			t.Fatalf("expected application/zip got %s", ct)
+		}
+
+		req, _ = http.NewRequest("POST", importURL, bytes.NewReader(archive))
+		req.Header.Set("Content-Type", "application/zip")
+		req.Header.Set("Authorization", "Bearer "+otherToken)
+		resp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != 201 {
+			t.Fatalf("expected 201 got %d", resp.StatusCode)
+		}
+
+		var data map[string]any
+		json.NewDecoder(resp.Body).Decode(&data)
+		if data["name"] != "Office" {
+			t.Fatalf("expected the space name to carry over got %v", data["name"])
+		}
+	})
+
+	t.Run("Unknown bundle versions are rejected", func(t *testing.T) {
+		bundle["version"] = 99
+
+		resp, _ := doRequest(t, "POST", importURL, bundle, otherToken)
+		if resp.StatusCode != 400 {
+			t.Fatalf("expected 400 got %d", resp.StatusCode)
+		}
+	})
+}
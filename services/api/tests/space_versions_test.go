@@ -0,0 +1,224 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSpaceVersions(t *testing.T) {
+	_, adminToken := signupAndSignin(t, randomUsername()+"-admin", "admin")
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	_, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, el := doRequest(t, "POST", BACKEND_URL+"/api/v1/admin/element", map[string]any{
+		"imageUrl": "https://test.com/chair.png",
+		"width":    1,
+		"height":   1,
+		"static":   true,
+	}, adminToken)
+	elementId := el["id"].(string)
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Versioned",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+	versionsURL := BACKEND_URL + "/api/v1/space/" + spaceId + "/versions"
+
+	_, placed := doRequest(t, "POST", BACKEND_URL+"/api/v1/space/element", map[string]any{
+		"elementId": elementId,
+		"spaceId":   spaceId,
+		"x":         4,
+		"y":         4,
+	}, ownerToken)
+	placedId := placed["id"].(string)
+
+	doRequest(t, "DELETE", BACKEND_URL+"/api/v1/space/element", map[string]any{
+		"id": placedId,
+	}, ownerToken)
+
+	t.Run("Every change is recorded as a version", func(t *testing.T) {
+		resp, data := doRequest(t, "GET", versionsURL, nil, ownerToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		versions := data["versions"].([]any)
+		if len(versions) != 3 {
+			t.Fatalf("expected 3 versions got %d", len(versions))
+		}
+
+		latest := versions[0].(map[string]any)
+		if latest["version"] != 3.0 || latest["action"] != "delete-element" {
+			t.Fatalf("unexpected latest version %v", latest)
+		}
+
+		removed := latest["changes"].(map[string]any)["removed"].([]any)
+		if len(removed) != 1 || removed[0].(map[string]any)["id"] != placedId {
+			t.Fatalf("expected the element removed got %v", removed)
+		}
+	})
+
+	t.Run("Versions can be diffed", func(t *testing.T) {
+		resp, data := doRequest(t, "GET", versionsURL+"/diff?from=1&to=2", nil, ownerToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		if added := data["added"].([]any); len(added) != 1 {
+			t.Fatalf("expected 1 added element got %d", len(added))
+		}
+	})
+
+	t.Run("Only the owner sees the history", func(t *testing.T) {
+		resp, _ := doRequest(t, "GET", versionsURL, nil, guestToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Unknown versions are not found", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", versionsURL+"/99/rollback", nil, ownerToken)
+		if resp.StatusCode != 404 {
+			t.Fatalf("expected 404 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Rollback restores the layout and notifies the room", func(t *testing.T) {
+		guest := joinSpaceAt(t, spaceId, guestToken, 3, 4)
+
+		resp, data := doRequest(t, "POST", fmt.Sprintf("%s/2/rollback", versionsURL), nil, ownerToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+		if data["version"] != 4.0 {
+			t.Fatalf("expected version 4 got %v", data["version"])
+		}
+
+		msg := waitForMessage(t, guest)
+		if msg["type"] != "elements-changed" {
+			t.Fatalf("expected elements-changed got %v", msg["type"])
+		}
+		added := msg["payload"].(map[string]any)["added"].([]any)
+		if len(added) != 1 || added[0].(map[string]any)["id"] != placedId {
+			t.Fatalf("expected the element back got %v", added)
+		}
+
+		guest.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 4, "y": 4},
+		})
+		if msg := waitForMessage(t, guest); msg["type"] != "movement-rejected" {
+			t.Fatalf("expected movement-rejected got %v", msg["type"])
+		}
+
+		_, data = doRequest(t, "GET", versionsURL, nil, ownerToken)
+		latest := data["versions"].([]any)[0].(map[string]any)
+		if latest["action"] != "rollback" || latest["restoredVersion"] != 2.0 {
+			t.Fatalf("unexpected latest version %v", latest)
+		}
+	})
+
+	t.Run("Concurrent changes are recorded one version each", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(x int) {
+				defer wg.Done()
+				doRequest(t, "POST", BACKEND_URL+"/api/v1/space/element", map[string]any{
+					"elementId": elementId,
+					"spaceId":   spaceId,
+					"x":         20 + x,
+					"y":         20,
+				}, ownerToken)
+			}(i)
+		}
+		wg.Wait()
+
+		_, data := doRequest(t, "GET", versionsURL, nil, ownerToken)
+		versions := data["versions"].([]any)
+		if len(versions) != 9 {
+			t.Fatalf("expected 9 versions got %d", len(versions))
+		}
+
+		for _, v := range versions[:5] {
+			v := v.(map[string]any)
+			added := v["changes"].(map[string]any)["added"].([]any)
+			if v["action"] != "add-element" || len(added) != 1 {
+				t.Fatalf("expected one added element per version got %v", v)
+			}
+		}
+	})
+}
+
+// TestRollbackAfterPurge needs a server that purges the trash quickly, e.g.
+// TRASH_RETENTION=1s TRASH_PURGE_INTERVAL=1s, with the same values set here.
+func TestRollbackAfterPurge(t *testing.T) {
+	retention, _ := time.ParseDuration(os.Getenv("TRASH_RETENTION"))
+	interval, _ := time.ParseDuration(os.Getenv("TRASH_PURGE_INTERVAL"))
+	if retention <= 0 || interval <= 0 || retention+interval > time.Minute {
+		t.Skip("TRASH_RETENTION and TRASH_PURGE_INTERVAL are not set short")
+	}
+
+	_, adminToken := signupAndSignin(t, randomUsername()+"-admin", "admin")
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+
+	var elementIds []string
+	for range 2 {
+		_, el := doRequest(t, "POST", BACKEND_URL+"/api/v1/admin/element", map[string]any{
+			"imageUrl": "https://test.com/lamp.png",
+			"width":    1,
+			"height":   1,
+			"static":   true,
+		}, adminToken)
+		elementIds = append(elementIds, el["id"].(string))
+	}
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Purged",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+	versionsURL := BACKEND_URL + "/api/v1/space/" + spaceId + "/versions"
+
+	var placedIds []string
+	for i, elementId := range elementIds {
+		_, placed := doRequest(t, "POST", BACKEND_URL+"/api/v1/space/element", map[string]any{
+			"elementId": elementId,
+			"spaceId":   spaceId,
+			"x":         4 + i,
+			"y":         4,
+		}, ownerToken)
+		placedIds = append(placedIds, placed["id"].(string))
+	}
+
+	for _, placedId := range placedIds {
+		doRequest(t, "DELETE", BACKEND_URL+"/api/v1/space/element", map[string]any{
+			"id": placedId,
+		}, ownerToken)
+	}
+
+	resp, _ := doRequest(t, "DELETE", BACKEND_URL+"/api/v1/admin/element/"+elementIds[0], nil, adminToken)
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200 got %d", resp.StatusCode)
+	}
+	time.Sleep(retention + interval + time.Second)
+
+	resp, data := doRequest(t, "POST", versionsURL+"/3/rollback", nil, ownerToken)
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200 got %d", resp.StatusCode)
+	}
+	if data["version"] != 6.0 {
+		t.Fatalf("expected version 6 got %v", data["version"])
+	}
+
+	_, data = doRequest(t, "GET", versionsURL, nil, ownerToken)
+	latest := data["versions"].([]any)[0].(map[string]any)
+	added := latest["changes"].(map[string]any)["added"].([]any)
+	if len(added) != 1 || added[0].(map[string]any)["id"] != placedIds[1] {
+		t.Fatalf("expected only the live element back got %v", added)
+	}
+}
@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// waitForDelta waits for the next message, which must be a state delta, and
+// returns its payload.
+func waitForDelta(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+
+	msg := waitForMessage(t, conn)
+	if msg["type"] != "state-delta" {
+		t.Fatalf("expected state-delta got %v", msg["type"])
+	}
+	return msg["payload"].(map[string]any)
+}
+
+// deltaUsers returns the entries of one list of a state delta by user id.
+func deltaUsers(delta map[string]any, list string) map[string]map[string]any {
+	users := map[string]map[string]any{}
+
+	entries, _ := delta[list].([]any)
+	for _, e := range entries {
+		switch e := e.(type) {
+		case string:
+			users[e] = map[string]any{"userId": e}
+		case map[string]any:
+			users[e["userId"].(string)] = e
+		}
+	}
+	return users
+}
+
+func TestTickDeltas(t *testing.T) {
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	guestId, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Ticking",
+		"dimensions": "100x200",
+	}, ownerToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	owner := joinSpaceForDeltas(t, spaceId, ownerToken, 0, 0)
+	guest := joinSpaceForDeltas(t, spaceId, guestToken, 1, 1)
+	waitForMessage(t, owner) // guest's user-joined
+
+	t.Run("Moves are batched into one delta per tick", func(t *testing.T) {
+		for x := 2; x <= 4; x++ {
+			guest.WriteJSON(map[string]any{
+				"type":    "move",
+				"payload": map[string]any{"x": x, "y": 1},
+			})
+		}
+
+		// However the moves fall across ticks, each delta carries the
+		// guest once, at its latest position, until it stands at 4.
+		lastTick := 0.0
+		for {
+			delta := waitForDelta(t, owner)
+
+			tick := delta["tick"].(float64)
+			if tick <= lastTick {
+				t.Fatalf("expected ticks to increase got %v after %v", tick, lastTick)
+			}
+			lastTick = tick
+
+			moved := delta["moved"].([]any)
+			if len(moved) != 1 {
+				t.Fatalf("expected one moved user got %v", moved)
+			}
+
+			at := deltaUsers(delta, "moved")[guestId]
+			if at == nil {
+				t.Fatalf("expected the guest to move got %v", moved)
+			}
+			if at["x"] == 4.0 {
+				break
+			}
+		}
+	})
+
+	t.Run("The mover has its own moves confirmed", func(t *testing.T) {
+		for {
+			at := deltaUsers(waitForDelta(t, guest), "moved")[guestId]
+			if at == nil {
+				t.Fatal("expected the guest's own move")
+			}
+			if at["x"] == 4.0 {
+				break
+			}
+		}
+	})
+
+	t.Run("Others only see the users that moved", func(t *testing.T) {
+		owner.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 0, "y": 1},
+		})
+
+		delta := waitForDelta(t, guest)
+		if deltaUsers(delta, "moved")[guestId] != nil {
+			t.Fatalf("expected only the owner to move got %v", delta)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const tiledJSONMap = `{
+  "orientation": "orthogonal", "infinite": false,
+  "width": 10, "height": 8, "tilewidth": 32, "tileheight": 32,
+  "properties": [{"name": "name", "type": "string", "value": "Lobby"}],
+  "tilesets": [
+    {"firstgid": 1, "name": "walls", "tilewidth": 32, "tileheight": 32, "tilecount": 4, "columns": 2,
+     "image": "walls.png", "imagewidth": 64, "imageheight": 64},
+    {"firstgid": 5, "name": "props", "tilewidth": 64, "tileheight": 32, "tilecount": 1, "columns": 0,
+     "tiles": [{"id": 0, "image": "desk.png", "imagewidth": 64, "imageheight": 32,
+                "properties": [{"name": "static", "type": "bool", "value": true}]}]}
+  ],
+  "layers": [
+    {"type": "tilelayer", "name": "collision", "width": 10, "height": 8, "data": [
+      1,1,1,1,1,1,1,1,1,1,
+      0,0,0,0,0,0,0,0,0,0,
+      0,0,0,0,0,0,0,0,0,0,
+      0,0,0,0,0,0,0,0,0,0,
+      0,0,0,0,0,0,0,0,0,0,
+      0,0,0,0,0,0,0,0,0,0,
+      0,0,0,0,0,0,0,0,0,0,
+      0,0,0,0,0,0,0,0,0,0]},
+    {"type": "objectgroup", "name": "furniture", "objects": [
+      {"id": 1, "gid": 5, "x": 64, "y": 128, "width": 64, "height": 32},
+      {"id": 2, "gid": 99, "x": 0, "y": 64, "width": 32, "height": 32},
+      {"id": 3, "name": "Kitchen", "x": 192, "y": 64, "width": 96, "height": 96,
+       "properties": [{"name": "chatScope", "type": "string", "value": "zone"}]}
+    ]}
+  ]
+}`
+
+const tiledTMXMap = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="4" height="4" tilewidth="16" tileheight="16" infinite="0">
+ <tileset firstgid="1" name="walls" tilewidth="16" tileheight="16" tilecount="1" columns="1">
+  <image source="wall.png" width="16" height="16"/>
+ </tileset>
+ <layer name="blocked" width="4" height="4">
+  <data encoding="csv">
+1,0,0,0,
+1,0,0,0,
+0,0,0,0,
+0,0,0,0
+</data>
+ </layer>
+</map>`
+
+func postTiled(t *testing.T, url, body, token string) (*http.Response, map[string]any) {
+	req, err := http.NewRequest("POST", url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	var result map[string]any
+	json.Unmarshal(respBody, &result)
+
+	return resp, result
+}
+
+func TestTiledImport(t *testing.T) {
+	_, token := signupAndSignin(t, randomUsername(), "user")
+	importURL := BACKEND_URL + "/api/v1/space/import/tiled"
+
+	t.Run("JSON maps become spaces", func(t *testing.T) {
+		resp, data := postTiled(t, importURL+"?assetBaseUrl=https://cdn.test/"+randomUsername()+"/", tiledJSONMap, token)
+		if resp.StatusCode != 201 {
+			t.Fatalf("expected 201 got %d", resp.StatusCode)
+		}
+		if data["name"] != "Lobby" {
+			t.Fatalf("expected the map name got %v", data["name"])
+		}
+
+		conflicts := data["conflicts"].([]any)
+		if len(conflicts) != 1 || conflicts[0].(map[string]any)["id"] != "object:2" {
+			t.Fatalf("expected the unknown gid reported got %v", conflicts)
+		}
+
+		_, bundle := doRequest(t, "GET", BACKEND_URL+"/api/v1/space/"+data["spaceId"].(string)+"/export", nil, token)
+
+		space := bundle["space"].(map[string]any)
+		if space["width"] != 10.0 || space["height"] != 8.0 {
+			t.Fatalf("expected a 10x8 space got %v", space)
+		}
+		if n := len(bundle["placements"].([]any)); n != 11 {
+			t.Fatalf("expected 10 wall tiles and a desk got %d placements", n)
+		}
+		if n := len(bundle["elements"].([]any)); n != 2 {
+			t.Fatalf("expected 2 catalog elements got %d", n)
+		}
+
+		zones := bundle["zones"].([]any)
+		if len(zones) != 1 || zones[0].(map[string]any)["chatScope"] != "zone" {
+			t.Fatalf("expected the kitchen zone got %v", zones)
+		}
+	})
+
+	t.Run("Collision is derived from the named layer", func(t *testing.T) {
+		resp, data := postTiled(t, importURL+"?collisionLayer=blocked&name=Corridor", tiledTMXMap, token)
+		if resp.StatusCode != 201 {
+			t.Fatalf("expected 201 got %d", resp.StatusCode)
+		}
+		spaceId := data["spaceId"].(string)
+
+		conn := joinSpaceAt(t, spaceId, token, 1, 1)
+		conn.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 0, "y": 1},
+		})
+
+		if msg := waitForMessage(t, conn); msg["type"] != "movement-rejected" {
+			t.Fatalf("expected movement-rejected got %v", msg["type"])
+		}
+	})
+
+	t.Run("A missing collision layer is reported", func(t *testing.T) {
+		_, data := postTiled(t, importURL, tiledTMXMap, token)
+
+		conflicts := data["conflicts"].([]any)
+		if len(conflicts) != 1 || conflicts[0].(map[string]any)["kind"] != "collision" {
+			t.Fatalf("expected a collision conflict got %v", conflicts)
+		}
+	})
+
+	t.Run("Unsupported maps are rejected", func(t *testing.T) {
+		isometric := strings.Replace(tiledTMXMap, `orientation="orthogonal"`, `orientation="isometric"`, 1)
+
+		resp, _ := postTiled(t, importURL, isometric, token)
+		if resp.StatusCode != 400 {
+			t.Fatalf("expected 400 got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package tests
+
+import "testing"
+
+func TestSpaceTrash(t *testing.T) {
+	_, token := signupAndSignin(t, randomUsername(), "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Test",
+		"dimensions": "100x200",
+	}, token)
+
+	spaceId := spaceData["spaceId"].(string)
+
+	resp, _ := doRequest(t, "DELETE", BACKEND_URL+"/api/v1/space/"+spaceId, nil, token)
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200 got %d", resp.StatusCode)
+	}
+
+	t.Run("Deleted space is listed in the trash", func(t *testing.T) {
+		_, data := doRequest(t, "GET", BACKEND_URL+"/api/v1/space/trash", nil, token)
+
+		spaces := data["spaces"].([]any)
+		if len(spaces) != 1 || spaces[0].(map[string]any)["id"] != spaceId {
+			t.Fatal("expected deleted space in trash")
+		}
+	})
+
+	t.Run("Deleting twice is not found", func(t *testing.T) {
+		resp, _ := doRequest(t, "DELETE", BACKEND_URL+"/api/v1/space/"+spaceId, nil, token)
+		if resp.StatusCode != 404 {
+			t.Fatalf("expected 404 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Only the owner can restore", func(t *testing.T) {
+		_, otherToken := signupAndSignin(t, randomUsername()+"-other", "user")
+
+		resp, _ := doRequest(t, "POST", BACKEND_URL+"/api/v1/space/"+spaceId+"/restore", nil, otherToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Restore empties the trash", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", BACKEND_URL+"/api/v1/space/"+spaceId+"/restore", nil, token)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		_, data := doRequest(t, "GET", BACKEND_URL+"/api/v1/space/trash", nil, token)
+		if len(data["spaces"].([]any)) != 0 {
+			t.Fatal("expected empty trash")
+		}
+	})
+
+	t.Run("Deleting sends everyone away", func(t *testing.T) {
+		ws := joinSpace(t, spaceId, token)
+		defer ws.Close()
+
+		resp, _ := doRequest(t, "DELETE", BACKEND_URL+"/api/v1/space/"+spaceId, nil, token)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		if msg := waitForMessage(t, ws); msg["type"] != "error" {
+			t.Fatalf("expected the user to be sent away got %v", msg["type"])
+		}
+	})
+}
+
+func TestElementTrash(t *testing.T) {
+	_, adminToken := signupAndSignin(t, randomUsername(), "admin")
+	_, userToken := signupAndSignin(t, randomUsername()+"-user", "user")
+
+	_, el := doRequest(t, "POST", BACKEND_URL+"/api/v1/admin/element", map[string]any{
+		"imageUrl": "https://test.com/a.png",
+		"width":    1,
+		"height":   1,
+		"static":   true,
+	}, adminToken)
+
+	elementId := el["id"].(string)
+
+	t.Run("User cannot delete catalog elements", func(t *testing.T) {
+		resp, _ := doRequest(t, "DELETE", BACKEND_URL+"/api/v1/admin/element/"+elementId, nil, userToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Admin deletes and restores element", func(t *testing.T) {
+		resp, _ := doRequest(t, "DELETE", BACKEND_URL+"/api/v1/admin/element/"+elementId, nil, adminToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+			"name":       "Test",
+			"dimensions": "100x200",
+		}, userToken)
+		resp, _ = doRequest(t, "POST", BACKEND_URL+"/api/v1/space/element", map[string]any{
+			"elementId": elementId,
+			"spaceId":   spaceData["spaceId"],
+			"x":         10,
+			"y":         10,
+		}, userToken)
+		if resp.StatusCode != 404 {
+			t.Fatalf("expected a trashed element not to be placed got %d", resp.StatusCode)
+		}
+
+		resp, _ = doRequest(t, "POST", BACKEND_URL+"/api/v1/admin/element/"+elementId+"/restore", nil, adminToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestUserTrash(t *testing.T) {
+	_, adminToken := signupAndSignin(t, randomUsername(), "admin")
+	userId, userToken := signupAndSignin(t, randomUsername()+"-user", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Test",
+		"dimensions": "100x200",
+	}, adminToken)
+	spaceId := spaceData["spaceId"].(string)
+
+	resp, _ := doRequest(t, "DELETE", BACKEND_URL+"/api/v1/admin/user/"+userId, nil, adminToken)
+	if resp.StatusCode != 204 {
+		t.Fatalf("expected 204 got %d", resp.StatusCode)
+	}
+
+	t.Run("Deleted users' tokens are refused", func(t *testing.T) {
+		resp, _ := doRequest(t, "GET", BACKEND_URL+"/api/v1/space/"+spaceId, nil, userToken)
+		if resp.StatusCode != 401 {
+			t.Fatalf("expected 401 got %d", resp.StatusCode)
+		}
+
+		if _, msg := tryJoinSpace(t, spaceId, userToken, ""); msg["type"] != "error" {
+			t.Fatalf("expected join to be refused got %v", msg["type"])
+		}
+	})
+
+	t.Run("Restored users' tokens work again", func(t *testing.T) {
+		doRequest(t, "POST", BACKEND_URL+"/api/v1/admin/user/"+userId+"/restore", nil, adminToken)
+
+		resp, _ := doRequest(t, "GET", BACKEND_URL+"/api/v1/space/"+spaceId, nil, userToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+	})
+}
@@ -9,17 +9,23 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+// waitForMessage returns the next message, skipping the latency reports
+// the server sends on its own schedule.
 func waitForMessage(t *testing.T, conn *websocket.Conn) map[string]interface{} {
 	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
 
-	_, msg, err := conn.ReadMessage()
-	if err != nil {
-		t.Fatal("failed to read websocket message:", err)
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal("failed to read websocket message:", err)
+		}
+
+		var data map[string]interface{}
+		json.Unmarshal(msg, &data)
+		if data["type"] != "latency" {
+			return data
+		}
 	}
-
-	var data map[string]interface{}
-	json.Unmarshal(msg, &data)
-	return data
 }
 
 func TestWebsocketFlow(t *testing.T) {
@@ -189,7 +195,7 @@ func TestWebsocketFlow(t *testing.T) {
 	//////////////// USER LEAVE ////////////////////////
 	////////////////////////////////////////////////////
 
-	ws1.Close()
+	leave(ws1)
 
 	leaveMsg := waitForMessage(t, ws2)
 
@@ -0,0 +1,195 @@
+package tests
+
+import (
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/vaxxnsh/metaverse/api/internal/middleware"
+	"github.com/vaxxnsh/metaverse/api/internal/realtime"
+)
+
+// spawnTicket issues the ticket the server would hand the user of token to
+// join spaceId at x, y, signed with the server's JWT_SECRET.
+func spawnTicket(t *testing.T, spaceId, token string, x, y int) string {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "supersecret"
+	}
+
+	claims, err := middleware.ParseToken(secret, token)
+	if err != nil {
+		t.Fatal("parse token:", err)
+	}
+	return realtime.SignSpawn(secret, claims.UserID, spaceId, x, y)
+}
+
+func joinSpaceAt(t *testing.T, spaceId, token string, x, y int) *websocket.Conn {
+	return joinSpaceWith(t, map[string]any{
+		"spaceId":     spaceId,
+		"token":       token,
+		"spawnTicket": spawnTicket(t, spaceId, token, x, y),
+	})
+}
+
+// joinSpaceForDeltas is joinSpaceAt for a client that asks for state
+// deltas.
+func joinSpaceForDeltas(t *testing.T, spaceId, token string, x, y int) *websocket.Conn {
+	return joinSpaceWith(t, map[string]any{
+		"spaceId":     spaceId,
+		"token":       token,
+		"spawnTicket": spawnTicket(t, spaceId, token, x, y),
+		"stateDeltas": true,
+	})
+}
+
+func joinSpaceWith(t *testing.T, payload map[string]any) *websocket.Conn {
+	u := url.URL{Scheme: "ws", Host: "localhost:3001", Path: "/"}
+
+	ws, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		t.Fatal("ws connection failed:", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+
+	ws.WriteJSON(map[string]any{"type": "join", "payload": payload})
+
+	if msg := waitForMessage(t, ws); msg["type"] != "space-joined" {
+		t.Fatalf("expected space-joined got %v", msg["type"])
+	}
+
+	return ws
+}
+
+func TestSpaceZones(t *testing.T) {
+	_, ownerToken := signupAndSignin(t, randomUsername(), "user")
+	_, guestToken := signupAndSignin(t, randomUsername()+"-guest", "user")
+
+	_, spaceData := doRequest(t, "POST", BACKEND_URL+"/api/v1/space", map[string]any{
+		"name":       "Zoned",
+		"dimensions": "100x200",
+	}, ownerToken)
+
+	spaceId := spaceData["spaceId"].(string)
+	zonesURL := BACKEND_URL + "/api/v1/space/" + spaceId + "/zones"
+
+	resp, data := doRequest(t, "POST", zonesURL, map[string]any{
+		"name":       "Meeting room",
+		"rect":       map[string]any{"x": 10, "y": 10, "width": 5, "height": 5},
+		"chatScope":  "zone",
+		"audio":      "zone",
+		"properties": map[string]any{"topic": "standup"},
+	}, ownerToken)
+	if resp.StatusCode != 201 {
+		t.Fatalf("expected 201 got %d", resp.StatusCode)
+	}
+	meetingId := data["id"].(string)
+
+	resp, _ = doRequest(t, "POST", zonesURL, map[string]any{
+		"name":    "Members only",
+		"polygon": []map[string]any{{"x": 50, "y": 50}, {"x": 60, "y": 50}, {"x": 55, "y": 60}},
+		"minRole": "member",
+	}, ownerToken)
+	if resp.StatusCode != 201 {
+		t.Fatalf("expected 201 got %d", resp.StatusCode)
+	}
+
+	t.Run("Only managers can create zones", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", zonesURL, map[string]any{
+			"name": "Mine",
+			"rect": map[string]any{"x": 0, "y": 0, "width": 1, "height": 1},
+		}, guestToken)
+		if resp.StatusCode != 403 {
+			t.Fatalf("expected 403 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Invalid zones are rejected", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", zonesURL, map[string]any{
+			"name": "Outside",
+			"rect": map[string]any{"x": 90, "y": 190, "width": 50, "height": 50},
+		}, ownerToken)
+		if resp.StatusCode != 400 {
+			t.Fatalf("expected 400 got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Zones are listed", func(t *testing.T) {
+		_, data := doRequest(t, "GET", zonesURL, nil, guestToken)
+		if zones := data["zones"].([]any); len(zones) != 2 {
+			t.Fatalf("expected 2 zones got %d", len(zones))
+		}
+	})
+
+	guest := joinSpaceAt(t, spaceId, guestToken, 9, 12)
+
+	t.Run("Entering a zone sends zone-entered", func(t *testing.T) {
+		guest.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 10, "y": 12},
+		})
+
+		msg := waitForMessage(t, guest)
+		if msg["type"] != "zone-entered" {
+			t.Fatalf("expected zone-entered got %v", msg["type"])
+		}
+
+		payload := msg["payload"].(map[string]any)
+		if payload["zoneId"] != meetingId || payload["audio"] != "zone" {
+			t.Fatalf("unexpected zone payload %v", payload)
+		}
+	})
+
+	t.Run("Restricted zones reject movement", func(t *testing.T) {
+		_, strangerToken := signupAndSignin(t, randomUsername()+"-stranger", "user")
+		stranger := joinSpaceAt(t, spaceId, strangerToken, 55, 49)
+
+		stranger.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 55, "y": 50},
+		})
+
+		if msg := waitForMessage(t, stranger); msg["type"] != "movement-rejected" {
+			t.Fatalf("expected movement-rejected got %v", msg["type"])
+		}
+	})
+
+	t.Run("New members enter restricted zones at once", func(t *testing.T) {
+		memberId, memberToken := signupAndSignin(t, randomUsername()+"-member", "user")
+		member := joinSpaceAt(t, spaceId, memberToken, 56, 49)
+
+		resp, _ := doRequest(t, "PUT", BACKEND_URL+"/api/v1/space/"+spaceId+"/members/"+memberId, map[string]any{
+			"role": "member",
+		}, ownerToken)
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected 200 got %d", resp.StatusCode)
+		}
+
+		member.WriteJSON(map[string]any{
+			"type":    "move",
+			"payload": map[string]any{"x": 56, "y": 50},
+		})
+
+		if msg := waitForMessage(t, member); msg["type"] != "zone-entered" {
+			t.Fatalf("expected zone-entered got %v", msg["type"])
+		}
+	})
+
+	t.Run("Deleting a zone sends zone-left", func(t *testing.T) {
+		resp, _ := doRequest(t, "DELETE", zonesURL+"/"+meetingId, nil, ownerToken)
+		if resp.StatusCode != 204 {
+			t.Fatalf("expected 204 got %d", resp.StatusCode)
+		}
+
+		// The guest sees the whole room, so the users who joined since
+		// come first.
+		msg := waitForMessage(t, guest)
+		for msg["type"] == "user-joined" {
+			msg = waitForMessage(t, guest)
+		}
+		if msg["type"] != "zone-left" || msg["payload"].(map[string]any)["zoneId"] != meetingId {
+			t.Fatalf("expected zone-left got %v", msg)
+		}
+	})
+}
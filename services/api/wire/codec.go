@@ -0,0 +1,174 @@
+// Package wire encodes the messages of the real-time server for either of
+// the WebSocket subprotocols it speaks. It is shared by the server and Go
+// clients.
+package wire
+
+//go:generate protoc -I ../proto --go_out=.. --go_opt=module=github.com/vaxxnsh/metaverse/api realtime/v1/realtime.proto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vaxxnsh/metaverse/api/wire/realtimev1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	SubprotocolJSON     = "metaverse.v1.json"
+	SubprotocolProtobuf = "metaverse.v1.protobuf"
+)
+
+// Subprotocols are the subprotocols the server speaks, most preferred
+// first.
+var Subprotocols = []string{SubprotocolProtobuf, SubprotocolJSON}
+
+var ErrUnknownType = errors.New("unknown message type")
+
+// Message is a message in either direction, whatever its encoding on the
+// wire. Payload is always JSON. Seq numbers the events a server sends in a
+// session, and is 0 for anything else.
+type Message struct {
+	Type    string          `json:"type"`
+	Seq     uint64          `json:"seq,omitempty"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Codec turns messages into frames and back for one subprotocol.
+type Codec interface {
+	Subprotocol() string
+
+	// Binary reports whether frames are sent as binary rather than text
+	// messages.
+	Binary() bool
+
+	Encode(m Message) ([]byte, error)
+	Decode(frame []byte) (Message, error)
+}
+
+// ServerCodec returns the codec a server uses on a connection that
+// negotiated subprotocol. Connections that negotiated none, like every
+// client written before negotiation, speak JSON.
+func ServerCodec(subprotocol string) Codec {
+	if subprotocol == SubprotocolProtobuf {
+		return &protoCodec{send: serverEvent, receive: clientMessage}
+	}
+	return jsonCodec{}
+}
+
+// ClientCodec returns the codec a client uses once the server has accepted
+// subprotocol.
+func ClientCodec(subprotocol string) Codec {
+	if subprotocol == SubprotocolProtobuf {
+		return &protoCodec{send: clientMessage, receive: serverEvent}
+	}
+	return jsonCodec{}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Subprotocol() string { return SubprotocolJSON }
+
+func (jsonCodec) Binary() bool { return false }
+
+func (jsonCodec) Encode(m Message) ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (jsonCodec) Decode(frame []byte) (Message, error) {
+	var m Message
+	err := json.Unmarshal(frame, &m)
+	return m, err
+}
+
+func clientMessage() protoreflect.Message {
+	return (&realtimev1.ClientMessage{}).ProtoReflect()
+}
+
+func serverEvent() protoreflect.Message {
+	return (&realtimev1.ServerEvent{}).ProtoReflect()
+}
+
+// protoCodec sends and receives envelopes whose oneof field names the
+// message type. Payloads are carried over through their JSON mapping, which
+// matches the JSON protocol field for field.
+type protoCodec struct {
+	send    func() protoreflect.Message
+	receive func() protoreflect.Message
+}
+
+// seqField is the envelope field that carries Message.Seq, which only
+// server events have.
+const seqField = "seq"
+
+// payloadJSON writes every field, so that zeros and empty lists read the
+// same as in the JSON protocol.
+var payloadJSON = protojson.MarshalOptions{EmitUnpopulated: true}
+
+func (*protoCodec) Subprotocol() string { return SubprotocolProtobuf }
+
+func (*protoCodec) Binary() bool { return true }
+
+func (c *protoCodec) Encode(m Message) ([]byte, error) {
+	env := c.send()
+
+	fd := payloadField(env.Descriptor(), m.Type)
+	if fd == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownType, m.Type)
+	}
+
+	payload := env.NewField(fd).Message()
+	if len(m.Payload) > 0 {
+		if err := protojson.Unmarshal(m.Payload, payload.Interface()); err != nil {
+			return nil, fmt.Errorf("encode %s payload: %w", m.Type, err)
+		}
+	}
+	env.Set(fd, protoreflect.ValueOfMessage(payload))
+
+	if seq := env.Descriptor().Fields().ByName(seqField); seq != nil && m.Seq > 0 {
+		env.Set(seq, protoreflect.ValueOfUint64(m.Seq))
+	}
+
+	return proto.Marshal(env.Interface())
+}
+
+func (c *protoCodec) Decode(frame []byte) (Message, error) {
+	env := c.receive()
+	if err := proto.Unmarshal(frame, env.Interface()); err != nil {
+		return Message{}, err
+	}
+
+	// A payload from a newer schema is kept as an unknown field, which
+	// leaves the oneof empty.
+	fd := env.WhichOneof(env.Descriptor().Oneofs().Get(0))
+	if fd == nil {
+		return Message{}, ErrUnknownType
+	}
+
+	payload, err := payloadJSON.Marshal(env.Get(fd).Message().Interface())
+	if err != nil {
+		return Message{}, err
+	}
+
+	m := Message{
+		Type:    strings.ReplaceAll(string(fd.Name()), "_", "-"),
+		Payload: payload,
+	}
+	if seq := env.Descriptor().Fields().ByName(seqField); seq != nil {
+		m.Seq = env.Get(seq).Uint()
+	}
+	return m, nil
+}
+
+// payloadField returns the oneof field of an envelope that carries msgType,
+// or nil.
+func payloadField(env protoreflect.MessageDescriptor, msgType string) protoreflect.FieldDescriptor {
+	fd := env.Fields().ByName(protoreflect.Name(strings.ReplaceAll(msgType, "-", "_")))
+	if fd == nil || fd.ContainingOneof() == nil {
+		return nil
+	}
+	return fd
+}
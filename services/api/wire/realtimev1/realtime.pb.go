@@ -0,0 +1,2743 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: realtime/v1/realtime.proto
+
+// The binary wire format of the real-time server, spoken by clients that
+// negotiate the metaverse.v1.protobuf WebSocket subprotocol.
+//
+// Every frame carries one ClientMessage or ServerEvent. The field set in
+// its oneof is the message type of the JSON protocol with dashes written as
+// underscores, and the fields of that message are the JSON payload's in
+// snake case, so both protocols describe the same messages.
+//
+// Fields may be added but never renumbered; anything else needs a new
+// version of the package and the subprotocol.
+
+package realtimev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Point struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int32                  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Point) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Point) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type UserPosition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	X             int32                  `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,3,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserPosition) Reset() {
+	*x = UserPosition{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserPosition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserPosition) ProtoMessage() {}
+
+func (x *UserPosition) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserPosition.ProtoReflect.Descriptor instead.
+func (*UserPosition) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UserPosition) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserPosition) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *UserPosition) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+// ClientMessage is a frame sent by a client.
+type ClientMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ClientMessage_Join
+	//	*ClientMessage_Move
+	//	*ClientMessage_MoveTo
+	//	*ClientMessage_Chat
+	//	*ClientMessage_Emote
+	//	*ClientMessage_DirectMessage
+	//	*ClientMessage_Kick
+	//	*ClientMessage_Mute
+	//	*ClientMessage_Ban
+	//	*ClientMessage_Resume
+	Payload       isClientMessage_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientMessage) Reset() {
+	*x = ClientMessage{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientMessage) ProtoMessage() {}
+
+func (x *ClientMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientMessage.ProtoReflect.Descriptor instead.
+func (*ClientMessage) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ClientMessage) GetPayload() isClientMessage_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetJoin() *Join {
+	if x != nil {
+		if x, ok := x.Payload.(*ClientMessage_Join); ok {
+			return x.Join
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetMove() *Move {
+	if x != nil {
+		if x, ok := x.Payload.(*ClientMessage_Move); ok {
+			return x.Move
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetMoveTo() *Move {
+	if x != nil {
+		if x, ok := x.Payload.(*ClientMessage_MoveTo); ok {
+			return x.MoveTo
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetChat() *Chat {
+	if x != nil {
+		if x, ok := x.Payload.(*ClientMessage_Chat); ok {
+			return x.Chat
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetEmote() *Emote {
+	if x != nil {
+		if x, ok := x.Payload.(*ClientMessage_Emote); ok {
+			return x.Emote
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetDirectMessage() *DirectMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*ClientMessage_DirectMessage); ok {
+			return x.DirectMessage
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetKick() *Moderation {
+	if x != nil {
+		if x, ok := x.Payload.(*ClientMessage_Kick); ok {
+			return x.Kick
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetMute() *Moderation {
+	if x != nil {
+		if x, ok := x.Payload.(*ClientMessage_Mute); ok {
+			return x.Mute
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetBan() *Moderation {
+	if x != nil {
+		if x, ok := x.Payload.(*ClientMessage_Ban); ok {
+			return x.Ban
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetResume() *Resume {
+	if x != nil {
+		if x, ok := x.Payload.(*ClientMessage_Resume); ok {
+			return x.Resume
+		}
+	}
+	return nil
+}
+
+type isClientMessage_Payload interface {
+	isClientMessage_Payload()
+}
+
+type ClientMessage_Join struct {
+	Join *Join `protobuf:"bytes,1,opt,name=join,proto3,oneof"`
+}
+
+type ClientMessage_Move struct {
+	Move *Move `protobuf:"bytes,2,opt,name=move,proto3,oneof"`
+}
+
+type ClientMessage_MoveTo struct {
+	MoveTo *Move `protobuf:"bytes,3,opt,name=move_to,json=moveTo,proto3,oneof"`
+}
+
+type ClientMessage_Chat struct {
+	Chat *Chat `protobuf:"bytes,4,opt,name=chat,proto3,oneof"`
+}
+
+type ClientMessage_Emote struct {
+	Emote *Emote `protobuf:"bytes,5,opt,name=emote,proto3,oneof"`
+}
+
+type ClientMessage_DirectMessage struct {
+	DirectMessage *DirectMessage `protobuf:"bytes,6,opt,name=direct_message,json=directMessage,proto3,oneof"`
+}
+
+type ClientMessage_Kick struct {
+	Kick *Moderation `protobuf:"bytes,7,opt,name=kick,proto3,oneof"`
+}
+
+type ClientMessage_Mute struct {
+	Mute *Moderation `protobuf:"bytes,8,opt,name=mute,proto3,oneof"`
+}
+
+type ClientMessage_Ban struct {
+	Ban *Moderation `protobuf:"bytes,9,opt,name=ban,proto3,oneof"`
+}
+
+type ClientMessage_Resume struct {
+	Resume *Resume `protobuf:"bytes,10,opt,name=resume,proto3,oneof"`
+}
+
+func (*ClientMessage_Join) isClientMessage_Payload() {}
+
+func (*ClientMessage_Move) isClientMessage_Payload() {}
+
+func (*ClientMessage_MoveTo) isClientMessage_Payload() {}
+
+func (*ClientMessage_Chat) isClientMessage_Payload() {}
+
+func (*ClientMessage_Emote) isClientMessage_Payload() {}
+
+func (*ClientMessage_DirectMessage) isClientMessage_Payload() {}
+
+func (*ClientMessage_Kick) isClientMessage_Payload() {}
+
+func (*ClientMessage_Mute) isClientMessage_Payload() {}
+
+func (*ClientMessage_Ban) isClientMessage_Payload() {}
+
+func (*ClientMessage_Resume) isClientMessage_Payload() {}
+
+type Join struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId string                 `protobuf:"bytes,1,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	Token   string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	// Only needed for password-protected spaces the user is not a member of.
+	Password string `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	// Asks for a StateDelta per tick in place of the movement, enter_view
+	// and leave_view events of each user.
+	StateDeltas bool `protobuf:"varint,5,opt,name=state_deltas,json=stateDeltas,proto3" json:"state_deltas,omitempty"`
+	// An optional spawn ticket the server issued, e.g. for joining a friend
+	// or in a Redirect.
+	SpawnTicket   string `protobuf:"bytes,6,opt,name=spawn_ticket,json=spawnTicket,proto3" json:"spawn_ticket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Join) Reset() {
+	*x = Join{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Join) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Join) ProtoMessage() {}
+
+func (x *Join) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Join.ProtoReflect.Descriptor instead.
+func (*Join) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Join) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *Join) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *Join) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *Join) GetStateDeltas() bool {
+	if x != nil {
+		return x.StateDeltas
+	}
+	return false
+}
+
+func (x *Join) GetSpawnTicket() string {
+	if x != nil {
+		return x.SpawnTicket
+	}
+	return ""
+}
+
+// Resume takes over the session the token was issued for, replaying the
+// events sent after last_seq. It is 32 bits for the same reason as
+// StateDelta.tick.
+type Resume struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	LastSeq       uint32                 `protobuf:"varint,2,opt,name=last_seq,json=lastSeq,proto3" json:"last_seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Resume) Reset() {
+	*x = Resume{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resume) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resume) ProtoMessage() {}
+
+func (x *Resume) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resume.ProtoReflect.Descriptor instead.
+func (*Resume) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Resume) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *Resume) GetLastSeq() uint32 {
+	if x != nil {
+		return x.LastSeq
+	}
+	return 0
+}
+
+type Move struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int32                  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Move) Reset() {
+	*x = Move{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Move) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Move) ProtoMessage() {}
+
+func (x *Move) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Move.ProtoReflect.Descriptor instead.
+func (*Move) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Move) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Move) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type Chat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Chat) Reset() {
+	*x = Chat{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Chat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chat) ProtoMessage() {}
+
+func (x *Chat) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chat.ProtoReflect.Descriptor instead.
+func (*Chat) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Chat) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type Emote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Emote         string                 `protobuf:"bytes,1,opt,name=emote,proto3" json:"emote,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Emote) Reset() {
+	*x = Emote{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Emote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Emote) ProtoMessage() {}
+
+func (x *Emote) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Emote.ProtoReflect.Descriptor instead.
+func (*Emote) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Emote) GetEmote() string {
+	if x != nil {
+		return x.Emote
+	}
+	return ""
+}
+
+type DirectMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DirectMessage) Reset() {
+	*x = DirectMessage{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DirectMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirectMessage) ProtoMessage() {}
+
+func (x *DirectMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirectMessage.ProtoReflect.Descriptor instead.
+func (*DirectMessage) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DirectMessage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DirectMessage) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// Moderation is a moderator command. Duration is in seconds and is ignored
+// for kicks.
+type Moderation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Duration      int32                  `protobuf:"varint,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Moderation) Reset() {
+	*x = Moderation{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Moderation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Moderation) ProtoMessage() {}
+
+func (x *Moderation) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Moderation.ProtoReflect.Descriptor instead.
+func (*Moderation) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Moderation) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Moderation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Moderation) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+// ServerEvent is a frame sent by the server. Seq numbers the events of a
+// session from 1, and is 0 for events that are not replayed on resume.
+type ServerEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Seq   uint64                 `protobuf:"varint,20,opt,name=seq,proto3" json:"seq,omitempty"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ServerEvent_SpaceJoined
+	//	*ServerEvent_UserJoined
+	//	*ServerEvent_UserLeft
+	//	*ServerEvent_MovementRejected
+	//	*ServerEvent_StateDelta
+	//	*ServerEvent_Chat
+	//	*ServerEvent_Emote
+	//	*ServerEvent_DirectMessage
+	//	*ServerEvent_Kicked
+	//	*ServerEvent_Muted
+	//	*ServerEvent_Unmuted
+	//	*ServerEvent_Banned
+	//	*ServerEvent_QueuePosition
+	//	*ServerEvent_ZoneEntered
+	//	*ServerEvent_ZoneLeft
+	//	*ServerEvent_PortalTransition
+	//	*ServerEvent_Path
+	//	*ServerEvent_ElementsChanged
+	//	*ServerEvent_Error
+	//	*ServerEvent_Resumed
+	//	*ServerEvent_Redirect
+	//	*ServerEvent_UserIdle
+	//	*ServerEvent_UserActive
+	//	*ServerEvent_Latency
+	//	*ServerEvent_RateLimited
+	//	*ServerEvent_Movement
+	//	*ServerEvent_EnterView
+	//	*ServerEvent_LeaveView
+	Payload       isServerEvent_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerEvent) Reset() {
+	*x = ServerEvent{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerEvent) ProtoMessage() {}
+
+func (x *ServerEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerEvent.ProtoReflect.Descriptor instead.
+func (*ServerEvent) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ServerEvent) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ServerEvent) GetPayload() isServerEvent_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetSpaceJoined() *SpaceJoined {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_SpaceJoined); ok {
+			return x.SpaceJoined
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetUserJoined() *UserPosition {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_UserJoined); ok {
+			return x.UserJoined
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetUserLeft() *UserLeft {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_UserLeft); ok {
+			return x.UserLeft
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetMovementRejected() *MovementRejected {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_MovementRejected); ok {
+			return x.MovementRejected
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetStateDelta() *StateDelta {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_StateDelta); ok {
+			return x.StateDelta
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetChat() *UserChat {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_Chat); ok {
+			return x.Chat
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetEmote() *UserEmote {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_Emote); ok {
+			return x.Emote
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetDirectMessage() *UserChat {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_DirectMessage); ok {
+			return x.DirectMessage
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetKicked() *Sanction {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_Kicked); ok {
+			return x.Kicked
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetMuted() *Sanction {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_Muted); ok {
+			return x.Muted
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetUnmuted() *Sanction {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_Unmuted); ok {
+			return x.Unmuted
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetBanned() *Sanction {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_Banned); ok {
+			return x.Banned
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetQueuePosition() *QueuePosition {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_QueuePosition); ok {
+			return x.QueuePosition
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetZoneEntered() *ZoneEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_ZoneEntered); ok {
+			return x.ZoneEntered
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetZoneLeft() *ZoneEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_ZoneLeft); ok {
+			return x.ZoneLeft
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetPortalTransition() *PortalTransition {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_PortalTransition); ok {
+			return x.PortalTransition
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetPath() *Path {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_Path); ok {
+			return x.Path
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetElementsChanged() *ElementsChanged {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_ElementsChanged); ok {
+			return x.ElementsChanged
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetResumed() *Resumed {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_Resumed); ok {
+			return x.Resumed
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetRedirect() *Redirect {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_Redirect); ok {
+			return x.Redirect
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetUserIdle() *UserActivity {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_UserIdle); ok {
+			return x.UserIdle
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetUserActive() *UserActivity {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_UserActive); ok {
+			return x.UserActive
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetLatency() *Latency {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_Latency); ok {
+			return x.Latency
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetRateLimited() *RateLimited {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_RateLimited); ok {
+			return x.RateLimited
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetMovement() *UserPosition {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_Movement); ok {
+			return x.Movement
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetEnterView() *UserPosition {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_EnterView); ok {
+			return x.EnterView
+		}
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetLeaveView() *UserLeft {
+	if x != nil {
+		if x, ok := x.Payload.(*ServerEvent_LeaveView); ok {
+			return x.LeaveView
+		}
+	}
+	return nil
+}
+
+type isServerEvent_Payload interface {
+	isServerEvent_Payload()
+}
+
+type ServerEvent_SpaceJoined struct {
+	SpaceJoined *SpaceJoined `protobuf:"bytes,1,opt,name=space_joined,json=spaceJoined,proto3,oneof"`
+}
+
+type ServerEvent_UserJoined struct {
+	UserJoined *UserPosition `protobuf:"bytes,2,opt,name=user_joined,json=userJoined,proto3,oneof"`
+}
+
+type ServerEvent_UserLeft struct {
+	UserLeft *UserLeft `protobuf:"bytes,3,opt,name=user_left,json=userLeft,proto3,oneof"`
+}
+
+type ServerEvent_MovementRejected struct {
+	MovementRejected *MovementRejected `protobuf:"bytes,4,opt,name=movement_rejected,json=movementRejected,proto3,oneof"`
+}
+
+type ServerEvent_StateDelta struct {
+	StateDelta *StateDelta `protobuf:"bytes,5,opt,name=state_delta,json=stateDelta,proto3,oneof"`
+}
+
+type ServerEvent_Chat struct {
+	Chat *UserChat `protobuf:"bytes,6,opt,name=chat,proto3,oneof"`
+}
+
+type ServerEvent_Emote struct {
+	Emote *UserEmote `protobuf:"bytes,7,opt,name=emote,proto3,oneof"`
+}
+
+type ServerEvent_DirectMessage struct {
+	DirectMessage *UserChat `protobuf:"bytes,8,opt,name=direct_message,json=directMessage,proto3,oneof"`
+}
+
+type ServerEvent_Kicked struct {
+	Kicked *Sanction `protobuf:"bytes,9,opt,name=kicked,proto3,oneof"`
+}
+
+type ServerEvent_Muted struct {
+	Muted *Sanction `protobuf:"bytes,10,opt,name=muted,proto3,oneof"`
+}
+
+type ServerEvent_Unmuted struct {
+	Unmuted *Sanction `protobuf:"bytes,11,opt,name=unmuted,proto3,oneof"`
+}
+
+type ServerEvent_Banned struct {
+	Banned *Sanction `protobuf:"bytes,12,opt,name=banned,proto3,oneof"`
+}
+
+type ServerEvent_QueuePosition struct {
+	QueuePosition *QueuePosition `protobuf:"bytes,13,opt,name=queue_position,json=queuePosition,proto3,oneof"`
+}
+
+type ServerEvent_ZoneEntered struct {
+	ZoneEntered *ZoneEvent `protobuf:"bytes,14,opt,name=zone_entered,json=zoneEntered,proto3,oneof"`
+}
+
+type ServerEvent_ZoneLeft struct {
+	ZoneLeft *ZoneEvent `protobuf:"bytes,15,opt,name=zone_left,json=zoneLeft,proto3,oneof"`
+}
+
+type ServerEvent_PortalTransition struct {
+	PortalTransition *PortalTransition `protobuf:"bytes,16,opt,name=portal_transition,json=portalTransition,proto3,oneof"`
+}
+
+type ServerEvent_Path struct {
+	Path *Path `protobuf:"bytes,17,opt,name=path,proto3,oneof"`
+}
+
+type ServerEvent_ElementsChanged struct {
+	ElementsChanged *ElementsChanged `protobuf:"bytes,18,opt,name=elements_changed,json=elementsChanged,proto3,oneof"`
+}
+
+type ServerEvent_Error struct {
+	Error *Error `protobuf:"bytes,19,opt,name=error,proto3,oneof"`
+}
+
+type ServerEvent_Resumed struct {
+	Resumed *Resumed `protobuf:"bytes,21,opt,name=resumed,proto3,oneof"`
+}
+
+type ServerEvent_Redirect struct {
+	Redirect *Redirect `protobuf:"bytes,22,opt,name=redirect,proto3,oneof"`
+}
+
+type ServerEvent_UserIdle struct {
+	UserIdle *UserActivity `protobuf:"bytes,23,opt,name=user_idle,json=userIdle,proto3,oneof"`
+}
+
+type ServerEvent_UserActive struct {
+	UserActive *UserActivity `protobuf:"bytes,24,opt,name=user_active,json=userActive,proto3,oneof"`
+}
+
+type ServerEvent_Latency struct {
+	Latency *Latency `protobuf:"bytes,25,opt,name=latency,proto3,oneof"`
+}
+
+type ServerEvent_RateLimited struct {
+	RateLimited *RateLimited `protobuf:"bytes,26,opt,name=rate_limited,json=rateLimited,proto3,oneof"`
+}
+
+type ServerEvent_Movement struct {
+	Movement *UserPosition `protobuf:"bytes,27,opt,name=movement,proto3,oneof"`
+}
+
+type ServerEvent_EnterView struct {
+	EnterView *UserPosition `protobuf:"bytes,28,opt,name=enter_view,json=enterView,proto3,oneof"`
+}
+
+type ServerEvent_LeaveView struct {
+	LeaveView *UserLeft `protobuf:"bytes,29,opt,name=leave_view,json=leaveView,proto3,oneof"`
+}
+
+func (*ServerEvent_SpaceJoined) isServerEvent_Payload() {}
+
+func (*ServerEvent_UserJoined) isServerEvent_Payload() {}
+
+func (*ServerEvent_UserLeft) isServerEvent_Payload() {}
+
+func (*ServerEvent_MovementRejected) isServerEvent_Payload() {}
+
+func (*ServerEvent_StateDelta) isServerEvent_Payload() {}
+
+func (*ServerEvent_Chat) isServerEvent_Payload() {}
+
+func (*ServerEvent_Emote) isServerEvent_Payload() {}
+
+func (*ServerEvent_DirectMessage) isServerEvent_Payload() {}
+
+func (*ServerEvent_Kicked) isServerEvent_Payload() {}
+
+func (*ServerEvent_Muted) isServerEvent_Payload() {}
+
+func (*ServerEvent_Unmuted) isServerEvent_Payload() {}
+
+func (*ServerEvent_Banned) isServerEvent_Payload() {}
+
+func (*ServerEvent_QueuePosition) isServerEvent_Payload() {}
+
+func (*ServerEvent_ZoneEntered) isServerEvent_Payload() {}
+
+func (*ServerEvent_ZoneLeft) isServerEvent_Payload() {}
+
+func (*ServerEvent_PortalTransition) isServerEvent_Payload() {}
+
+func (*ServerEvent_Path) isServerEvent_Payload() {}
+
+func (*ServerEvent_ElementsChanged) isServerEvent_Payload() {}
+
+func (*ServerEvent_Error) isServerEvent_Payload() {}
+
+func (*ServerEvent_Resumed) isServerEvent_Payload() {}
+
+func (*ServerEvent_Redirect) isServerEvent_Payload() {}
+
+func (*ServerEvent_UserIdle) isServerEvent_Payload() {}
+
+func (*ServerEvent_UserActive) isServerEvent_Payload() {}
+
+func (*ServerEvent_Latency) isServerEvent_Payload() {}
+
+func (*ServerEvent_RateLimited) isServerEvent_Payload() {}
+
+func (*ServerEvent_Movement) isServerEvent_Payload() {}
+
+func (*ServerEvent_EnterView) isServerEvent_Payload() {}
+
+func (*ServerEvent_LeaveView) isServerEvent_Payload() {}
+
+type SpaceJoined struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Spawn         *Point                 `protobuf:"bytes,2,opt,name=spawn,proto3" json:"spawn,omitempty"`
+	Users         []*UserPosition        `protobuf:"bytes,3,rep,name=users,proto3" json:"users,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,4,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	Idle          []string               `protobuf:"bytes,5,rep,name=idle,proto3" json:"idle,omitempty"`
+	SpawnMoved    bool                   `protobuf:"varint,6,opt,name=spawn_moved,json=spawnMoved,proto3" json:"spawn_moved,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceJoined) Reset() {
+	*x = SpaceJoined{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceJoined) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceJoined) ProtoMessage() {}
+
+func (x *SpaceJoined) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceJoined.ProtoReflect.Descriptor instead.
+func (*SpaceJoined) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SpaceJoined) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SpaceJoined) GetSpawn() *Point {
+	if x != nil {
+		return x.Spawn
+	}
+	return nil
+}
+
+func (x *SpaceJoined) GetUsers() []*UserPosition {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *SpaceJoined) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *SpaceJoined) GetIdle() []string {
+	if x != nil {
+		return x.Idle
+	}
+	return nil
+}
+
+func (x *SpaceJoined) GetSpawnMoved() bool {
+	if x != nil {
+		return x.SpawnMoved
+	}
+	return false
+}
+
+// Resumed is sent before the replayed events, the last of which has seq
+// last_seq.
+type Resumed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId       string                 `protobuf:"bytes,1,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	Position      *Point                 `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	LastSeq       uint32                 `protobuf:"varint,4,opt,name=last_seq,json=lastSeq,proto3" json:"last_seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Resumed) Reset() {
+	*x = Resumed{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resumed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resumed) ProtoMessage() {}
+
+func (x *Resumed) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resumed.ProtoReflect.Descriptor instead.
+func (*Resumed) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Resumed) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *Resumed) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *Resumed) GetPosition() *Point {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *Resumed) GetLastSeq() uint32 {
+	if x != nil {
+		return x.LastSeq
+	}
+	return 0
+}
+
+// Redirect sends a client to the node serving space_id, to join it there
+// with spawn_ticket.
+type Redirect struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId       string                 `protobuf:"bytes,1,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	SpawnTicket   string                 `protobuf:"bytes,4,opt,name=spawn_ticket,json=spawnTicket,proto3" json:"spawn_ticket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Redirect) Reset() {
+	*x = Redirect{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Redirect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Redirect) ProtoMessage() {}
+
+func (x *Redirect) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Redirect.ProtoReflect.Descriptor instead.
+func (*Redirect) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Redirect) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *Redirect) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Redirect) GetSpawnTicket() string {
+	if x != nil {
+		return x.SpawnTicket
+	}
+	return ""
+}
+
+// UserActivity says a user went idle or came back.
+type UserActivity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserActivity) Reset() {
+	*x = UserActivity{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserActivity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserActivity) ProtoMessage() {}
+
+func (x *UserActivity) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserActivity.ProtoReflect.Descriptor instead.
+func (*UserActivity) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UserActivity) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// Latency is the client's last measured round trip, in milliseconds.
+type Latency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rtt           uint32                 `protobuf:"varint,1,opt,name=rtt,proto3" json:"rtt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Latency) Reset() {
+	*x = Latency{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Latency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Latency) ProtoMessage() {}
+
+func (x *Latency) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Latency.ProtoReflect.Descriptor instead.
+func (*Latency) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Latency) GetRtt() uint32 {
+	if x != nil {
+		return x.Rtt
+	}
+	return 0
+}
+
+// RateLimited says messages of a type are being dropped for coming too fast,
+// and how many milliseconds until the next one goes through.
+type RateLimited struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	RetryAfter    uint32                 `protobuf:"varint,2,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLimited) Reset() {
+	*x = RateLimited{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimited) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimited) ProtoMessage() {}
+
+func (x *RateLimited) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimited.ProtoReflect.Descriptor instead.
+func (*RateLimited) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RateLimited) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RateLimited) GetRetryAfter() uint32 {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return 0
+}
+
+type UserLeft struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserLeft) Reset() {
+	*x = UserLeft{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserLeft) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserLeft) ProtoMessage() {}
+
+func (x *UserLeft) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserLeft.ProtoReflect.Descriptor instead.
+func (*UserLeft) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UserLeft) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// MovementRejected puts a client back where the server has it.
+type MovementRejected struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int32                  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MovementRejected) Reset() {
+	*x = MovementRejected{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MovementRejected) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MovementRejected) ProtoMessage() {}
+
+func (x *MovementRejected) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MovementRejected.ProtoReflect.Descriptor instead.
+func (*MovementRejected) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *MovementRejected) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *MovementRejected) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *MovementRejected) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// StateDelta is what changed in a client's view during a tick. Ticks count
+// up from the room's creation; 32 bits last years at any tick rate the
+// server allows, and keep the number a number in the JSON mapping.
+type StateDelta struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tick          uint32                 `protobuf:"varint,1,opt,name=tick,proto3" json:"tick,omitempty"`
+	Moved         []*UserPosition        `protobuf:"bytes,2,rep,name=moved,proto3" json:"moved,omitempty"`
+	Entered       []*UserPosition        `protobuf:"bytes,3,rep,name=entered,proto3" json:"entered,omitempty"`
+	Left          []string               `protobuf:"bytes,4,rep,name=left,proto3" json:"left,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StateDelta) Reset() {
+	*x = StateDelta{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StateDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateDelta) ProtoMessage() {}
+
+func (x *StateDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateDelta.ProtoReflect.Descriptor instead.
+func (*StateDelta) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StateDelta) GetTick() uint32 {
+	if x != nil {
+		return x.Tick
+	}
+	return 0
+}
+
+func (x *StateDelta) GetMoved() []*UserPosition {
+	if x != nil {
+		return x.Moved
+	}
+	return nil
+}
+
+func (x *StateDelta) GetEntered() []*UserPosition {
+	if x != nil {
+		return x.Entered
+	}
+	return nil
+}
+
+func (x *StateDelta) GetLeft() []string {
+	if x != nil {
+		return x.Left
+	}
+	return nil
+}
+
+type UserChat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserChat) Reset() {
+	*x = UserChat{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserChat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserChat) ProtoMessage() {}
+
+func (x *UserChat) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserChat.ProtoReflect.Descriptor instead.
+func (*UserChat) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UserChat) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserChat) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type UserEmote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Emote         string                 `protobuf:"bytes,2,opt,name=emote,proto3" json:"emote,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserEmote) Reset() {
+	*x = UserEmote{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserEmote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserEmote) ProtoMessage() {}
+
+func (x *UserEmote) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserEmote.ProtoReflect.Descriptor instead.
+func (*UserEmote) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UserEmote) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserEmote) GetEmote() string {
+	if x != nil {
+		return x.Emote
+	}
+	return ""
+}
+
+// Sanction tells a client why it was kicked, muted or banned. Until is an
+// RFC 3339 time, empty for kicks and permanent bans.
+type Sanction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId       string                 `protobuf:"bytes,1,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Until         string                 `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sanction) Reset() {
+	*x = Sanction{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sanction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sanction) ProtoMessage() {}
+
+func (x *Sanction) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sanction.ProtoReflect.Descriptor instead.
+func (*Sanction) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Sanction) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *Sanction) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Sanction) GetUntil() string {
+	if x != nil {
+		return x.Until
+	}
+	return ""
+}
+
+// QueuePosition is sent while a client waits for a full space. Position is
+// 1-based.
+type QueuePosition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId       string                 `protobuf:"bytes,1,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	Position      int32                  `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	Size          int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueuePosition) Reset() {
+	*x = QueuePosition{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueuePosition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueuePosition) ProtoMessage() {}
+
+func (x *QueuePosition) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueuePosition.ProtoReflect.Descriptor instead.
+func (*QueuePosition) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *QueuePosition) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *QueuePosition) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *QueuePosition) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type ZoneEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ZoneId        string                 `protobuf:"bytes,2,opt,name=zone_id,json=zoneId,proto3" json:"zone_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	ChatScope     string                 `protobuf:"bytes,4,opt,name=chat_scope,json=chatScope,proto3" json:"chat_scope,omitempty"`
+	Audio         string                 `protobuf:"bytes,5,opt,name=audio,proto3" json:"audio,omitempty"`
+	Properties    *structpb.Struct       `protobuf:"bytes,6,opt,name=properties,proto3" json:"properties,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZoneEvent) Reset() {
+	*x = ZoneEvent{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZoneEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZoneEvent) ProtoMessage() {}
+
+func (x *ZoneEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZoneEvent.ProtoReflect.Descriptor instead.
+func (*ZoneEvent) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ZoneEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ZoneEvent) GetZoneId() string {
+	if x != nil {
+		return x.ZoneId
+	}
+	return ""
+}
+
+func (x *ZoneEvent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ZoneEvent) GetChatScope() string {
+	if x != nil {
+		return x.ChatScope
+	}
+	return ""
+}
+
+func (x *ZoneEvent) GetAudio() string {
+	if x != nil {
+		return x.Audio
+	}
+	return ""
+}
+
+func (x *ZoneEvent) GetProperties() *structpb.Struct {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type PortalTransition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ElementId     string                 `protobuf:"bytes,1,opt,name=element_id,json=elementId,proto3" json:"element_id,omitempty"`
+	FromSpaceId   string                 `protobuf:"bytes,2,opt,name=from_space_id,json=fromSpaceId,proto3" json:"from_space_id,omitempty"`
+	SpaceId       string                 `protobuf:"bytes,3,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	Spawn         *Point                 `protobuf:"bytes,4,opt,name=spawn,proto3" json:"spawn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PortalTransition) Reset() {
+	*x = PortalTransition{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortalTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortalTransition) ProtoMessage() {}
+
+func (x *PortalTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortalTransition.ProtoReflect.Descriptor instead.
+func (*PortalTransition) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PortalTransition) GetElementId() string {
+	if x != nil {
+		return x.ElementId
+	}
+	return ""
+}
+
+func (x *PortalTransition) GetFromSpaceId() string {
+	if x != nil {
+		return x.FromSpaceId
+	}
+	return ""
+}
+
+func (x *PortalTransition) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *PortalTransition) GetSpawn() *Point {
+	if x != nil {
+		return x.Spawn
+	}
+	return nil
+}
+
+// Path is the route a move-to will walk, excluding the start.
+type Path struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Steps         []*Point               `protobuf:"bytes,1,rep,name=steps,proto3" json:"steps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Path) Reset() {
+	*x = Path{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Path) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Path) ProtoMessage() {}
+
+func (x *Path) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Path.ProtoReflect.Descriptor instead.
+func (*Path) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Path) GetSteps() []*Point {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+type LayoutElement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ElementId     string                 `protobuf:"bytes,2,opt,name=element_id,json=elementId,proto3" json:"element_id,omitempty"`
+	X             int32                  `protobuf:"varint,3,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,4,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LayoutElement) Reset() {
+	*x = LayoutElement{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LayoutElement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LayoutElement) ProtoMessage() {}
+
+func (x *LayoutElement) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LayoutElement.ProtoReflect.Descriptor instead.
+func (*LayoutElement) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *LayoutElement) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LayoutElement) GetElementId() string {
+	if x != nil {
+		return x.ElementId
+	}
+	return ""
+}
+
+func (x *LayoutElement) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *LayoutElement) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type ElementsChanged struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId       string                 `protobuf:"bytes,1,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Added         []*LayoutElement       `protobuf:"bytes,3,rep,name=added,proto3" json:"added,omitempty"`
+	Removed       []*LayoutElement       `protobuf:"bytes,4,rep,name=removed,proto3" json:"removed,omitempty"`
+	Moved         []*LayoutElement       `protobuf:"bytes,5,rep,name=moved,proto3" json:"moved,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ElementsChanged) Reset() {
+	*x = ElementsChanged{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ElementsChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ElementsChanged) ProtoMessage() {}
+
+func (x *ElementsChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ElementsChanged.ProtoReflect.Descriptor instead.
+func (*ElementsChanged) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ElementsChanged) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *ElementsChanged) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ElementsChanged) GetAdded() []*LayoutElement {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *ElementsChanged) GetRemoved() []*LayoutElement {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+func (x *ElementsChanged) GetMoved() []*LayoutElement {
+	if x != nil {
+		return x.Moved
+	}
+	return nil
+}
+
+type Error struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_realtime_v1_realtime_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_realtime_v1_realtime_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_realtime_v1_realtime_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_realtime_v1_realtime_proto protoreflect.FileDescriptor
+
+const file_realtime_v1_realtime_proto_rawDesc = "" +
+	"\n" +
+	"\x1arealtime/v1/realtime.proto\x12\x15metaverse.realtime.v1\x1a\x1cgoogle/protobuf/struct.proto\"#\n" +
+	"\x05Point\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x05R\x01y\"C\n" +
+	"\fUserPosition\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\f\n" +
+	"\x01x\x18\x02 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x03 \x01(\x05R\x01y\"\xd2\x04\n" +
+	"\rClientMessage\x121\n" +
+	"\x04join\x18\x01 \x01(\v2\x1b.metaverse.realtime.v1.JoinH\x00R\x04join\x121\n" +
+	"\x04move\x18\x02 \x01(\v2\x1b.metaverse.realtime.v1.MoveH\x00R\x04move\x126\n" +
+	"\amove_to\x18\x03 \x01(\v2\x1b.metaverse.realtime.v1.MoveH\x00R\x06moveTo\x121\n" +
+	"\x04chat\x18\x04 \x01(\v2\x1b.metaverse.realtime.v1.ChatH\x00R\x04chat\x124\n" +
+	"\x05emote\x18\x05 \x01(\v2\x1c.metaverse.realtime.v1.EmoteH\x00R\x05emote\x12M\n" +
+	"\x0edirect_message\x18\x06 \x01(\v2$.metaverse.realtime.v1.DirectMessageH\x00R\rdirectMessage\x127\n" +
+	"\x04kick\x18\a \x01(\v2!.metaverse.realtime.v1.ModerationH\x00R\x04kick\x127\n" +
+	"\x04mute\x18\b \x01(\v2!.metaverse.realtime.v1.ModerationH\x00R\x04mute\x125\n" +
+	"\x03ban\x18\t \x01(\v2!.metaverse.realtime.v1.ModerationH\x00R\x03ban\x127\n" +
+	"\x06resume\x18\n" +
+	" \x01(\v2\x1d.metaverse.realtime.v1.ResumeH\x00R\x06resumeB\t\n" +
+	"\apayload\"\xa6\x01\n" +
+	"\x04Join\x12\x19\n" +
+	"\bspace_id\x18\x01 \x01(\tR\aspaceId\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12!\n" +
+	"\fstate_deltas\x18\x05 \x01(\bR\vstateDeltas\x12!\n" +
+	"\fspawn_ticket\x18\x06 \x01(\tR\vspawnTicketJ\x04\b\x04\x10\x05R\x05spawn\"9\n" +
+	"\x06Resume\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x19\n" +
+	"\blast_seq\x18\x02 \x01(\rR\alastSeq\"\"\n" +
+	"\x04Move\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x05R\x01y\" \n" +
+	"\x04Chat\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x1d\n" +
+	"\x05Emote\x12\x14\n" +
+	"\x05emote\x18\x01 \x01(\tR\x05emote\"B\n" +
+	"\rDirectMessage\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"Y\n" +
+	"\n" +
+	"Moderation\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1a\n" +
+	"\bduration\x18\x03 \x01(\x05R\bduration\"\x84\x0f\n" +
+	"\vServerEvent\x12\x10\n" +
+	"\x03seq\x18\x14 \x01(\x04R\x03seq\x12G\n" +
+	"\fspace_joined\x18\x01 \x01(\v2\".metaverse.realtime.v1.SpaceJoinedH\x00R\vspaceJoined\x12F\n" +
+	"\vuser_joined\x18\x02 \x01(\v2#.metaverse.realtime.v1.UserPositionH\x00R\n" +
+	"userJoined\x12>\n" +
+	"\tuser_left\x18\x03 \x01(\v2\x1f.metaverse.realtime.v1.UserLeftH\x00R\buserLeft\x12V\n" +
+	"\x11movement_rejected\x18\x04 \x01(\v2'.metaverse.realtime.v1.MovementRejectedH\x00R\x10movementRejected\x12D\n" +
+	"\vstate_delta\x18\x05 \x01(\v2!.metaverse.realtime.v1.StateDeltaH\x00R\n" +
+	"stateDelta\x125\n" +
+	"\x04chat\x18\x06 \x01(\v2\x1f.metaverse.realtime.v1.UserChatH\x00R\x04chat\x128\n" +
+	"\x05emote\x18\a \x01(\v2 .metaverse.realtime.v1.UserEmoteH\x00R\x05emote\x12H\n" +
+	"\x0edirect_message\x18\b \x01(\v2\x1f.metaverse.realtime.v1.UserChatH\x00R\rdirectMessage\x129\n" +
+	"\x06kicked\x18\t \x01(\v2\x1f.metaverse.realtime.v1.SanctionH\x00R\x06kicked\x127\n" +
+	"\x05muted\x18\n" +
+	" \x01(\v2\x1f.metaverse.realtime.v1.SanctionH\x00R\x05muted\x12;\n" +
+	"\aunmuted\x18\v \x01(\v2\x1f.metaverse.realtime.v1.SanctionH\x00R\aunmuted\x129\n" +
+	"\x06banned\x18\f \x01(\v2\x1f.metaverse.realtime.v1.SanctionH\x00R\x06banned\x12M\n" +
+	"\x0equeue_position\x18\r \x01(\v2$.metaverse.realtime.v1.QueuePositionH\x00R\rqueuePosition\x12E\n" +
+	"\fzone_entered\x18\x0e \x01(\v2 .metaverse.realtime.v1.ZoneEventH\x00R\vzoneEntered\x12?\n" +
+	"\tzone_left\x18\x0f \x01(\v2 .metaverse.realtime.v1.ZoneEventH\x00R\bzoneLeft\x12V\n" +
+	"\x11portal_transition\x18\x10 \x01(\v2'.metaverse.realtime.v1.PortalTransitionH\x00R\x10portalTransition\x121\n" +
+	"\x04path\x18\x11 \x01(\v2\x1b.metaverse.realtime.v1.PathH\x00R\x04path\x12S\n" +
+	"\x10elements_changed\x18\x12 \x01(\v2&.metaverse.realtime.v1.ElementsChangedH\x00R\x0felementsChanged\x124\n" +
+	"\x05error\x18\x13 \x01(\v2\x1c.metaverse.realtime.v1.ErrorH\x00R\x05error\x12:\n" +
+	"\aresumed\x18\x15 \x01(\v2\x1e.metaverse.realtime.v1.ResumedH\x00R\aresumed\x12=\n" +
+	"\bredirect\x18\x16 \x01(\v2\x1f.metaverse.realtime.v1.RedirectH\x00R\bredirect\x12B\n" +
+	"\tuser_idle\x18\x17 \x01(\v2#.metaverse.realtime.v1.UserActivityH\x00R\buserIdle\x12F\n" +
+	"\vuser_active\x18\x18 \x01(\v2#.metaverse.realtime.v1.UserActivityH\x00R\n" +
+	"userActive\x12:\n" +
+	"\alatency\x18\x19 \x01(\v2\x1e.metaverse.realtime.v1.LatencyH\x00R\alatency\x12G\n" +
+	"\frate_limited\x18\x1a \x01(\v2\".metaverse.realtime.v1.RateLimitedH\x00R\vrateLimited\x12A\n" +
+	"\bmovement\x18\x1b \x01(\v2#.metaverse.realtime.v1.UserPositionH\x00R\bmovement\x12D\n" +
+	"\n" +
+	"enter_view\x18\x1c \x01(\v2#.metaverse.realtime.v1.UserPositionH\x00R\tenterView\x12@\n" +
+	"\n" +
+	"leave_view\x18\x1d \x01(\v2\x1f.metaverse.realtime.v1.UserLeftH\x00R\tleaveViewB\t\n" +
+	"\apayload\"\xed\x01\n" +
+	"\vSpaceJoined\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x122\n" +
+	"\x05spawn\x18\x02 \x01(\v2\x1c.metaverse.realtime.v1.PointR\x05spawn\x129\n" +
+	"\x05users\x18\x03 \x03(\v2#.metaverse.realtime.v1.UserPositionR\x05users\x12!\n" +
+	"\fresume_token\x18\x04 \x01(\tR\vresumeToken\x12\x12\n" +
+	"\x04idle\x18\x05 \x03(\tR\x04idle\x12\x1f\n" +
+	"\vspawn_moved\x18\x06 \x01(\bR\n" +
+	"spawnMoved\"\x9c\x01\n" +
+	"\aResumed\x12\x19\n" +
+	"\bspace_id\x18\x01 \x01(\tR\aspaceId\x12!\n" +
+	"\fresume_token\x18\x02 \x01(\tR\vresumeToken\x128\n" +
+	"\bposition\x18\x03 \x01(\v2\x1c.metaverse.realtime.v1.PointR\bposition\x12\x19\n" +
+	"\blast_seq\x18\x04 \x01(\rR\alastSeq\"g\n" +
+	"\bRedirect\x12\x19\n" +
+	"\bspace_id\x18\x01 \x01(\tR\aspaceId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12!\n" +
+	"\fspawn_ticket\x18\x04 \x01(\tR\vspawnTicketJ\x04\b\x03\x10\x04R\x05spawn\"'\n" +
+	"\fUserActivity\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x1b\n" +
+	"\aLatency\x12\x10\n" +
+	"\x03rtt\x18\x01 \x01(\rR\x03rtt\"B\n" +
+	"\vRateLimited\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1f\n" +
+	"\vretry_after\x18\x02 \x01(\rR\n" +
+	"retryAfter\"#\n" +
+	"\bUserLeft\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"F\n" +
+	"\x10MovementRejected\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x05R\x01y\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xae\x01\n" +
+	"\n" +
+	"StateDelta\x12\x12\n" +
+	"\x04tick\x18\x01 \x01(\rR\x04tick\x129\n" +
+	"\x05moved\x18\x02 \x03(\v2#.metaverse.realtime.v1.UserPositionR\x05moved\x12=\n" +
+	"\aentered\x18\x03 \x03(\v2#.metaverse.realtime.v1.UserPositionR\aentered\x12\x12\n" +
+	"\x04left\x18\x04 \x03(\tR\x04left\"=\n" +
+	"\bUserChat\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\":\n" +
+	"\tUserEmote\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05emote\x18\x02 \x01(\tR\x05emote\"S\n" +
+	"\bSanction\x12\x19\n" +
+	"\bspace_id\x18\x01 \x01(\tR\aspaceId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05until\x18\x03 \x01(\tR\x05until\"Z\n" +
+	"\rQueuePosition\x12\x19\n" +
+	"\bspace_id\x18\x01 \x01(\tR\aspaceId\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\x05R\bposition\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\"\xbf\x01\n" +
+	"\tZoneEvent\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\azone_id\x18\x02 \x01(\tR\x06zoneId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"chat_scope\x18\x04 \x01(\tR\tchatScope\x12\x14\n" +
+	"\x05audio\x18\x05 \x01(\tR\x05audio\x127\n" +
+	"\n" +
+	"properties\x18\x06 \x01(\v2\x17.google.protobuf.StructR\n" +
+	"properties\"\xa4\x01\n" +
+	"\x10PortalTransition\x12\x1d\n" +
+	"\n" +
+	"element_id\x18\x01 \x01(\tR\telementId\x12\"\n" +
+	"\rfrom_space_id\x18\x02 \x01(\tR\vfromSpaceId\x12\x19\n" +
+	"\bspace_id\x18\x03 \x01(\tR\aspaceId\x122\n" +
+	"\x05spawn\x18\x04 \x01(\v2\x1c.metaverse.realtime.v1.PointR\x05spawn\":\n" +
+	"\x04Path\x122\n" +
+	"\x05steps\x18\x01 \x03(\v2\x1c.metaverse.realtime.v1.PointR\x05steps\"Z\n" +
+	"\rLayoutElement\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"element_id\x18\x02 \x01(\tR\telementId\x12\f\n" +
+	"\x01x\x18\x03 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x04 \x01(\x05R\x01y\"\xfe\x01\n" +
+	"\x0fElementsChanged\x12\x19\n" +
+	"\bspace_id\x18\x01 \x01(\tR\aspaceId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12:\n" +
+	"\x05added\x18\x03 \x03(\v2$.metaverse.realtime.v1.LayoutElementR\x05added\x12>\n" +
+	"\aremoved\x18\x04 \x03(\v2$.metaverse.realtime.v1.LayoutElementR\aremoved\x12:\n" +
+	"\x05moved\x18\x05 \x03(\v2$.metaverse.realtime.v1.LayoutElementR\x05moved\"!\n" +
+	"\x05Error\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessageB2Z0github.com/vaxxnsh/metaverse/api/wire/realtimev1b\x06proto3"
+
+var (
+	file_realtime_v1_realtime_proto_rawDescOnce sync.Once
+	file_realtime_v1_realtime_proto_rawDescData []byte
+)
+
+func file_realtime_v1_realtime_proto_rawDescGZIP() []byte {
+	file_realtime_v1_realtime_proto_rawDescOnce.Do(func() {
+		file_realtime_v1_realtime_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_realtime_v1_realtime_proto_rawDesc), len(file_realtime_v1_realtime_proto_rawDesc)))
+	})
+	return file_realtime_v1_realtime_proto_rawDescData
+}
+
+var file_realtime_v1_realtime_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_realtime_v1_realtime_proto_goTypes = []any{
+	(*Point)(nil),            // 0: metaverse.realtime.v1.Point
+	(*UserPosition)(nil),     // 1: metaverse.realtime.v1.UserPosition
+	(*ClientMessage)(nil),    // 2: metaverse.realtime.v1.ClientMessage
+	(*Join)(nil),             // 3: metaverse.realtime.v1.Join
+	(*Resume)(nil),           // 4: metaverse.realtime.v1.Resume
+	(*Move)(nil),             // 5: metaverse.realtime.v1.Move
+	(*Chat)(nil),             // 6: metaverse.realtime.v1.Chat
+	(*Emote)(nil),            // 7: metaverse.realtime.v1.Emote
+	(*DirectMessage)(nil),    // 8: metaverse.realtime.v1.DirectMessage
+	(*Moderation)(nil),       // 9: metaverse.realtime.v1.Moderation
+	(*ServerEvent)(nil),      // 10: metaverse.realtime.v1.ServerEvent
+	(*SpaceJoined)(nil),      // 11: metaverse.realtime.v1.SpaceJoined
+	(*Resumed)(nil),          // 12: metaverse.realtime.v1.Resumed
+	(*Redirect)(nil),         // 13: metaverse.realtime.v1.Redirect
+	(*UserActivity)(nil),     // 14: metaverse.realtime.v1.UserActivity
+	(*Latency)(nil),          // 15: metaverse.realtime.v1.Latency
+	(*RateLimited)(nil),      // 16: metaverse.realtime.v1.RateLimited
+	(*UserLeft)(nil),         // 17: metaverse.realtime.v1.UserLeft
+	(*MovementRejected)(nil), // 18: metaverse.realtime.v1.MovementRejected
+	(*StateDelta)(nil),       // 19: metaverse.realtime.v1.StateDelta
+	(*UserChat)(nil),         // 20: metaverse.realtime.v1.UserChat
+	(*UserEmote)(nil),        // 21: metaverse.realtime.v1.UserEmote
+	(*Sanction)(nil),         // 22: metaverse.realtime.v1.Sanction
+	(*QueuePosition)(nil),    // 23: metaverse.realtime.v1.QueuePosition
+	(*ZoneEvent)(nil),        // 24: metaverse.realtime.v1.ZoneEvent
+	(*PortalTransition)(nil), // 25: metaverse.realtime.v1.PortalTransition
+	(*Path)(nil),             // 26: metaverse.realtime.v1.Path
+	(*LayoutElement)(nil),    // 27: metaverse.realtime.v1.LayoutElement
+	(*ElementsChanged)(nil),  // 28: metaverse.realtime.v1.ElementsChanged
+	(*Error)(nil),            // 29: metaverse.realtime.v1.Error
+	(*structpb.Struct)(nil),  // 30: google.protobuf.Struct
+}
+var file_realtime_v1_realtime_proto_depIdxs = []int32{
+	3,  // 0: metaverse.realtime.v1.ClientMessage.join:type_name -> metaverse.realtime.v1.Join
+	5,  // 1: metaverse.realtime.v1.ClientMessage.move:type_name -> metaverse.realtime.v1.Move
+	5,  // 2: metaverse.realtime.v1.ClientMessage.move_to:type_name -> metaverse.realtime.v1.Move
+	6,  // 3: metaverse.realtime.v1.ClientMessage.chat:type_name -> metaverse.realtime.v1.Chat
+	7,  // 4: metaverse.realtime.v1.ClientMessage.emote:type_name -> metaverse.realtime.v1.Emote
+	8,  // 5: metaverse.realtime.v1.ClientMessage.direct_message:type_name -> metaverse.realtime.v1.DirectMessage
+	9,  // 6: metaverse.realtime.v1.ClientMessage.kick:type_name -> metaverse.realtime.v1.Moderation
+	9,  // 7: metaverse.realtime.v1.ClientMessage.mute:type_name -> metaverse.realtime.v1.Moderation
+	9,  // 8: metaverse.realtime.v1.ClientMessage.ban:type_name -> metaverse.realtime.v1.Moderation
+	4,  // 9: metaverse.realtime.v1.ClientMessage.resume:type_name -> metaverse.realtime.v1.Resume
+	11, // 10: metaverse.realtime.v1.ServerEvent.space_joined:type_name -> metaverse.realtime.v1.SpaceJoined
+	1,  // 11: metaverse.realtime.v1.ServerEvent.user_joined:type_name -> metaverse.realtime.v1.UserPosition
+	17, // 12: metaverse.realtime.v1.ServerEvent.user_left:type_name -> metaverse.realtime.v1.UserLeft
+	18, // 13: metaverse.realtime.v1.ServerEvent.movement_rejected:type_name -> metaverse.realtime.v1.MovementRejected
+	19, // 14: metaverse.realtime.v1.ServerEvent.state_delta:type_name -> metaverse.realtime.v1.StateDelta
+	20, // 15: metaverse.realtime.v1.ServerEvent.chat:type_name -> metaverse.realtime.v1.UserChat
+	21, // 16: metaverse.realtime.v1.ServerEvent.emote:type_name -> metaverse.realtime.v1.UserEmote
+	20, // 17: metaverse.realtime.v1.ServerEvent.direct_message:type_name -> metaverse.realtime.v1.UserChat
+	22, // 18: metaverse.realtime.v1.ServerEvent.kicked:type_name -> metaverse.realtime.v1.Sanction
+	22, // 19: metaverse.realtime.v1.ServerEvent.muted:type_name -> metaverse.realtime.v1.Sanction
+	22, // 20: metaverse.realtime.v1.ServerEvent.unmuted:type_name -> metaverse.realtime.v1.Sanction
+	22, // 21: metaverse.realtime.v1.ServerEvent.banned:type_name -> metaverse.realtime.v1.Sanction
+	23, // 22: metaverse.realtime.v1.ServerEvent.queue_position:type_name -> metaverse.realtime.v1.QueuePosition
+	24, // 23: metaverse.realtime.v1.ServerEvent.zone_entered:type_name -> metaverse.realtime.v1.ZoneEvent
+	24, // 24: metaverse.realtime.v1.ServerEvent.zone_left:type_name -> metaverse.realtime.v1.ZoneEvent
+	25, // 25: metaverse.realtime.v1.ServerEvent.portal_transition:type_name -> metaverse.realtime.v1.PortalTransition
+	26, // 26: metaverse.realtime.v1.ServerEvent.path:type_name -> metaverse.realtime.v1.Path
+	28, // 27: metaverse.realtime.v1.ServerEvent.elements_changed:type_name -> metaverse.realtime.v1.ElementsChanged
+	29, // 28: metaverse.realtime.v1.ServerEvent.error:type_name -> metaverse.realtime.v1.Error
+	12, // 29: metaverse.realtime.v1.ServerEvent.resumed:type_name -> metaverse.realtime.v1.Resumed
+	13, // 30: metaverse.realtime.v1.ServerEvent.redirect:type_name -> metaverse.realtime.v1.Redirect
+	14, // 31: metaverse.realtime.v1.ServerEvent.user_idle:type_name -> metaverse.realtime.v1.UserActivity
+	14, // 32: metaverse.realtime.v1.ServerEvent.user_active:type_name -> metaverse.realtime.v1.UserActivity
+	15, // 33: metaverse.realtime.v1.ServerEvent.latency:type_name -> metaverse.realtime.v1.Latency
+	16, // 34: metaverse.realtime.v1.ServerEvent.rate_limited:type_name -> metaverse.realtime.v1.RateLimited
+	1,  // 35: metaverse.realtime.v1.ServerEvent.movement:type_name -> metaverse.realtime.v1.UserPosition
+	1,  // 36: metaverse.realtime.v1.ServerEvent.enter_view:type_name -> metaverse.realtime.v1.UserPosition
+	17, // 37: metaverse.realtime.v1.ServerEvent.leave_view:type_name -> metaverse.realtime.v1.UserLeft
+	0,  // 38: metaverse.realtime.v1.SpaceJoined.spawn:type_name -> metaverse.realtime.v1.Point
+	1,  // 39: metaverse.realtime.v1.SpaceJoined.users:type_name -> metaverse.realtime.v1.UserPosition
+	0,  // 40: metaverse.realtime.v1.Resumed.position:type_name -> metaverse.realtime.v1.Point
+	1,  // 41: metaverse.realtime.v1.StateDelta.moved:type_name -> metaverse.realtime.v1.UserPosition
+	1,  // 42: metaverse.realtime.v1.StateDelta.entered:type_name -> metaverse.realtime.v1.UserPosition
+	30, // 43: metaverse.realtime.v1.ZoneEvent.properties:type_name -> google.protobuf.Struct
+	0,  // 44: metaverse.realtime.v1.PortalTransition.spawn:type_name -> metaverse.realtime.v1.Point
+	0,  // 45: metaverse.realtime.v1.Path.steps:type_name -> metaverse.realtime.v1.Point
+	27, // 46: metaverse.realtime.v1.ElementsChanged.added:type_name -> metaverse.realtime.v1.LayoutElement
+	27, // 47: metaverse.realtime.v1.ElementsChanged.removed:type_name -> metaverse.realtime.v1.LayoutElement
+	27, // 48: metaverse.realtime.v1.ElementsChanged.moved:type_name -> metaverse.realtime.v1.LayoutElement
+	49, // [49:49] is the sub-list for method output_type
+	49, // [49:49] is the sub-list for method input_type
+	49, // [49:49] is the sub-list for extension type_name
+	49, // [49:49] is the sub-list for extension extendee
+	0,  // [0:49] is the sub-list for field type_name
+}
+
+func init() { file_realtime_v1_realtime_proto_init() }
+func file_realtime_v1_realtime_proto_init() {
+	if File_realtime_v1_realtime_proto != nil {
+		return
+	}
+	file_realtime_v1_realtime_proto_msgTypes[2].OneofWrappers = []any{
+		(*ClientMessage_Join)(nil),
+		(*ClientMessage_Move)(nil),
+		(*ClientMessage_MoveTo)(nil),
+		(*ClientMessage_Chat)(nil),
+		(*ClientMessage_Emote)(nil),
+		(*ClientMessage_DirectMessage)(nil),
+		(*ClientMessage_Kick)(nil),
+		(*ClientMessage_Mute)(nil),
+		(*ClientMessage_Ban)(nil),
+		(*ClientMessage_Resume)(nil),
+	}
+	file_realtime_v1_realtime_proto_msgTypes[10].OneofWrappers = []any{
+		(*ServerEvent_SpaceJoined)(nil),
+		(*ServerEvent_UserJoined)(nil),
+		(*ServerEvent_UserLeft)(nil),
+		(*ServerEvent_MovementRejected)(nil),
+		(*ServerEvent_StateDelta)(nil),
+		(*ServerEvent_Chat)(nil),
+		(*ServerEvent_Emote)(nil),
+		(*ServerEvent_DirectMessage)(nil),
+		(*ServerEvent_Kicked)(nil),
+		(*ServerEvent_Muted)(nil),
+		(*ServerEvent_Unmuted)(nil),
+		(*ServerEvent_Banned)(nil),
+		(*ServerEvent_QueuePosition)(nil),
+		(*ServerEvent_ZoneEntered)(nil),
+		(*ServerEvent_ZoneLeft)(nil),
+		(*ServerEvent_PortalTransition)(nil),
+		(*ServerEvent_Path)(nil),
+		(*ServerEvent_ElementsChanged)(nil),
+		(*ServerEvent_Error)(nil),
+		(*ServerEvent_Resumed)(nil),
+		(*ServerEvent_Redirect)(nil),
+		(*ServerEvent_UserIdle)(nil),
+		(*ServerEvent_UserActive)(nil),
+		(*ServerEvent_Latency)(nil),
+		(*ServerEvent_RateLimited)(nil),
+		(*ServerEvent_Movement)(nil),
+		(*ServerEvent_EnterView)(nil),
+		(*ServerEvent_LeaveView)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_realtime_v1_realtime_proto_rawDesc), len(file_realtime_v1_realtime_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   30,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_realtime_v1_realtime_proto_goTypes,
+		DependencyIndexes: file_realtime_v1_realtime_proto_depIdxs,
+		MessageInfos:      file_realtime_v1_realtime_proto_msgTypes,
+	}.Build()
+	File_realtime_v1_realtime_proto = out.File
+	file_realtime_v1_realtime_proto_goTypes = nil
+	file_realtime_v1_realtime_proto_depIdxs = nil
+}